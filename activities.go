@@ -0,0 +1,58 @@
+package stash
+
+import "net/http"
+
+type (
+	// PullRequestActivity is a single event in pull request history, which
+	// fields are set depends on Action.
+	PullRequestActivity struct {
+		ID          int            `json:"id"`
+		CreatedDate int64          `json:"createdDate"`
+		User        User           `json:"user"`
+		Action      ActivityAction `json:"action"`
+
+		// CommentAction and Comment are set for COMMENTED activities.
+		CommentAction CommentAction `json:"commentAction,omitempty"`
+		Comment       *Comment      `json:"comment,omitempty"`
+
+		// FromHash, ToHash, their previous values and commits Added and
+		// Removed are set for RESCOPED activities.
+		FromHash         string          `json:"fromHash,omitempty"`
+		PreviousFromHash string          `json:"previousFromHash,omitempty"`
+		ToHash           string          `json:"toHash,omitempty"`
+		PreviousToHash   string          `json:"previousToHash,omitempty"`
+		Added            *RescopeCommits `json:"added,omitempty"`
+		Removed          *RescopeCommits `json:"removed,omitempty"`
+	}
+
+	// RescopeCommits lists commits added to or removed from pull request,
+	// server returns only a few of them, Total is amount of all commits.
+	RescopeCommits struct {
+		Commits []Commit `json:"commits"`
+		Total   int      `json:"total"`
+	}
+)
+
+// GetPullRequestActivities returns history of the pull request, the latest
+// activities go first.
+func (client Client) GetPullRequestActivities(
+	projectKey, repositorySlug, identifier string,
+) ([]PullRequestActivity, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[PullRequestActivity], error) {
+			var response Paged[PullRequestActivity]
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/activities?start=%d&limit=%d",
+					projectKey, repositorySlug, identifier, start, limit,
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
@@ -0,0 +1,63 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetPullRequestActivities(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantPath := "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/42/activities"
+		if r.URL.Path != wantPath {
+			t.Fatalf("Want %s but found %s\n", wantPath, r.URL.Path)
+		}
+
+		switch r.URL.Query().Get("start") {
+		case "0":
+			fmt.Fprint(w, `{"isLastPage": false, "nextPageStart": 2, "values": [
+				{"id": 3, "action": "MERGED", "user": {"name": "alice"}},
+				{"id": 2, "action": "COMMENTED", "commentAction": "ADDED",
+				 "comment": {"id": 17, "version": 1, "text": "LGTM", "author": {"name": "bob"}}}
+			]}`)
+		case "2":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [
+				{"id": 1, "action": "RESCOPED", "fromHash": "def", "previousFromHash": "abc",
+				 "added": {"commits": [{"id": "def"}], "total": 1}}
+			]}`)
+		default:
+			t.Fatalf("Unexpected query %s\n", r.URL.RawQuery)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	activities, err := stashClient.Project("PRJ").Repo("widge").PullRequest(42).Activities()
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(activities) != 3 {
+		t.Fatalf("Want 3 activities but got %d\n", len(activities))
+	}
+
+	comment := activities[1]
+	if comment.Action != ActivityActionCommented ||
+		comment.CommentAction != CommentActionAdded ||
+		comment.Comment == nil ||
+		comment.Comment.Text != "LGTM" ||
+		comment.Comment.Author.Name != "bob" {
+		t.Fatalf("Want comment by bob but got %+v\n", comment)
+	}
+
+	rescope := activities[2]
+	if rescope.Action != ActivityActionRescoped ||
+		rescope.Added == nil ||
+		rescope.Added.Total != 1 ||
+		rescope.Added.Commits[0].ID != "def" {
+		t.Fatalf("Want rescope with added commit but got %+v\n", rescope)
+	}
+}
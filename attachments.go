@@ -0,0 +1,142 @@
+package stash
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/reconquest/karma-go"
+)
+
+// Attachment is a file uploaded to repository to be referenced from pull
+// request descriptions and comments.
+type Attachment struct {
+	ID    string `json:"id"`
+	URL   string `json:"url"`
+	Links struct {
+		Self       Link `json:"self"`
+		Attachment Link `json:"attachment"`
+	} `json:"links"`
+
+	// Name is a file name given to UploadAttachment, it's not returned by
+	// server.
+	Name string `json:"-"`
+}
+
+// UploadAttachment uploads contents of reader as a file with the given name,
+// use Markdown to reference it.
+func (client Client) UploadAttachment(
+	projectKey, repositorySlug, name string,
+	reader io.Reader,
+) (Attachment, error) {
+	path := pathf(
+		"/rest/api/1.0/projects/%s/repos/%s/attachments",
+		projectKey, repositorySlug,
+	)
+
+	err := client.validateRequest(path)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	// attachments are small reports and screenshots, so body is buffered to
+	// be resent on retries
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	part, err := writer.CreateFormFile("files", name)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	_, err = io.Copy(part, reader)
+	if err != nil {
+		return Attachment{}, karma.Format(err, "unable to read attachment")
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	request, err := http.NewRequestWithContext(
+		client.requestContext(),
+		"POST",
+		client.getFullURL(path),
+		&body,
+	)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	request.Header.Set("X-Atlassian-Token", "no-check")
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+
+	err = client.authenticate(request)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	var response struct {
+		Attachments []Attachment `json:"attachments"`
+	}
+
+	err = client.sendDecode(
+		request,
+		jsonDecoder(&response),
+		http.StatusOK,
+		http.StatusCreated,
+	)
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	if client.dryRun != nil {
+		return Attachment{Name: name}, nil
+	}
+
+	if len(response.Attachments) == 0 {
+		return Attachment{}, karma.
+			Describe("name", name).
+			Reason("no attachments in response")
+	}
+
+	attachment := response.Attachments[0]
+	attachment.Name = name
+
+	return attachment, nil
+}
+
+// Markdown returns a reference to the attachment to be embedded in pull
+// request description or comment. Images are displayed inline.
+func (attachment Attachment) Markdown() string {
+	link := attachment.Links.Attachment.HREF
+	if link == "" {
+		link = attachment.URL
+	}
+
+	name := strings.NewReplacer("[", `\[`, "]", `\]`).Replace(attachment.Name)
+
+	if isImage(attachment.Name) {
+		return fmt.Sprintf("![%s](%s)", name, link)
+	}
+
+	return fmt.Sprintf("[%s](%s)", name, link)
+}
+
+func isImage(name string) bool {
+	name = strings.ToLower(name)
+	for _, extension := range []string{
+		".png", ".jpg", ".jpeg", ".gif", ".svg", ".webp",
+	} {
+		if strings.HasSuffix(name, extension) {
+			return true
+		}
+	}
+
+	return false
+}
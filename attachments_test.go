@@ -0,0 +1,53 @@
+package stash
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestUploadAttachment(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantPath := "/rest/api/1.0/projects/PRJ/repos/widge/attachments"
+		if r.Method != "POST" || r.URL.Path != wantPath {
+			t.Fatalf("Want POST %s but found %s %s\n", wantPath, r.Method, r.URL.Path)
+		}
+
+		file, header, err := r.FormFile("files")
+		if err != nil {
+			t.Fatalf("Want multipart file but got %v\n", err)
+		}
+
+		data, _ := io.ReadAll(file)
+		if header.Filename != "report.png" || string(data) != "PNG" {
+			t.Fatalf("Want report.png uploaded but got %s: %q\n", header.Filename, data)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"attachments": [{"id": "3", "url": "http://stash/attachments/abc/3",
+			"links": {"attachment": {"href": "attachment:7/3"}}}]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	attachment, err := stashClient.Project("PRJ").Repo("widge").
+		UploadAttachment("report.png", strings.NewReader("PNG"))
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if markdown := attachment.Markdown(); markdown != "![report.png](attachment:7/3)" {
+		t.Fatalf("Want inline image reference but got %s\n", markdown)
+	}
+
+	attachment.Name = "build.log"
+	if markdown := attachment.Markdown(); markdown != "[build.log](attachment:7/3)" {
+		t.Fatalf("Want file link but got %s\n", markdown)
+	}
+}
@@ -0,0 +1,169 @@
+package stash
+
+import (
+	"sync"
+
+	"github.com/reconquest/karma-go"
+)
+
+type (
+	PermissionAuditOptions struct {
+		// Concurrency limits amount of projects audited at the same time,
+		// as well as amount of repositories of each project, default is 1.
+		Concurrency int
+	}
+
+	// PermissionAudit lists permissions granted explicitly on all levels,
+	// global grants go first, then grants of each project followed by
+	// grants of its repositories.
+	PermissionAudit struct {
+		Grants []PermissionGrant `json:"grants"`
+	}
+
+	// PermissionGrant is a permission granted to either user or group.
+	PermissionGrant struct {
+		Scope          PermissionScope `json:"scope"`
+		ProjectKey     string          `json:"projectKey,omitempty"`
+		RepositorySlug string          `json:"repositorySlug,omitempty"`
+		User           string          `json:"user,omitempty"`
+		Group          string          `json:"group,omitempty"`
+		Permission     Permission      `json:"permission"`
+	}
+)
+
+// AuditPermissions walks all projects and repositories and collects
+// permissions granted on them along with global ones. It fails if any of
+// permissions can't be read, since partial report is misleading.
+func (client Client) AuditPermissions(
+	options PermissionAuditOptions,
+) (PermissionAudit, error) {
+	grants, err := client.auditScope(PermissionGrant{Scope: PermissionScopeGlobal})
+	if err != nil {
+		return PermissionAudit{}, karma.Format(err, "audit global permissions")
+	}
+
+	projects, err := client.ListProjects()
+	if err != nil {
+		return PermissionAudit{}, err
+	}
+
+	var (
+		mutex    sync.Mutex
+		failure  error
+		reported = make([][]PermissionGrant, len(projects))
+	)
+
+	fail := func(err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if failure == nil {
+			failure = err
+		}
+	}
+
+	forEachConcurrently(len(projects), options.Concurrency, func(index int) {
+		project := projects[index]
+
+		projectGrants, err := client.auditScope(PermissionGrant{
+			Scope:      PermissionScopeProject,
+			ProjectKey: project.Key,
+		})
+		if err != nil {
+			fail(karma.Describe("project", project.Key).Reason(err))
+			return
+		}
+
+		repositories, err := client.ListProjectRepositories(project.Key)
+		if err != nil {
+			fail(karma.Describe("project", project.Key).Reason(err))
+			return
+		}
+
+		repositoryGrants := make([][]PermissionGrant, len(repositories))
+		forEachConcurrently(len(repositories), options.Concurrency, func(index int) {
+			grants, err := client.auditScope(PermissionGrant{
+				Scope:          PermissionScopeRepository,
+				ProjectKey:     project.Key,
+				RepositorySlug: repositories[index].Slug,
+			})
+			if err != nil {
+				fail(karma.
+					Describe("project", project.Key).
+					Describe("repository", repositories[index].Slug).
+					Reason(err))
+				return
+			}
+
+			repositoryGrants[index] = grants
+		})
+
+		for _, grants := range repositoryGrants {
+			projectGrants = append(projectGrants, grants...)
+		}
+
+		reported[index] = projectGrants
+	})
+
+	if failure != nil {
+		return PermissionAudit{}, failure
+	}
+
+	for _, projectGrants := range reported {
+		grants = append(grants, projectGrants...)
+	}
+
+	return PermissionAudit{Grants: grants}, nil
+}
+
+// auditScope returns user and group grants of the level described by scope.
+func (client Client) auditScope(scope PermissionGrant) ([]PermissionGrant, error) {
+	var (
+		users  []UserPermission
+		groups []GroupPermission
+		err    error
+	)
+
+	switch scope.Scope {
+	case PermissionScopeGlobal:
+		users, err = client.GetGlobalUserPermissions()
+		if err == nil {
+			groups, err = client.GetGlobalGroupPermissions()
+		}
+	case PermissionScopeProject:
+		users, err = client.GetProjectUserPermissions(scope.ProjectKey)
+		if err == nil {
+			groups, err = client.GetProjectGroupPermissions(scope.ProjectKey)
+		}
+	default:
+		users, err = client.GetRepositoryUserPermissions(
+			scope.ProjectKey, scope.RepositorySlug,
+		)
+		if err == nil {
+			groups, err = client.GetRepositoryGroupPermissions(
+				scope.ProjectKey, scope.RepositorySlug,
+			)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var grants []PermissionGrant
+	for _, user := range users {
+		grant := scope
+		grant.User = user.User.Name
+		grant.Permission = user.Permission
+		grants = append(grants, grant)
+	}
+
+	for _, group := range groups {
+		grant := scope
+		grant.Group = group.Group.Name
+		grant.Permission = group.Permission
+		grants = append(grants, grant)
+	}
+
+	return grants, nil
+}
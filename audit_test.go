@@ -0,0 +1,56 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestAuditPermissions(t *testing.T) {
+	responses := map[string]string{
+		"/rest/api/1.0/admin/permissions/users":                 `{"isLastPage": true, "values": [{"user": {"name": "admin"}, "permission": "SYS_ADMIN"}]}`,
+		"/rest/api/1.0/admin/permissions/groups":                `{"isLastPage": true, "values": [{"group": {"name": "developers"}, "permission": "LICENSED_USER"}]}`,
+		"/rest/api/1.0/projects":                                `{"isLastPage": true, "values": [{"key": "A"}, {"key": "B"}]}`,
+		"/rest/api/1.0/projects/A/permissions/users":            `{"isLastPage": true, "values": [{"user": {"name": "alice"}, "permission": "PROJECT_ADMIN"}]}`,
+		"/rest/api/1.0/projects/A/permissions/groups":           `{"isLastPage": true, "values": []}`,
+		"/rest/api/1.0/projects/B/permissions/users":            `{"isLastPage": true, "values": []}`,
+		"/rest/api/1.0/projects/B/permissions/groups":           `{"isLastPage": true, "values": [{"group": {"name": "qa"}, "permission": "PROJECT_READ"}]}`,
+		"/rest/api/1.0/projects/A/repos":                        `{"isLastPage": true, "values": [{"slug": "one"}]}`,
+		"/rest/api/1.0/projects/B/repos":                        `{"isLastPage": true, "values": []}`,
+		"/rest/api/1.0/projects/A/repos/one/permissions/users":  `{"isLastPage": true, "values": [{"user": {"name": "bob"}, "permission": "REPO_WRITE"}]}`,
+		"/rest/api/1.0/projects/A/repos/one/permissions/groups": `{"isLastPage": true, "values": []}`,
+	}
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			t.Fatalf("Unexpected request %s\n", r.URL.Path)
+		}
+		fmt.Fprint(w, response)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	audit, err := stashClient.AuditPermissions(PermissionAuditOptions{Concurrency: 4})
+	if err != nil {
+		t.Fatalf("AuditPermissions() not expecting an error, but received: %v\n", err)
+	}
+
+	want := []PermissionGrant{
+		{Scope: PermissionScopeGlobal, User: "admin", Permission: PermissionSysAdmin},
+		{Scope: PermissionScopeGlobal, Group: "developers", Permission: PermissionLicensedUser},
+		{Scope: PermissionScopeProject, ProjectKey: "A", User: "alice", Permission: PermissionProjectAdmin},
+		{Scope: PermissionScopeRepository, ProjectKey: "A", RepositorySlug: "one", User: "bob", Permission: PermissionRepoWrite},
+		{Scope: PermissionScopeProject, ProjectKey: "B", Group: "qa", Permission: PermissionProjectRead},
+	}
+	if !reflect.DeepEqual(audit.Grants, want) {
+		encoded, _ := json.MarshalIndent(audit, "", "  ")
+		t.Fatalf("Unexpected audit:\n%s\n", encoded)
+	}
+}
@@ -0,0 +1,168 @@
+package stash
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/reconquest/karma-go"
+)
+
+type (
+	// TokenSource supplies OAuth 2.0 access tokens, see WithTokenSource.
+	// Token is called for every request, so it's expected to cache token
+	// until it expires. golang.org/x/oauth2 token source is adapted as:
+	//
+	//	stash.TokenSourceFunc(func() (string, error) {
+	//		token, err := source.Token()
+	//		if err != nil {
+	//			return "", err
+	//		}
+	//
+	//		return token.AccessToken, nil
+	//	})
+	TokenSource interface {
+		Token() (string, error)
+	}
+
+	// RefreshingTokenSource is TokenSource which can drop cached token and
+	// obtain a new one, e.g. when cached token is revoked before it
+	// expires.
+	RefreshingTokenSource interface {
+		TokenSource
+		Refresh() (string, error)
+	}
+
+	TokenSourceFunc func() (string, error)
+)
+
+// WithTokenSource authenticates requests with OAuth 2.0 bearer tokens, for
+// servers fronted by OAuth capable gateway. If server rejects token with
+// 401, request is sent once again with a new token, which is obtained with
+// Refresh if source is RefreshingTokenSource, or with Token otherwise.
+// Token source takes precedence over other credentials.
+func WithTokenSource(source TokenSource) Option {
+	return func(client *Client) {
+		client.tokens = source
+	}
+}
+
+func (fn TokenSourceFunc) Token() (string, error) {
+	return fn()
+}
+
+// WithTrustedHeader authenticates requests by the header instead of basic
+// auth, for servers behind reverse proxy which authenticates users itself
+// and passes identity in a header, e.g. X-Forwarded-User. Value is redacted
+// in dumps, since proxies often expect a shared secret there. Option can be
+// given several times to set several headers.
+func WithTrustedHeader(name, value string) Option {
+	return func(client *Client) {
+		if client.authHeaders == nil {
+			client.authHeaders = http.Header{}
+		}
+
+		client.authHeaders.Set(name, value)
+	}
+}
+
+// WithSSOCookie authenticates requests by the single sign-on cookie instead
+// of basic auth, e.g. crowd.token_key issued by Crowd SSO.
+func WithSSOCookie(name, value string) Option {
+	return func(client *Client) {
+		client.authCookies = append(
+			client.authCookies, &http.Cookie{Name: name, Value: value},
+		)
+	}
+}
+
+// authenticate sets credentials on the request, token source goes first,
+// then trusted headers and SSO cookies, then user name and password.
+func (client Client) authenticate(request *http.Request) error {
+	if client.tokens != nil {
+		token, err := client.tokens.Token()
+		if err != nil {
+			return karma.Format(err, "get OAuth token")
+		}
+
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		return nil
+	}
+
+	if len(client.authHeaders) > 0 || len(client.authCookies) > 0 {
+		for name, values := range client.authHeaders {
+			request.Header[name] = values
+		}
+
+		for _, cookie := range client.authCookies {
+			request.AddCookie(cookie)
+		}
+
+		return nil
+	}
+
+	if client.userName != "" && client.password != "" {
+		request.SetBasicAuth(client.userName, client.password)
+	}
+
+	return nil
+}
+
+// sendAuthenticated sends the request, resending it once with a new token
+// if server rejects the one from token source. Request body must be
+// replayable, which is true for requests made by getRequest.
+func (client Client) sendAuthenticated(
+	request *http.Request,
+) (*http.Response, error) {
+	response, err := client.send(request)
+	if err != nil ||
+		client.tokens == nil ||
+		response.StatusCode != http.StatusUnauthorized ||
+		(request.Body != nil && request.GetBody == nil) {
+		return response, err
+	}
+
+	sent := request.Header.Get("Authorization")
+
+	var token string
+	if refreshing, ok := client.tokens.(RefreshingTokenSource); ok {
+		token, err = refreshing.Refresh()
+	} else {
+		token, err = client.tokens.Token()
+	}
+	if err != nil {
+		response.Body.Close()
+		return nil, karma.Format(err, "refresh OAuth token")
+	}
+
+	if "Bearer "+token == sent {
+		// source has nothing better, so 401 is returned as is
+		return response, nil
+	}
+
+	retry := request.Clone(request.Context())
+	if request.GetBody != nil {
+		retry.Body, err = request.GetBody()
+		if err != nil {
+			response.Body.Close()
+			return nil, err
+		}
+	}
+
+	retry.Header.Set("Authorization", "Bearer "+token)
+
+	response.Body.Close()
+
+	return client.send(retry)
+}
+
+// redactAuthHeaders hides values of trusted headers in the dump.
+func (client Client) redactAuthHeaders(dump string) string {
+	for name := range client.authHeaders {
+		dump = regexp.MustCompile(
+			`(?im)^(`+regexp.QuoteMeta(name)+`:).*$`,
+		).ReplaceAllString(dump, "$1 "+redacted)
+	}
+
+	return dump
+}
@@ -0,0 +1,127 @@
+package stash
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWithTrustedHeader(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Fatalf("Want no basic auth but got %s\n", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("X-Forwarded-User") != "bob" || r.Header.Get("X-Proxy-Secret") != "s3cr3t" {
+			t.Fatalf("Unexpected headers %v\n", r.Header)
+		}
+		fmt.Fprint(w, `{"isLastPage": true, "values": []}`)
+	}))
+	defer testServer.Close()
+
+	var output bytes.Buffer
+	defer func(logger *log.Logger) {
+		Log = logger
+	}(Log)
+	Log = log.New(&output, "", 0)
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient(
+		"u", "p", url,
+		WithTrustedHeader("X-Forwarded-User", "bob"),
+		WithTrustedHeader("X-Proxy-Secret", "s3cr3t"),
+		WithHTTPDump(nil),
+	)
+
+	_, err := stashClient.ListProjects()
+	if err != nil {
+		t.Fatalf("ListProjects() not expecting an error, but received: %v\n", err)
+	}
+
+	if strings.Contains(output.String(), "s3cr3t") {
+		t.Fatalf("Want trusted header redacted but got:\n%s\n", output.String())
+	}
+}
+
+func TestWithSSOCookie(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); ok {
+			t.Fatalf("Want no basic auth but got %s\n", r.Header.Get("Authorization"))
+		}
+		cookie, err := r.Cookie("crowd.token_key")
+		if err != nil || cookie.Value != "token" {
+			t.Fatalf("Want crowd.token_key cookie but got %v\n", r.Cookies())
+		}
+		fmt.Fprint(w, `{"isLastPage": true, "values": []}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithSSOCookie("crowd.token_key", "token"))
+
+	_, err := stashClient.ListProjects()
+	if err != nil {
+		t.Fatalf("ListProjects() not expecting an error, but received: %v\n", err)
+	}
+}
+
+// rotatingTokens issues a new token on every refresh.
+type rotatingTokens struct {
+	current   string
+	refreshes int
+}
+
+func (tokens *rotatingTokens) Token() (string, error) {
+	return tokens.current, nil
+}
+
+func (tokens *rotatingTokens) Refresh() (string, error) {
+	tokens.refreshes++
+	tokens.current = fmt.Sprintf("token-%d", tokens.refreshes)
+	return tokens.current, nil
+}
+
+func TestWithTokenSource(t *testing.T) {
+	var bodies []string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := new(bytes.Buffer)
+		body.ReadFrom(r.Body)
+		bodies = append(bodies, body.String())
+
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"errors": [{"message": "Authentication failed"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"id": "refs/heads/feature", "displayId": "feature"}`)
+	}))
+	defer testServer.Close()
+
+	tokens := &rotatingTokens{current: "expired"}
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithTokenSource(tokens))
+
+	_, err := stashClient.CreateBranch("PRJ", "widge", "feature", "master")
+	if err != nil {
+		t.Fatalf("Want no error after token refresh but got %s\n", err)
+	}
+
+	if tokens.refreshes != 1 || len(bodies) != 2 || bodies[0] != bodies[1] || bodies[1] == "" {
+		t.Fatalf("Want request resent once with the same body but got %d refreshes, %q\n", tokens.refreshes, bodies)
+	}
+
+	// static source has no new token, so 401 is reported
+	stashClient = NewClient("u", "p", url, WithTokenSource(TokenSourceFunc(func() (string, error) {
+		return "revoked", nil
+	})))
+
+	_, err = stashClient.CreateBranch("PRJ", "widge", "feature", "master")
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Want 401 error but got %v\n", err)
+	}
+}
@@ -0,0 +1,166 @@
+package stash
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures retries of requests which fail with network error
+// or transient server error, see WithRetryPolicy.
+type RetryPolicy struct {
+	// MaxAttempts limits amount of times request is sent, including the
+	// first one. Default is 3.
+	MaxAttempts int
+
+	// Backoff is a delay before the first retry, it's doubled for every
+	// next one. Default is 500ms.
+	Backoff time.Duration
+
+	// MaxBackoff limits delay between retries, default is 30s. Delay
+	// requested by server with Retry-After is limited too.
+	MaxBackoff time.Duration
+
+	// Jitter randomly shortens delay by up to given fraction, e.g. 0.5,
+	// so concurrent jobs don't retry at the same moment.
+	Jitter float64
+}
+
+// WithRetryPolicy makes client retry requests which fail with network
+// error or 500, 502, 503 and 504 statuses. Requests which are not
+// idempotent, like POST, are only retried on 503, since server doesn't
+// process them then. Retry-After header of the response is honored.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(client *Client) {
+		if policy.MaxAttempts == 0 {
+			policy.MaxAttempts = 3
+		}
+
+		if policy.Backoff == 0 {
+			policy.Backoff = 500 * time.Millisecond
+		}
+
+		if policy.MaxBackoff == 0 {
+			policy.MaxBackoff = 30 * time.Second
+		}
+
+		client.retries = &policy
+	}
+}
+
+// sendRetrying sends the request, repeating it according to the retry
+// policy of the client.
+func (client Client) sendRetrying(request *http.Request) (*http.Response, error) {
+	policy := client.retries
+	if policy == nil || (request.Body != nil && request.GetBody == nil) {
+		return client.sendThrottled(request)
+	}
+
+	for attempt := 1; ; attempt++ {
+		response, err := client.sendThrottled(request)
+		if attempt >= policy.MaxAttempts || !policy.retryable(request, response, err) {
+			return response, err
+		}
+
+		delay := policy.delay(attempt, response)
+
+		if response != nil {
+			// connection is reused only if body is read to the end
+			io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		}
+
+		sleepErr := client.sleep(delay)
+		if sleepErr != nil {
+			return nil, sleepErr
+		}
+
+		request = request.Clone(request.Context())
+		if request.GetBody != nil {
+			request.Body, err = request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// retryable tells whether request failed with transient error.
+func (policy RetryPolicy) retryable(
+	request *http.Request,
+	response *http.Response,
+	err error,
+) bool {
+	if err != nil {
+		if errors.Is(err, request.Context().Err()) {
+			return false
+		}
+
+		return idempotent(request.Method)
+	}
+
+	switch response.StatusCode {
+	case http.StatusServiceUnavailable:
+		return true
+	case http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusGatewayTimeout:
+		return idempotent(request.Method)
+	}
+
+	return false
+}
+
+// delay returns how long to wait before the given retry.
+func (policy RetryPolicy) delay(attempt int, response *http.Response) time.Duration {
+	if response != nil {
+		if delay, ok := retryAfter(response); ok {
+			return min(delay, policy.MaxBackoff)
+		}
+	}
+
+	delay := policy.Backoff << (attempt - 1)
+	if delay <= 0 || delay > policy.MaxBackoff {
+		delay = policy.MaxBackoff
+	}
+
+	if policy.Jitter > 0 {
+		delay -= time.Duration(float64(delay) * policy.Jitter * rand.Float64())
+	}
+
+	return delay
+}
+
+// retryAfter parses Retry-After header, which is either amount of seconds
+// or HTTP date.
+func retryAfter(response *http.Response) (time.Duration, bool) {
+	header := response.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(header)
+	if err == nil {
+		return max(time.Duration(seconds)*time.Second, 0), true
+	}
+
+	date, err := http.ParseTime(header)
+	if err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions,
+		http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
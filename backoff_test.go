@@ -0,0 +1,65 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestWithRetryPolicy(t *testing.T) {
+	var requests int
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		switch r.Method {
+		case "GET":
+			if requests < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			fmt.Fprint(w, `{"slug": "widge", "project": {"key": "PRJ"}}`)
+		case "POST":
+			// not idempotent, so 500 is not retried
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithRetryPolicy(RetryPolicy{
+		Backoff: time.Millisecond,
+	}))
+
+	repository, err := stashClient.GetRepository("PRJ", "widge")
+	if err != nil || repository.Slug != "widge" {
+		t.Fatalf("Want repository after retries but got %v\n", err)
+	}
+
+	if requests != 3 {
+		t.Fatalf("Want 3 requests but got %d\n", requests)
+	}
+
+	requests = 0
+	_, err = stashClient.CreateBranch("PRJ", "widge", "feature", "master")
+	if err == nil || requests != 1 {
+		t.Fatalf("Want POST to fail without retries but got %d requests, %v\n", requests, err)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+
+	for attempt, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		if delay := policy.delay(attempt+1, nil); delay != want {
+			t.Fatalf("Want %s delay for attempt %d but got %s\n", want, attempt+1, delay)
+		}
+	}
+
+	response := &http.Response{Header: http.Header{"Retry-After": {"3"}}}
+	if delay := policy.delay(1, response); delay != 3*time.Second {
+		t.Fatalf("Want Retry-After delay 3s but got %s\n", delay)
+	}
+}
@@ -109,3 +109,57 @@ func TestGetBranches500(t *testing.T) {
 		t.Fatalf("GetBranches() expecting an error but received none\n")
 	}
 }
+
+func TestListBranches(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		url := *r.URL
+		if url.Path != "/rest/api/1.0/projects/PRJ/repos/widge/branches" {
+			t.Fatalf("ListBranches() URL path expected to be /rest/api/1.0/projects/PRJ/repos/widge/branches but found %s\n", url.Path)
+		}
+		fmt.Fprintln(w, branches)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	branches, err := stashClient.ListBranches("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("ListBranches() not expecting an error, but received: %v\n", err)
+	}
+
+	want := []string{"develop", "master", "feature/PRJ-447", "bug/PRJ-442"}
+	if len(branches) != len(want) {
+		t.Fatalf("ListBranches() expected to return %d branches, but received %d\n", len(want), len(branches))
+	}
+
+	for i, name := range want {
+		if branches[i].DisplayID != name {
+			t.Fatalf("Wanted branch %s at position %d but found %s\n", name, i, branches[i].DisplayID)
+		}
+	}
+}
+
+func TestGetBranchesPage(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		if params.Get("start") != "25" || params.Get("limit") != "50" {
+			t.Fatalf("Want start=25&limit=50 but found %s\n", r.URL.RawQuery)
+		}
+		fmt.Fprintln(w, branches)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	page, err := stashClient.GetBranchesPage("PRJ", "widge", 25, 50)
+	if err != nil {
+		t.Fatalf("GetBranchesPage() not expecting an error, but received: %v\n", err)
+	}
+
+	if !page.IsLastPage {
+		t.Fatalf("Want last page but found %+v\n", page)
+	}
+	if page.Size != 7 {
+		t.Fatalf("Want size 7 but found %d\n", page.Size)
+	}
+}
@@ -0,0 +1,130 @@
+package stash
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type (
+	BrowseOptions struct {
+		// At is a branch, tag or commit to read file at, default branch is
+		// used if it's empty.
+		At string
+
+		// Start is the first line of the page, counting from zero, and
+		// Limit is amount of lines in the page, server default is used if
+		// it's zero.
+		Start int
+		Limit int
+
+		// Blame includes authors of the page lines.
+		Blame bool
+	}
+
+	// FilePage is a chunk of text file lines. Use NextPageStart as
+	// BrowseOptions.Start to read the next chunk until IsLastPage is true.
+	FilePage struct {
+		Page
+		Lines []FileLine `json:"lines"`
+
+		// Blame is filled only when requested with BrowseOptions.Blame.
+		Blame []BlameSpan `json:"blame"`
+	}
+
+	FileLine struct {
+		Text string `json:"text"`
+	}
+
+	// BlameSpan is a group of consecutive lines last changed by the same
+	// commit, LineNumber is the first line of the span counting from one.
+	BlameSpan struct {
+		Author             User   `json:"author"`
+		AuthorTimestamp    int64  `json:"authorTimestamp"`
+		Committer          User   `json:"committer"`
+		CommitterTimestamp int64  `json:"committerTimestamp"`
+		CommitID           string `json:"commitId"`
+		DisplayCommitID    string `json:"displayCommitId"`
+		FileName           string `json:"fileName"`
+		LineNumber         int    `json:"lineNumber"`
+		SpannedLines       int    `json:"spannedLines"`
+	}
+
+	// LastModified maps names of directory entries to commits which
+	// changed them last.
+	LastModified struct {
+		Files map[string]Commit `json:"files"`
+		// LatestCommit is the commit the directory was read at.
+		LatestCommit Commit `json:"latestCommit"`
+	}
+)
+
+// BrowseFile returns a page of lines of the text file, so large files are
+// read in chunks.
+func (client Client) BrowseFile(
+	projectKey, repositorySlug, filePath string,
+	options BrowseOptions,
+) (FilePage, error) {
+	query := url.Values{}
+	if options.At != "" {
+		query.Set("at", options.At)
+	}
+	query.Set("start", strconv.Itoa(options.Start))
+	if options.Limit > 0 {
+		query.Set("limit", strconv.Itoa(options.Limit))
+	}
+	if options.Blame {
+		query.Set("blame", "true")
+	}
+
+	var response FilePage
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/browse/%s?%s",
+			projectKey, repositorySlug,
+			rawPath(escapeFilePath(filePath)), rawPath(query.Encode()),
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return FilePage{}, err
+	}
+
+	// unlike listings, file pages don't tell where the next one starts
+	if !response.IsLastPage && response.NextPageStart == 0 {
+		response.NextPageStart = response.Start + response.Size
+	}
+
+	return response, nil
+}
+
+// GetLastModified returns the latest commit which changed each file and
+// subdirectory of the directory at the given ref, empty path selects the
+// repository root. Requires Bitbucket 5.x or newer.
+func (client Client) GetLastModified(
+	projectKey, repositorySlug, path, at string,
+) (LastModified, error) {
+	query := url.Values{}
+	query.Set("at", at)
+
+	var response LastModified
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/last-modified%s?%s",
+			projectKey, repositorySlug,
+			rawPath(directoryPath(path)), rawPath(query.Encode()),
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return LastModified{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,66 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestBrowseFile(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PRJ/repos/widge/browse/src/main.go" {
+			t.Fatalf("Want browse path but found %s\n", r.URL.Path)
+		}
+
+		params := r.URL.Query()
+		if params.Get("limit") != "2" || params.Get("blame") != "true" {
+			t.Fatalf("Want 2 lines with blame but got %s\n", r.URL.RawQuery)
+		}
+
+		switch params.Get("start") {
+		case "0":
+			fmt.Fprint(w, `{"lines": [{"text": "package main"}, {"text": ""}],
+				"start": 0, "size": 2, "isLastPage": false,
+				"blame": [{"author": {"name": "alice"}, "commitId": "abc", "lineNumber": 1, "spannedLines": 2}]}`)
+		case "2":
+			fmt.Fprint(w, `{"lines": [{"text": "func main() {}"}], "start": 2, "size": 1, "isLastPage": true}`)
+		default:
+			t.Fatalf("Unexpected query %s\n", r.URL.RawQuery)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	options := BrowseOptions{Limit: 2, Blame: true}
+
+	var lines []string
+	for {
+		page, err := stashClient.BrowseFile("PRJ", "widge", "src/main.go", options)
+		if err != nil {
+			t.Fatalf("Not expecting error: %v\n", err)
+		}
+
+		if options.Start == 0 &&
+			(len(page.Blame) != 1 || page.Blame[0].Author.Name != "alice") {
+			t.Fatalf("Want blame by alice but got %+v\n", page.Blame)
+		}
+
+		for _, line := range page.Lines {
+			lines = append(lines, line.Text)
+		}
+
+		if page.IsLastPage {
+			break
+		}
+
+		options.Start = page.NextPageStart
+	}
+
+	if len(lines) != 3 || lines[2] != "func main() {}" {
+		t.Fatalf("Want 3 lines but got %q\n", lines)
+	}
+}
@@ -0,0 +1,62 @@
+package stash
+
+import "net/http"
+
+// BuildStatusStats counts builds reported for a commit by their state.
+type BuildStatusStats struct {
+	Successful int `json:"successful"`
+	InProgress int `json:"inProgress"`
+	Failed     int `json:"failed"`
+}
+
+// GetBuildStatusStats returns counts of builds reported for the commit.
+func (client Client) GetBuildStatusStats(commitID string) (BuildStatusStats, error) {
+	var response BuildStatusStats
+	err := client.requestJSON(
+		"GET",
+		pathf("/rest/build-status/1.0/commits/stats/%s", commitID),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return BuildStatusStats{}, err
+	}
+
+	return response, nil
+}
+
+// GetBuildStatusStatsForCommits returns counts of builds for many commits
+// with a single request, keyed by commit ID. Commits without builds are
+// reported with zero counts.
+func (client Client) GetBuildStatusStatsForCommits(
+	commitIDs []string,
+) (map[string]BuildStatusStats, error) {
+	if len(commitIDs) == 0 {
+		return map[string]BuildStatusStats{}, nil
+	}
+
+	var response map[string]BuildStatusStats
+	err := client.requestJSON(
+		"POST", "/rest/build-status/1.0/commits/stats",
+		commitIDs,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Total returns amount of builds reported for the commit.
+func (stats BuildStatusStats) Total() int {
+	return stats.Successful + stats.InProgress + stats.Failed
+}
+
+// Passed reports whether there is at least one build and all of them are
+// successful, which is what merge checks usually require.
+func (stats BuildStatusStats) Passed() bool {
+	return stats.Successful > 0 && stats.InProgress == 0 && stats.Failed == 0
+}
@@ -0,0 +1,50 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetBuildStatusStats(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/build-status/1.0/commits/stats/aaa":
+			fmt.Fprint(w, `{"successful": 2, "inProgress": 1, "failed": 0}`)
+		case "POST /rest/build-status/1.0/commits/stats":
+			var commits []string
+			if err := json.NewDecoder(r.Body).Decode(&commits); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if len(commits) != 2 {
+				t.Fatalf("Want 2 commits but found %v\n", commits)
+			}
+			fmt.Fprint(w, `{"aaa": {"successful": 2}, "bbb": {"failed": 1}}`)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	stats, err := stashClient.GetBuildStatusStats("aaa")
+	if err != nil {
+		t.Fatalf("GetBuildStatusStats() not expecting an error, but received: %v\n", err)
+	}
+	if stats.Total() != 3 || stats.Passed() {
+		t.Fatalf("Unexpected stats %+v\n", stats)
+	}
+
+	all, err := stashClient.GetBuildStatusStatsForCommits([]string{"aaa", "bbb"})
+	if err != nil {
+		t.Fatalf("GetBuildStatusStatsForCommits() not expecting an error, but received: %v\n", err)
+	}
+	if !all["aaa"].Passed() || all["bbb"].Passed() {
+		t.Fatalf("Unexpected stats %+v\n", all)
+	}
+}
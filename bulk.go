@@ -0,0 +1,139 @@
+package stash
+
+import "sync"
+
+type (
+	DeleteBranchesOptions struct {
+		// Concurrency limits amount of branches being deleted at the same
+		// time, branches are deleted one by one if it's not set.
+		Concurrency int
+		// DryRun only checks that branches can be deleted.
+		DryRun bool
+	}
+
+	// BranchDeletion is an outcome of deleting a single branch by
+	// DeleteBranches.
+	BranchDeletion struct {
+		Branch string
+		Err    error
+
+		// Vetoes explain why branch can't be deleted, e.g. restrictions
+		// or open pull requests blocking it.
+		Vetoes []Veto
+	}
+)
+
+// DeleteBranches deletes given branches with bounded concurrency and returns
+// an outcome per branch in the same order as branch names are given.
+// Failure to delete one branch doesn't stop deletion of other ones.
+func (client Client) DeleteBranches(
+	projectKey, repositorySlug string,
+	branchNames []string,
+	options DeleteBranchesOptions,
+) []BranchDeletion {
+	results := make([]BranchDeletion, len(branchNames))
+
+	forEachConcurrently(
+		len(branchNames), options.Concurrency,
+		func(index int) {
+			err := client.DeleteBranchWithOptions(
+				projectKey, repositorySlug,
+				branchNames[index],
+				DeleteBranchOptions{DryRun: options.DryRun},
+			)
+
+			results[index] = BranchDeletion{
+				Branch: branchNames[index],
+				Err:    err,
+				Vetoes: ResponseVetoes(err),
+			}
+		},
+	)
+
+	return results
+}
+
+type (
+	// BranchSpec describes branch to be created by CreateBranches.
+	BranchSpec struct {
+		Repository RepositoryRef
+		Name       string
+
+		// StartPoint is a commit hash or name of existing branch or tag.
+		StartPoint string
+	}
+
+	CreateBranchesOptions struct {
+		// Concurrency limits amount of branches being created at the same
+		// time, branches are created one by one if it's not set.
+		Concurrency int
+	}
+
+	// BranchCreation is an outcome of creating a single branch by
+	// CreateBranches.
+	BranchCreation struct {
+		Spec   BranchSpec
+		Branch Branch
+		Err    error
+	}
+)
+
+// CreateBranches creates branches described by specs, possibly in different
+// repositories, with bounded concurrency and returns an outcome per spec in
+// the same order as specs are given. Failure to create one branch doesn't
+// stop creation of other ones.
+func (client Client) CreateBranches(
+	specs []BranchSpec,
+	options CreateBranchesOptions,
+) []BranchCreation {
+	results := make([]BranchCreation, len(specs))
+
+	forEachConcurrently(
+		len(specs), options.Concurrency,
+		func(index int) {
+			spec := specs[index]
+
+			branch, err := client.CreateBranch(
+				spec.Repository.ProjectKey, spec.Repository.Slug,
+				spec.Name, spec.StartPoint,
+			)
+
+			results[index] = BranchCreation{
+				Spec:   spec,
+				Branch: branch,
+				Err:    err,
+			}
+		},
+	)
+
+	return results
+}
+
+// forEachConcurrently calls fn for every index in [0, count) running at most
+// concurrency calls at the same time.
+func forEachConcurrently(count, concurrency int, fn func(index int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		group     sync.WaitGroup
+		semaphore = make(chan struct{}, concurrency)
+	)
+
+	for index := 0; index < count; index++ {
+		group.Add(1)
+		semaphore <- struct{}{}
+
+		go func(index int) {
+			defer func() {
+				<-semaphore
+				group.Done()
+			}()
+
+			fn(index)
+		}(index)
+	}
+
+	group.Wait()
+}
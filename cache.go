@@ -0,0 +1,115 @@
+package stash
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+type (
+	// listingCache keeps results of listings for a limited time. Any
+	// mutating request made by the client drops everything, since it's not
+	// known which listings it affects.
+	listingCache struct {
+		sync.Mutex
+		ttl        time.Duration
+		generation uint64
+		entries    map[string]cacheEntry
+	}
+
+	cacheEntry struct {
+		value   interface{}
+		expires time.Time
+	}
+)
+
+// WithCache makes GetRepositories, GetProjectRepositories, GetBranches and
+// their List variants reuse results for given ttl. Cache is dropped on any
+// non-GET request made by the same client.
+func WithCache(ttl time.Duration) Option {
+	return func(client *Client) {
+		if ttl > 0 {
+			client.cache = &listingCache{
+				ttl:     ttl,
+				entries: map[string]cacheEntry{},
+			}
+		}
+	}
+}
+
+// cached returns copy of value stored under key, calling fetch when it's
+// missing or expired.
+func cached[T any](
+	client Client,
+	key string,
+	fetch func() ([]T, error),
+) ([]T, error) {
+	if client.cache == nil {
+		return fetch()
+	}
+
+	value, generation, ok := client.cache.get(key)
+	if ok {
+		return append([]T(nil), value.([]T)...), nil
+	}
+
+	list, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	client.cache.set(key, generation, append([]T(nil), list...))
+
+	return list, nil
+}
+
+func (cache *listingCache) get(key string) (interface{}, uint64, bool) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, cache.generation, false
+	}
+
+	return entry.value, cache.generation, true
+}
+
+// set stores value unless cache was invalidated since fetching has been
+// started, so listing racing with a mutation is never kept.
+func (cache *listingCache) set(key string, generation uint64, value interface{}) {
+	cache.Lock()
+	defer cache.Unlock()
+
+	if cache.generation != generation {
+		return
+	}
+
+	cache.entries[key] = cacheEntry{
+		value:   value,
+		expires: time.Now().Add(cache.ttl),
+	}
+}
+
+func (cache *listingCache) invalidate() {
+	cache.Lock()
+	defer cache.Unlock()
+
+	cache.generation++
+	cache.entries = map[string]cacheEntry{}
+}
+
+// invalidateCache drops cached listings if request might change server
+// state.
+func (client Client) invalidateCache(request *http.Request) {
+	if client.cache == nil {
+		return
+	}
+
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+
+	client.cache.invalidate()
+}
@@ -0,0 +1,83 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithCache(t *testing.T) {
+	var listings int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		atomic.AddInt32(&listings, 1)
+		fmt.Fprint(w, branches)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithCache(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		branches, err := stashClient.GetBranches("PRJ", "widge")
+		if err != nil {
+			t.Fatalf("GetBranches() not expecting an error, but received: %v\n", err)
+		}
+		if len(branches) != 4 {
+			t.Fatalf("Want 4 branches but got %d\n", len(branches))
+		}
+	}
+
+	if listings != 1 {
+		t.Fatalf("Want 1 listing request but got %d\n", listings)
+	}
+
+	if _, err := stashClient.ListBranches("PRJ", "other"); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if listings != 2 {
+		t.Fatalf("Want 2 listing requests but got %d\n", listings)
+	}
+
+	if err := stashClient.DeleteBranch("PRJ", "widge", "master"); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if _, err := stashClient.GetBranches("PRJ", "widge"); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if listings != 3 {
+		t.Fatalf("Want cache to be dropped after DELETE but got %d listing requests\n", listings)
+	}
+}
+
+func TestWithCacheExpires(t *testing.T) {
+	var listings int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&listings, 1)
+		fmt.Fprint(w, repos)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithCache(time.Millisecond))
+
+	if _, err := stashClient.GetRepositories(); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := stashClient.GetRepositories(); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if listings != 2 {
+		t.Fatalf("Want 2 listing requests but got %d\n", listings)
+	}
+}
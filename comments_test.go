@@ -0,0 +1,176 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+func TestCreateInlineComment(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantPath := "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/42/comments"
+		if r.Method != "POST" || r.URL.Path != wantPath {
+			t.Fatalf("Want POST %s but found %s %s\n", wantPath, r.Method, r.URL.Path)
+		}
+
+		var payload map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&payload)
+
+		anchor, _ := payload["anchor"].(map[string]interface{})
+		if payload["text"] != "unused variable" ||
+			anchor["path"] != "main.go" ||
+			anchor["line"] != float64(12) ||
+			anchor["lineType"] != "ADDED" ||
+			anchor["fileType"] != "TO" ||
+			anchor["toHash"] != "def" {
+			t.Fatalf("Want anchored comment but got %v\n", payload)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 17, "version": 0, "text": "unused variable",
+			"anchor": {"path": "main.go", "line": 12, "lineType": "ADDED", "fileType": "TO"}}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	comment, err := stashClient.Project("PRJ").Repo("widge").PullRequest(42).InlineComment(
+		"unused variable",
+		CommentAnchor{
+			Path:     "main.go",
+			Line:     12,
+			LineType: CommentLineTypeAdded,
+			FileType: CommentFileTypeTo,
+			FromHash: "abc",
+			ToHash:   "def",
+		},
+	)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if comment.ID != 17 || comment.Anchor == nil || comment.Anchor.Line != 12 {
+		t.Fatalf("Want comment on line 12 but got %+v\n", comment)
+	}
+}
+
+func TestReplyComment(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload CommentResource
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Parent == nil || payload.Parent.ID != 17 || payload.Anchor != nil {
+			t.Fatalf("Want reply to comment 17 but got %+v\n", payload)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id": 18, "version": 0, "text": "done",
+			"author": {"name": "bot"}, "createdDate": 1700000000000,
+			"comments": [{"id": 19, "text": "thanks"}]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	comment, err := stashClient.Project("PRJ").Repo("widge").PullRequest(42).Reply(17, "done")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if comment.ID != 18 ||
+		comment.Author.Name != "bot" ||
+		comment.CreatedDate != 1700000000000 ||
+		len(comment.Comments) != 1 {
+		t.Fatalf("Want full reply comment but got %+v\n", comment)
+	}
+}
+
+func TestCommitComments(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantPath := "/rest/api/1.0/projects/PRJ/repos/widge/commits/abc/comments"
+		if r.URL.Path != wantPath {
+			t.Fatalf("Want %s but found %s\n", wantPath, r.URL.Path)
+		}
+
+		switch r.Method {
+		case "POST":
+			var payload CommentResource
+			json.NewDecoder(r.Body).Decode(&payload)
+			if payload.Anchor == nil || payload.Anchor.Path != "main.go" || payload.Anchor.Line != 3 {
+				t.Fatalf("Want comment on main.go:3 but got %+v\n", payload)
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": 7, "text": "insecure", "anchor": {"path": "main.go", "line": 3}}`)
+		case "GET":
+			if r.URL.Query().Get("path") != "main.go" {
+				t.Fatalf("Want comments filtered by path but got %s\n", r.URL.RawQuery)
+			}
+
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"id": 7, "text": "insecure"}]}`)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	repo := NewClient("u", "p", url).Project("PRJ").Repo("widge")
+
+	comment, err := repo.CommentCommit("abc", "insecure", &CommentAnchor{
+		Path:     "main.go",
+		Line:     3,
+		LineType: CommentLineTypeAdded,
+		FileType: CommentFileTypeTo,
+	})
+	if err != nil || comment.ID != 7 {
+		t.Fatalf("Want created comment but got %+v, %v\n", comment, err)
+	}
+
+	comments, err := repo.CommitComments("abc", "main.go")
+	if err != nil || len(comments) != 1 || comments[0].Text != "insecure" {
+		t.Fatalf("Want single comment but got %+v, %v\n", comments, err)
+	}
+}
+
+func TestCommitCommentsWithPagePrefetch(t *testing.T) {
+	const total = 200
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("path") != "main.go" {
+			t.Errorf("Want comments filtered by path but got %s\n", r.URL.RawQuery)
+		}
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		response := Paged[Comment]{}
+		for i := start; i < start+limit && i < total; i++ {
+			response.Values = append(response.Values, Comment{ID: i})
+		}
+		response.IsLastPage = start+limit >= total
+		response.NextPageStart = start + limit
+
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	comments, err := NewClient("u", "p", url, WithPagePrefetch(8)).
+		GetCommitComments("PRJ", "widge", "abc", "main.go")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(comments) != total {
+		t.Fatalf("Want %d comments but got %d\n", total, len(comments))
+	}
+	for i, comment := range comments {
+		if comment.ID != i {
+			t.Fatalf("Want comment %d at position %d but got %d\n", i, i, comment.ID)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package stash
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// CreateCommitComment creates a comment on the commit, which doesn't need to
+// be a part of any pull request. Anchor places comment on a file or a line
+// of the commit diff, nil anchor creates a general comment.
+func (client Client) CreateCommitComment(
+	projectKey, repositorySlug, commitHash, text string,
+	anchor *CommentAnchor,
+) (Comment, error) {
+	return client.postComment(
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/commits/%s/comments",
+			projectKey, repositorySlug, commitHash,
+		),
+		CommentResource{Text: text, Anchor: anchor},
+	)
+}
+
+// GetCommitComments returns comments made on the given file of the commit,
+// or on the whole commit if path is empty.
+func (client Client) GetCommitComments(
+	projectKey, repositorySlug, commitHash, path string,
+) ([]Comment, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[Comment], error) {
+			query := url.Values{}
+			if path != "" {
+				query.Set("path", path)
+			}
+			query.Set("start", strconv.Itoa(start))
+			query.Set("limit", strconv.Itoa(limit))
+
+			var response Paged[Comment]
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/api/1.0/projects/%s/repos/%s/commits/%s/comments?%s",
+					projectKey, repositorySlug, commitHash,
+					rawPath(query.Encode()),
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
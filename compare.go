@@ -0,0 +1,147 @@
+package stash
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type (
+	// AheadBehind tells how far two refs have diverged.
+	AheadBehind struct {
+		// Ahead is amount of commits reachable from ref but not from base.
+		Ahead int
+		// Behind is amount of commits reachable from base but not from ref.
+		Behind int
+	}
+
+	// Change is a file changed between two refs, SrcPath is set only for
+	// moved and copied files.
+	Change struct {
+		ContentID        string     `json:"contentId"`
+		FromContentID    string     `json:"fromContentId"`
+		Path             DiffPath   `json:"path"`
+		SrcPath          *DiffPath  `json:"srcPath,omitempty"`
+		Type             ChangeType `json:"type"`
+		NodeType         string     `json:"nodeType"`
+		Executable       bool       `json:"executable"`
+		PercentUnchanged int        `json:"percentUnchanged"`
+	}
+)
+
+// GetAheadBehind counts commits ref and base don't share using the compare
+// API, refs are branch or tag names, full ref IDs or commit hashes. Every
+// differing commit is listed by the server, so refs which diverged a lot
+// take many requests.
+func (client Client) GetAheadBehind(
+	projectKey, repositorySlug, ref, base string,
+) (AheadBehind, error) {
+	ahead, err := client.countCommitsBetween(projectKey, repositorySlug, ref, base)
+	if err != nil {
+		return AheadBehind{}, err
+	}
+
+	behind, err := client.countCommitsBetween(projectKey, repositorySlug, base, ref)
+	if err != nil {
+		return AheadBehind{}, err
+	}
+
+	return AheadBehind{Ahead: ahead, Behind: behind}, nil
+}
+
+// countCommitsBetween counts commits reachable from from but not from to.
+func (client Client) countCommitsBetween(
+	projectKey, repositorySlug, from, to string,
+) (int, error) {
+	// commits are decoded into empty structs, since only amount matters
+	commits, err := comparePages[struct{}](
+		client, projectKey, repositorySlug, "commits", from, to,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(commits), nil
+}
+
+// CompareCommits returns commits reachable from from but not from to, e.g.
+// commits of master which are not released yet are compared from "master"
+// to "release/1.2". Refs are branch or tag names, full ref IDs or commit
+// hashes.
+func (client Client) CompareCommits(
+	projectKey, repositorySlug, from, to string,
+) ([]Commit, error) {
+	return comparePages[Commit](
+		client, projectKey, repositorySlug, "commits", from, to,
+	)
+}
+
+// CompareChanges returns files changed by commits available from from but
+// not from to, same as git diff to...from.
+func (client Client) CompareChanges(
+	projectKey, repositorySlug, from, to string,
+) ([]Change, error) {
+	return comparePages[Change](
+		client, projectKey, repositorySlug, "changes", from, to,
+	)
+}
+
+// CompareDiff returns structured diff of changes available from from but not
+// from to, same as git diff to...from.
+func (client Client) CompareDiff(
+	projectKey, repositorySlug, from, to string,
+	options DiffOptions,
+) (Diff, error) {
+	values := options.values()
+	values.Set("from", from)
+	values.Set("to", to)
+
+	var response Diff
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/compare/diff%s?%s",
+			projectKey, repositorySlug,
+			rawPath(options.filePath()), rawPath(values.Encode()),
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	return response, nil
+}
+
+// comparePages lists all pages of the compare endpoint.
+func comparePages[T any](
+	client Client,
+	projectKey, repositorySlug, endpoint, from, to string,
+) ([]T, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[T], error) {
+			query := url.Values{}
+			query.Set("from", from)
+			query.Set("to", to)
+			query.Set("start", strconv.Itoa(start))
+			query.Set("limit", strconv.Itoa(limit))
+
+			var response Paged[T]
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/api/1.0/projects/%s/repos/%s/compare/%s?%s",
+					projectKey, repositorySlug, endpoint,
+					rawPath(query.Encode()),
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
@@ -0,0 +1,93 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetAheadBehind(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PRJ/repos/widge/compare/commits" {
+			t.Fatalf("Want /rest/api/1.0/projects/PRJ/repos/widge/compare/commits but found %s\n", r.URL.Path)
+		}
+
+		params := r.URL.Query()
+		switch params.Get("from") + ".." + params.Get("to") {
+		case "feature..master":
+			if params.Get("start") == "0" {
+				fmt.Fprint(w, `{"isLastPage": false, "nextPageStart": 2, "values": [{"id": "a"}, {"id": "b"}]}`)
+			} else {
+				fmt.Fprint(w, `{"isLastPage": true, "values": [{"id": "c"}]}`)
+			}
+		case "master..feature":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"id": "d"}]}`)
+		default:
+			t.Fatalf("Unexpected query %s\n", r.URL.RawQuery)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	counts, err := stashClient.GetAheadBehind("PRJ", "widge", "feature", "master")
+	if err != nil {
+		t.Fatalf("GetAheadBehind() not expecting an error, but received: %v\n", err)
+	}
+	if counts.Ahead != 3 || counts.Behind != 1 {
+		t.Fatalf("Want 3 ahead and 1 behind but got %+v\n", counts)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		if params.Get("from") != "master" || params.Get("to") != "release/1.2" {
+			t.Fatalf("Want master compared to release/1.2 but got %s\n", r.URL.RawQuery)
+		}
+
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PRJ/repos/widge/compare/commits":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"id": "abc", "message": "Fix"}]}`)
+		case "/rest/api/1.0/projects/PRJ/repos/widge/compare/changes":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [
+				{"path": {"toString": "new.go"}, "srcPath": {"toString": "old.go"}, "type": "MOVE"}
+			]}`)
+		case "/rest/api/1.0/projects/PRJ/repos/widge/compare/diff/new.go":
+			if params.Get("whitespace") != "ignore-all" {
+				t.Fatalf("Want ignore-all but found %s\n", params.Get("whitespace"))
+			}
+			fmt.Fprint(w, pullRequestDiffResponse)
+		default:
+			t.Fatalf("Unexpected path %s\n", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	commits, err := stashClient.CompareCommits("PRJ", "widge", "master", "release/1.2")
+	if err != nil || len(commits) != 1 || commits[0].ID != "abc" {
+		t.Fatalf("Want single commit but got %+v, %v\n", commits, err)
+	}
+
+	changes, err := stashClient.CompareChanges("PRJ", "widge", "master", "release/1.2")
+	if err != nil || len(changes) != 1 ||
+		changes[0].Type != ChangeTypeMove ||
+		changes[0].SrcPath == nil ||
+		changes[0].SrcPath.ToString != "old.go" {
+		t.Fatalf("Want moved file but got %+v, %v\n", changes, err)
+	}
+
+	diff, err := stashClient.CompareDiff(
+		"PRJ", "widge", "master", "release/1.2",
+		DiffOptions{Path: "new.go", IgnoreWhitespace: true},
+	)
+	if err != nil || len(diff.Diffs) != 1 {
+		t.Fatalf("Want diff but got %+v, %v\n", diff, err)
+	}
+}
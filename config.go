@@ -0,0 +1,127 @@
+package stash
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+
+	"github.com/reconquest/karma-go"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Config is a set of named server profiles shared across tools:
+	//
+	//	default: work
+	//	profiles:
+	//	  work:
+	//	    url: https://stash.example.com
+	//	    username: bob
+	//	    token: NzY0...
+	//	    project: PRJ
+	Config struct {
+		Default  string             `yaml:"default"`
+		Profiles map[string]Profile `yaml:"profiles"`
+	}
+
+	Profile struct {
+		URL      string `yaml:"url"`
+		Username string `yaml:"username"`
+		// Token is looked up in .netrc if omitted.
+		Token string `yaml:"token"`
+		// Project is a default project key for tools to use.
+		Project string `yaml:"project"`
+		// Insecure disables verification of server certificate, see
+		// WithInsecureTLS.
+		Insecure bool `yaml:"insecure"`
+	}
+)
+
+// LoadConfig reads YAML config file.
+func LoadConfig(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var config Config
+	err = yaml.Unmarshal(data, &config)
+	if err != nil {
+		return Config{}, karma.Format(err, "unable to decode config %s", path)
+	}
+
+	return config, nil
+}
+
+// Profile returns profile with given name or default one if name is empty.
+func (config Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = config.Default
+	}
+
+	if name == "" && len(config.Profiles) == 1 {
+		for _, profile := range config.Profiles {
+			return profile, nil
+		}
+	}
+
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q is not found in config", name)
+	}
+
+	return profile, nil
+}
+
+// NewClientFromConfig creates client using profile from config file. Empty
+// profile name selects default profile. Profile is returned as well, so
+// tools can use its default project.
+func NewClientFromConfig(
+	path, profileName string,
+	options ...Option,
+) (Stash, Profile, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, Profile{}, err
+	}
+
+	profile, err := config.Profile(profileName)
+	if err != nil {
+		return nil, Profile{}, err
+	}
+
+	baseURL, err := url.Parse(profile.URL)
+	if err != nil {
+		return nil, Profile{}, karma.Format(
+			err, "unable to parse profile URL: %s", profile.URL,
+		)
+	}
+
+	if profile.Token == "" {
+		path, err := netrcPath()
+		if err != nil {
+			return nil, Profile{}, err
+		}
+
+		login, password, err := readNetrc(path, baseURL.Hostname())
+		if err != nil && !os.IsNotExist(err) {
+			return nil, Profile{}, karma.Format(err, "unable to read %s", path)
+		}
+
+		if profile.Username == "" {
+			profile.Username = login
+		}
+
+		if profile.Username == login {
+			profile.Token = password
+		}
+	}
+
+	if profile.Insecure {
+		options = append([]Option{WithInsecureTLS()}, options...)
+	}
+
+	return NewClient(profile.Username, profile.Token, baseURL, options...),
+		profile, nil
+}
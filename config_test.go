@@ -0,0 +1,53 @@
+package stash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const config = `
+default: work
+profiles:
+  work:
+    url: https://stash.example.com
+    username: bob
+    token: secret
+    project: PRJ
+  home:
+    url: https://git.example.org
+    username: bob
+    token: other
+`
+
+func TestNewClientFromConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stash.yaml")
+	if err := os.WriteFile(path, []byte(config), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	stashClient, profile, err := NewClientFromConfig(path, "")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if profile.Project != "PRJ" {
+		t.Fatalf("Want PRJ but got %s\n", profile.Project)
+	}
+
+	client := stashClient.(Client)
+	if client.baseURL.Host != "stash.example.com" || client.password != "secret" {
+		t.Fatalf("Unexpected client %+v\n", client)
+	}
+
+	stashClient, _, err = NewClientFromConfig(path, "home")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if stashClient.(Client).baseURL.Host != "git.example.org" {
+		t.Fatalf("Want git.example.org but got %s\n", stashClient.(Client).baseURL.Host)
+	}
+
+	if _, _, err := NewClientFromConfig(path, "missing"); err == nil {
+		t.Fatalf("Want error for missing profile but got none\n")
+	}
+}
@@ -0,0 +1,43 @@
+package stash
+
+import (
+	"context"
+	"time"
+)
+
+// WithContext returns copy of the client which sends all requests with ctx,
+// so long listings can be cancelled and single calls can have deadlines:
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+//	defer cancel()
+//
+//	repositories, err := client.WithContext(ctx).ListRepositories()
+//
+// Listings stop at the first page which fails because ctx is done.
+func (client Client) WithContext(ctx context.Context) Stash {
+	client.ctx = ctx
+	return client
+}
+
+// requestContext returns context requests are sent with.
+func (client Client) requestContext() context.Context {
+	if client.ctx == nil {
+		return context.Background()
+	}
+
+	return client.ctx
+}
+
+// sleep pauses polling loops, returning early with context error if
+// context of the client is done.
+func (client Client) sleep(duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-client.requestContext().Done():
+		return client.requestContext().Err()
+	}
+}
@@ -0,0 +1,52 @@
+package stash
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithContext(t *testing.T) {
+	var pages int32
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&pages, 1) > 1 {
+			// the second page never arrives in time
+			select {
+			case <-r.Context().Done():
+			case <-time.After(5 * time.Second):
+			}
+			return
+		}
+
+		fmt.Fprint(w, `{"isLastPage": false, "nextPageStart": 1, "values": [{"slug": "widge"}]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	started := time.Now()
+	_, err := stashClient.WithContext(ctx).ListRepositories()
+	if err == nil || !strings.Contains(err.Error(), "context deadline exceeded") {
+		t.Fatalf("Want deadline error but got %v\n", err)
+	}
+
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("Want listing to stop at deadline but it took %s\n", elapsed)
+	}
+
+	// original client is not bound to the context
+	if stashClient.(Client).ctx != nil {
+		t.Fatalf("Want WithContext to return a copy\n")
+	}
+}
@@ -1,6 +1,7 @@
 package stash
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -261,7 +262,24 @@ func TestCreatePullRequest(t *testing.T) {
 	stashClient := NewClient("u", "p", url)
 
 	reviewers := []string{"bob", "bill"}
-	pullRequest, err := stashClient.CreatePullRequest("proj", "bar", "a title", "a description", "feature/file1", "develop", reviewers)
+	pullRequest, err := stashClient.CreatePullRequest(
+		"a title", "a description",
+		PullRequestRef{
+			Id: "feature/file1",
+			Repository: PullRequestRepository{
+				Slug:    "bar",
+				Project: PullRequestProject{Key: "proj"},
+			},
+		},
+		PullRequestRef{
+			Id: "develop",
+			Repository: PullRequestRepository{
+				Slug:    "bar",
+				Project: PullRequestProject{Key: "proj"},
+			},
+		},
+		reviewers,
+	)
 	if err != nil {
 		t.Fatalf("%v\n", err)
 	}
@@ -287,4 +305,96 @@ func TestCreatePullRequest(t *testing.T) {
 	if pullRequest.ToRef.DisplayID != "develop" {
 		t.Fatalf("Want develop but got %v\n", pullRequest.ToRef)
 	}
+	reviewer := pullRequest.Reviewers[0]
+	if reviewer.User.Slug != "bob" || reviewer.User.ID != 871 || !reviewer.User.Active {
+		t.Fatalf("Want active user bob with id 871 but got %+v\n", reviewer.User)
+	}
+	if reviewer.Role != ParticipantRoleReviewer {
+		t.Fatalf("Want REVIEWER but got %v\n", reviewer.Role)
+	}
+	if pullRequest.Author.Role != ParticipantRoleAuthor {
+		t.Fatalf("Want AUTHOR but got %v\n", pullRequest.Author.Role)
+	}
+}
+
+func TestCreatePullRequestWithOptions(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/1.0/application-properties" {
+			fmt.Fprint(w, `{"version": "8.18.0"}`)
+			return
+		}
+		if r.Method != "POST" {
+			t.Fatalf("wanted POST but found %s\n", r.Method)
+		}
+		url := *r.URL
+		if url.Path != "/rest/api/1.0/projects/proj/repos/bar/pull-requests" {
+			t.Fatalf("CreatePullRequestWithOptions() URL path expected to be /rest/api/1.0/projects/proj/repos/bar/pull-requests but found %s\n", url.Path)
+		}
+
+		var payload struct {
+			Draft        bool
+			FromRef      PullRequestRef
+			Reviewers    []Reviewer
+			Participants []Reviewer
+			Properties   map[string]any
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if !payload.Draft {
+			t.Fatalf("Want draft pull request but found %+v\n", payload)
+		}
+		if payload.FromRef.Repository.Project.Key != "~alice" {
+			t.Fatalf("Want fromRef in ~alice but found %+v\n", payload.FromRef)
+		}
+		if len(payload.Reviewers) != 2 || payload.Reviewers[1].User.Name != "bob" {
+			t.Fatalf("Want reviewers carol and bob but found %+v\n", payload.Reviewers)
+		}
+		if len(payload.Participants) != 1 ||
+			payload.Participants[0].Role != ParticipantRoleParticipant {
+			t.Fatalf("Want dave as participant but found %+v\n", payload.Participants)
+		}
+		if payload.Properties["labels"] == nil {
+			t.Fatalf("Want labels property but found %+v\n", payload.Properties)
+		}
+
+		w.WriteHeader(201)
+		fmt.Fprint(w, createPullRequestResponse)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	pullRequest, err := stashClient.CreatePullRequestWithOptions(CreatePullRequestOptions{
+		Title: "a title",
+		FromRef: PullRequestRef{
+			Id: "feature/file1",
+			Repository: PullRequestRepository{
+				Slug:    "bar",
+				Project: PullRequestProject{Key: "~alice"},
+			},
+		},
+		ToRef: PullRequestRef{
+			Id: "develop",
+			Repository: PullRequestRepository{
+				Slug:    "bar",
+				Project: PullRequestProject{Key: "proj"},
+			},
+		},
+		Reviewers: []string{"carol"},
+		Participants: []Reviewer{
+			{User: User{Name: "bob"}, Role: ParticipantRoleReviewer},
+			{User: User{Name: "dave"}, Role: ParticipantRoleParticipant},
+		},
+		Draft:      true,
+		Properties: map[string]any{"labels": []string{"backend"}},
+	})
+	if err != nil {
+		t.Fatalf("%v\n", err)
+	}
+
+	if pullRequest.ID != 2 {
+		t.Fatalf("Want 2 but got %v\n", pullRequest.ID)
+	}
 }
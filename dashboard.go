@@ -0,0 +1,60 @@
+package stash
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PullRequestSuggestion is a recent push of the current user to a branch
+// which has no pull request yet.
+type PullRequestSuggestion struct {
+	// ChangeTime is a time of the push in milliseconds, server spells
+	// the field this way.
+	ChangeTime int64 `json:"changeTme"`
+
+	Repository Repository `json:"repository"`
+	FromRef    Ref        `json:"fromRef"`
+
+	// ToRef is a branch pull request is suggested to target, usually
+	// the default branch.
+	ToRef Ref `json:"toRef"`
+
+	RefChange struct {
+		FromHash string `json:"fromHash"`
+		ToHash   string `json:"toHash"`
+		Type     string `json:"type"`
+	} `json:"refChange"`
+}
+
+// GetPullRequestSuggestions returns branches the current user pushed to
+// within changesSince and which have no pull requests. Zero changesSince
+// and limit use server defaults, which are 48 hours and 3 suggestions.
+func (client Client) GetPullRequestSuggestions(
+	changesSince time.Duration,
+	limit int,
+) ([]PullRequestSuggestion, error) {
+	query := url.Values{}
+	if changesSince > 0 {
+		query.Set("changesSince", strconv.Itoa(int(changesSince.Seconds())))
+	}
+
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var response Paged[PullRequestSuggestion]
+	err := client.requestJSON(
+		"GET",
+		"/rest/api/1.0/dashboard/pull-request-suggestions?"+query.Encode(),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Values, nil
+}
@@ -0,0 +1,48 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestGetPullRequestSuggestions(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/dashboard/pull-request-suggestions" {
+			t.Fatalf("Want /rest/api/1.0/dashboard/pull-request-suggestions but found %s\n", r.URL.Path)
+		}
+		params := r.URL.Query()
+		if params.Get("changesSince") != "3600" || params.Get("limit") != "2" {
+			t.Fatalf("Want changesSince=3600&limit=2 but found %s\n", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"isLastPage": true, "values": [{
+			"changeTme": 1700000000000,
+			"repository": {"slug": "widge", "project": {"key": "PRJ"}},
+			"fromRef": {"id": "refs/heads/feature", "displayId": "feature"},
+			"toRef": {"id": "refs/heads/master", "displayId": "master"},
+			"refChange": {"fromHash": "aaa", "toHash": "bbb", "type": "UPDATE"}
+		}]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	suggestions, err := stashClient.GetPullRequestSuggestions(time.Hour, 2)
+	if err != nil {
+		t.Fatalf("GetPullRequestSuggestions() not expecting an error, but received: %v\n", err)
+	}
+	if len(suggestions) != 1 {
+		t.Fatalf("Want single suggestion but got %+v\n", suggestions)
+	}
+
+	suggestion := suggestions[0]
+	if suggestion.ChangeTime != 1700000000000 || suggestion.FromRef.DisplayID != "feature" ||
+		suggestion.ToRef.DisplayID != "master" || suggestion.Repository.Slug != "widge" ||
+		suggestion.RefChange.ToHash != "bbb" {
+		t.Fatalf("Unexpected suggestion %+v\n", suggestion)
+	}
+}
@@ -0,0 +1,32 @@
+package stash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestDeleteAddonLicense(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Fatalf("wanted DELETE but found %s\n", r.Method)
+		}
+		url := *r.URL
+		if url.Path != "/rest/plugins/1.0/com.example.addon-key/license" {
+			t.Fatalf("Want /rest/plugins/1.0/com.example.addon-key/license but found %s\n", url.Path)
+		}
+		if r.Header.Get("Authorization") != "Basic dTpw" {
+			t.Fatalf("Want Basic dTpw but found %s\n", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	err := stashClient.DeleteAddonLicense("com.example.addon")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+}
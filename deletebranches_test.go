@@ -0,0 +1,122 @@
+package stash
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestDeleteBranches(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Fatalf("wanted DELETE but found %s\n", r.Method)
+		}
+
+		var payload struct {
+			Name   string `json:"name"`
+			DryRun bool   `json:"dryRun"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if !payload.DryRun {
+			t.Fatalf("Want dry run but found %+v\n", payload)
+		}
+
+		if payload.Name == "refs/heads/protected" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"errors": [{"message": "Branch is protected.", "vetoes": [{"summaryMessage": "Branch restricted", "detailedMessage": "You do not have permission to delete protected."}]}]}`))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	results := stashClient.DeleteBranches(
+		"PROJ", "slug",
+		[]string{"issue/1", "protected", "issue/2"},
+		DeleteBranchesOptions{Concurrency: 2, DryRun: true},
+	)
+
+	if len(results) != 3 {
+		t.Fatalf("Want 3 results but got %d\n", len(results))
+	}
+	for i, name := range []string{"issue/1", "protected", "issue/2"} {
+		if results[i].Branch != name {
+			t.Fatalf("Want %s at position %d but got %s\n", name, i, results[i].Branch)
+		}
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Fatalf("Not expecting errors but got %v\n", results)
+	}
+	if results[1].Err == nil {
+		t.Fatalf("Want error for protected branch but got none\n")
+	}
+	if len(results[1].Vetoes) != 1 || results[1].Vetoes[0].SummaryMessage != "Branch restricted" {
+		t.Fatalf("Want veto for protected branch but got %+v\n", results[1].Vetoes)
+	}
+}
+
+func TestCreateBranches(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Fatalf("wanted POST but found %s\n", r.Method)
+		}
+
+		var payload struct {
+			Name       string `json:"name"`
+			StartPoint string `json:"startPoint"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if payload.StartPoint != "master" {
+			t.Fatalf("Want start point master but found %+v\n", payload)
+		}
+
+		if r.URL.Path == "/rest/api/1.0/projects/PROJ/repos/missing/branches" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors": [{"message": "Repository missing does not exist."}]}`))
+			return
+		}
+
+		json.NewEncoder(w).Encode(Branch{
+			ID:        "refs/heads/" + payload.Name,
+			DisplayID: payload.Name,
+		})
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	var specs []BranchSpec
+	for _, slug := range []string{"one", "missing", "two"} {
+		specs = append(specs, BranchSpec{
+			Repository: RepositoryRef{ProjectKey: "PROJ", Slug: slug},
+			Name:       "release/1.0",
+			StartPoint: "master",
+		})
+	}
+
+	results := stashClient.CreateBranches(specs, CreateBranchesOptions{Concurrency: 2})
+	if len(results) != 3 {
+		t.Fatalf("Want 3 results but got %d\n", len(results))
+	}
+	for i, slug := range []string{"one", "missing", "two"} {
+		if results[i].Spec.Repository.Slug != slug {
+			t.Fatalf("Want result %d for %s but got %+v\n", i, slug, results[i])
+		}
+	}
+	if results[0].Err != nil || results[0].Branch.ID != "refs/heads/release/1.0" {
+		t.Fatalf("Unexpected result %+v\n", results[0])
+	}
+	if results[1].Err == nil {
+		t.Fatalf("Want error for missing repository\n")
+	}
+}
@@ -0,0 +1,234 @@
+// Package diff parses unified diffs, e.g. returned by raw diff endpoints,
+// into files, hunks and lines annotated with old and new line numbers.
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// LineType values match line types used by Bitbucket in comment anchors.
+type LineType string
+
+const (
+	LineContext LineType = "CONTEXT"
+	LineAdded   LineType = "ADDED"
+	LineRemoved LineType = "REMOVED"
+)
+
+type (
+	File struct {
+		// OldPath and NewPath are empty for added and deleted files
+		// respectively.
+		OldPath string
+		NewPath string
+
+		IsNew     bool
+		IsDeleted bool
+		IsRename  bool
+		IsBinary  bool
+
+		Hunks []Hunk
+	}
+
+	Hunk struct {
+		OldStart int
+		OldLines int
+		NewStart int
+		NewLines int
+		// Section is a text after hunk range, usually a function name.
+		Section string
+
+		Lines []Line
+	}
+
+	// Line is a single line of hunk. OldNumber is zero for added lines and
+	// NewNumber is zero for removed lines.
+	Line struct {
+		Type      LineType
+		Content   string
+		OldNumber int
+		NewNumber int
+	}
+)
+
+var hunkHeader = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+// Parse reads unified diff consisting of one or more files.
+func Parse(reader io.Reader) ([]File, error) {
+	var (
+		files   []File
+		file    *File
+		hunk    *Hunk
+		oldLeft int
+		newLeft int
+		oldLine int
+		newLine int
+		number  int
+	)
+
+	startFile := func() {
+		files = append(files, File{})
+		file = &files[len(files)-1]
+		hunk = nil
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		number++
+		text := scanner.Text()
+
+		if hunk != nil && (oldLeft > 0 || newLeft > 0) {
+			line := Line{}
+			switch {
+			case strings.HasPrefix(text, "+"):
+				line.Type = LineAdded
+				line.NewNumber = newLine
+				newLine++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				line.Type = LineRemoved
+				line.OldNumber = oldLine
+				oldLine++
+				oldLeft--
+			case strings.HasPrefix(text, " "), text == "":
+				line.Type = LineContext
+				line.OldNumber = oldLine
+				line.NewNumber = newLine
+				oldLine++
+				newLine++
+				oldLeft--
+				newLeft--
+			case strings.HasPrefix(text, `\`):
+				// \ No newline at end of file
+				continue
+			default:
+				return nil, fmt.Errorf(
+					"line %d: unexpected line inside hunk: %q", number, text,
+				)
+			}
+
+			if len(text) > 0 {
+				line.Content = text[1:]
+			}
+
+			hunk.Lines = append(hunk.Lines, line)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			startFile()
+			paths := strings.SplitN(strings.TrimPrefix(text, "diff --git "), " b/", 2)
+			if len(paths) == 2 {
+				file.OldPath = strings.TrimPrefix(paths[0], "a/")
+				file.NewPath = paths[1]
+			}
+
+		case strings.HasPrefix(text, "--- "):
+			if file == nil || hunk != nil {
+				startFile()
+			}
+			file.OldPath = parsePath(text[4:], "a/")
+			if file.OldPath == "" {
+				file.IsNew = true
+			}
+
+		case strings.HasPrefix(text, "+++ ") && file != nil && hunk == nil:
+			file.NewPath = parsePath(text[4:], "b/")
+			if file.NewPath == "" {
+				file.IsDeleted = true
+			}
+
+		case strings.HasPrefix(text, "new file mode") && file != nil:
+			file.IsNew = true
+			file.OldPath = ""
+
+		case strings.HasPrefix(text, "deleted file mode") && file != nil:
+			file.IsDeleted = true
+			file.NewPath = ""
+
+		case strings.HasPrefix(text, "rename from ") && file != nil:
+			file.IsRename = true
+			file.OldPath = strings.TrimPrefix(text, "rename from ")
+
+		case strings.HasPrefix(text, "rename to ") && file != nil:
+			file.IsRename = true
+			file.NewPath = strings.TrimPrefix(text, "rename to ")
+
+		case strings.HasPrefix(text, "Binary files ") && file != nil:
+			file.IsBinary = true
+
+		case strings.HasPrefix(text, "@@ "):
+			if file == nil {
+				return nil, fmt.Errorf(
+					"line %d: hunk outside of file: %q", number, text,
+				)
+			}
+
+			header, err := parseHunkHeader(text)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", number, err)
+			}
+
+			file.Hunks = append(file.Hunks, header)
+			hunk = &file.Hunks[len(file.Hunks)-1]
+			oldLeft, newLeft = hunk.OldLines, hunk.NewLines
+			oldLine, newLine = hunk.OldStart, hunk.NewStart
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// ParseString parses unified diff stored in string.
+func ParseString(text string) ([]File, error) {
+	return Parse(strings.NewReader(text))
+}
+
+func parseHunkHeader(text string) (Hunk, error) {
+	matches := hunkHeader.FindStringSubmatch(text)
+	if matches == nil {
+		return Hunk{}, fmt.Errorf("invalid hunk header: %q", text)
+	}
+
+	number := func(value string) int {
+		// omitted count means a single line
+		if value == "" {
+			return 1
+		}
+
+		result, _ := strconv.Atoi(value)
+		return result
+	}
+
+	return Hunk{
+		OldStart: number(matches[1]),
+		OldLines: number(matches[2]),
+		NewStart: number(matches[3]),
+		NewLines: number(matches[4]),
+		Section:  matches[5],
+	}, nil
+}
+
+func parsePath(path, prefix string) string {
+	// strip timestamp which plain diff puts after tab
+	if index := strings.Index(path, "\t"); index >= 0 {
+		path = path[:index]
+	}
+
+	if path == "/dev/null" {
+		return ""
+	}
+
+	return strings.TrimPrefix(path, prefix)
+}
@@ -0,0 +1,95 @@
+package diff
+
+import "testing"
+
+const gitDiff = `diff --git a/README.md b/README.md
+index 3b18e51..a0b2c3d 100644
+--- a/README.md
++++ b/README.md
+@@ -1,4 +1,5 @@ Stash tools
+ Stash tools
+-===========
++=====
++
+ Go package of Stash tools.
+ 
+@@ -10 +11 @@
+-old
++new
+\ No newline at end of file
+diff --git a/new.go b/new.go
+new file mode 100644
+index 0000000..e69de29
+--- /dev/null
++++ b/new.go
+@@ -0,0 +1,2 @@
++package stash
++--- not a header
+diff --git a/logo.png b/logo.png
+deleted file mode 100644
+Binary files a/logo.png and /dev/null differ
+`
+
+func TestParse(t *testing.T) {
+	files, err := ParseString(gitDiff)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(files) != 3 {
+		t.Fatalf("Want 3 files but got %d\n", len(files))
+	}
+
+	readme := files[0]
+	if readme.OldPath != "README.md" || readme.NewPath != "README.md" {
+		t.Fatalf("Unexpected paths %+v\n", readme)
+	}
+	if len(readme.Hunks) != 2 {
+		t.Fatalf("Want 2 hunks but got %d\n", len(readme.Hunks))
+	}
+
+	hunk := readme.Hunks[0]
+	if hunk.Section != "Stash tools" || len(hunk.Lines) != 6 {
+		t.Fatalf("Unexpected hunk %+v\n", hunk)
+	}
+
+	removed := hunk.Lines[1]
+	if removed.Type != LineRemoved || removed.OldNumber != 2 || removed.NewNumber != 0 {
+		t.Fatalf("Unexpected removed line %+v\n", removed)
+	}
+
+	added := hunk.Lines[3]
+	if added.Type != LineAdded || added.NewNumber != 3 || added.Content != "" {
+		t.Fatalf("Unexpected added line %+v\n", added)
+	}
+
+	context := hunk.Lines[5]
+	if context.Type != LineContext || context.OldNumber != 4 || context.NewNumber != 5 {
+		t.Fatalf("Unexpected context line %+v\n", context)
+	}
+
+	single := readme.Hunks[1]
+	if single.OldStart != 10 || single.OldLines != 1 || len(single.Lines) != 2 {
+		t.Fatalf("Unexpected hunk %+v\n", single)
+	}
+
+	created := files[1]
+	if !created.IsNew || created.OldPath != "" || created.NewPath != "new.go" {
+		t.Fatalf("Unexpected new file %+v\n", created)
+	}
+	if created.Hunks[0].Lines[1].Content != "--- not a header" {
+		t.Fatalf("Unexpected line %+v\n", created.Hunks[0].Lines[1])
+	}
+
+	deleted := files[2]
+	if !deleted.IsDeleted || !deleted.IsBinary || deleted.OldPath != "logo.png" {
+		t.Fatalf("Unexpected deleted file %+v\n", deleted)
+	}
+}
+
+func TestParseInvalidHunk(t *testing.T) {
+	_, err := ParseString("--- a/x\n+++ b/x\n@@ -1,2 +1,2 @@\n+one\nbroken\n")
+	if err == nil {
+		t.Fatalf("Want error but got none\n")
+	}
+}
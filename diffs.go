@@ -0,0 +1,229 @@
+package stash
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type (
+	// DiffOptions tunes diffs returned by the server.
+	DiffOptions struct {
+		// Path limits diff to a single file.
+		Path string
+		// SrcPath is a previous path of the file, required to diff renamed
+		// files.
+		SrcPath string
+		// ContextLines is a number of context lines around changes, server
+		// default is used when it's zero.
+		ContextLines int
+		// IgnoreWhitespace ignores all whitespace changes.
+		IgnoreWhitespace bool
+		// WithComments includes comments anchored to the diff.
+		WithComments bool
+	}
+
+	Diff struct {
+		FromHash     string     `json:"fromHash"`
+		ToHash       string     `json:"toHash"`
+		ContextLines int        `json:"contextLines"`
+		Whitespace   string     `json:"whitespace"`
+		Diffs        []FileDiff `json:"diffs"`
+		Truncated    bool       `json:"truncated"`
+	}
+
+	FileDiff struct {
+		// Source is nil for added files and Destination is nil for deleted
+		// ones.
+		Source      *DiffPath  `json:"source"`
+		Destination *DiffPath  `json:"destination"`
+		Hunks       []DiffHunk `json:"hunks"`
+		Binary      bool       `json:"binary"`
+		Truncated   bool       `json:"truncated"`
+	}
+
+	DiffPath struct {
+		Components []string `json:"components"`
+		Parent     string   `json:"parent"`
+		Name       string   `json:"name"`
+		Extension  string   `json:"extension"`
+		ToString   string   `json:"toString"`
+	}
+
+	DiffHunk struct {
+		SourceLine      int           `json:"sourceLine"`
+		SourceSpan      int           `json:"sourceSpan"`
+		DestinationLine int           `json:"destinationLine"`
+		DestinationSpan int           `json:"destinationSpan"`
+		Context         string        `json:"context"`
+		Segments        []DiffSegment `json:"segments"`
+		Truncated       bool          `json:"truncated"`
+	}
+
+	// DiffSegment is a group of lines of the same type: ADDED, REMOVED or
+	// CONTEXT.
+	DiffSegment struct {
+		Type      string     `json:"type"`
+		Lines     []DiffLine `json:"lines"`
+		Truncated bool       `json:"truncated"`
+	}
+
+	DiffLine struct {
+		Source      int    `json:"source"`
+		Destination int    `json:"destination"`
+		Line        string `json:"line"`
+		Truncated   bool   `json:"truncated"`
+		// CommentIDs is filled only when requested WithComments.
+		CommentIDs []int `json:"commentIds"`
+	}
+)
+
+// GetPullRequestDiff returns structured diff of the pull request.
+func (client Client) GetPullRequestDiff(
+	projectKey, repositorySlug, identifier string,
+	options DiffOptions,
+) (Diff, error) {
+	var response Diff
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/diff%s",
+			projectKey, repositorySlug, identifier, rawPath(options.path()),
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	return response, nil
+}
+
+// WritePullRequestPatch streams changes of the pull request to writer in
+// the given format, which applies with git apply for PatchFormatDiff or
+// git am for PatchFormatPatch. Requires Bitbucket 7.x or newer.
+func (client Client) WritePullRequestPatch(
+	projectKey, repositorySlug, identifier string,
+	format PatchFormat,
+	writer io.Writer,
+) error {
+	err := client.requireVersion("pull request patches", 7, 0)
+	if err != nil {
+		return err
+	}
+
+	return client.requestStream(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s.%s",
+			projectKey, repositorySlug, identifier, format,
+		),
+		nil,
+		writer,
+		http.StatusOK,
+	)
+}
+
+// GetCommitDiff returns structured diff of changes made by the commit
+// against its first parent.
+func (client Client) GetCommitDiff(
+	projectKey, repositorySlug, commitHash string,
+	options DiffOptions,
+) (Diff, error) {
+	var response Diff
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/commits/%s/diff%s",
+			projectKey, repositorySlug, commitHash, rawPath(options.path()),
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	return response, nil
+}
+
+// WriteCommitDiff streams changes made by the commit to writer as a raw
+// unified diff. WithComments option is ignored.
+func (client Client) WriteCommitDiff(
+	projectKey, repositorySlug, commitHash string,
+	options DiffOptions,
+	writer io.Writer,
+) error {
+	values := options.values()
+	values.Del("withComments")
+	values.Set("until", commitHash)
+
+	request, err := client.getRequest(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/diff%s?%s",
+			projectKey, repositorySlug,
+			rawPath(options.filePath()), rawPath(values.Encode()),
+		),
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Accept", "text/plain")
+
+	return client.sendStream(request, writer, http.StatusOK)
+}
+
+// path returns diff URL suffix with options applied.
+func (options DiffOptions) path() string {
+	return options.filePath() + "?" + options.values().Encode()
+}
+
+// filePath returns diff URL suffix limiting it to the file, if any.
+func (options DiffOptions) filePath() string {
+	if options.Path == "" {
+		return ""
+	}
+
+	return "/" + escapeFilePath(options.Path)
+}
+
+func (options DiffOptions) values() url.Values {
+	values := url.Values{}
+
+	if options.SrcPath != "" {
+		values.Set("srcPath", options.SrcPath)
+	}
+
+	if options.ContextLines > 0 {
+		values.Set("contextLines", strconv.Itoa(options.ContextLines))
+	}
+
+	if options.IgnoreWhitespace {
+		values.Set("whitespace", "ignore-all")
+	}
+
+	values.Set("withComments", strconv.FormatBool(options.WithComments))
+
+	return values
+}
+
+// Path returns the path of the changed file, which is the new path unless
+// file is deleted.
+func (diff FileDiff) Path() string {
+	if diff.Destination != nil {
+		return diff.Destination.ToString
+	}
+
+	if diff.Source != nil {
+		return diff.Source.ToString
+	}
+
+	return ""
+}
@@ -0,0 +1,232 @@
+package stash
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type (
+	// DownloadOptions configures DownloadRawFile and DownloadArchive.
+	DownloadOptions struct {
+		// At is a branch, tag or commit to download at, default branch is
+		// used if it's empty. Only commit is safe to resume at, since
+		// branches and tags may move between attempts.
+		At string
+
+		// Offset is amount of bytes already downloaded, e.g. size of the
+		// partial file, download continues from it. Writer is expected to
+		// be positioned at Offset, e.g. file opened for appending.
+		Offset int64
+
+		// Progress is called after each chunk is written.
+		Progress func(DownloadProgress)
+	}
+
+	DownloadProgress struct {
+		// Written is amount of bytes downloaded so far, including Offset.
+		Written int64
+
+		// Total is size of the whole download, or -1 if server doesn't
+		// report it, e.g. for archives generated on the fly.
+		Total int64
+	}
+)
+
+// DownloadRawFile writes contents of the file to writer and returns amount
+// of bytes downloaded including options.Offset. Range request is used to
+// resume download, if server ignores it already downloaded bytes are
+// skipped. On failure returned amount can be passed as Offset to retry.
+func (client Client) DownloadRawFile(
+	projectKey, repositorySlug, filePath string,
+	options DownloadOptions,
+	writer io.Writer,
+) (int64, error) {
+	return client.download(
+		rawFilePath(projectKey, repositorySlug, filePath, options.At),
+		options,
+		writer,
+	)
+}
+
+// DownloadArchive writes archive of the repository to writer, resuming
+// works as for DownloadRawFile. Archives are generated on the fly, so
+// resuming is only reliable when options.At is a commit.
+func (client Client) DownloadArchive(
+	projectKey, repositorySlug string,
+	format ArchiveFormat,
+	options DownloadOptions,
+	writer io.Writer,
+) (int64, error) {
+	query := url.Values{}
+	if options.At != "" {
+		query.Set("at", options.At)
+	}
+
+	if format != "" {
+		query.Set("format", string(format))
+	}
+
+	return client.download(
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/archive?%s",
+			projectKey, repositorySlug, rawPath(query.Encode()),
+		),
+		options,
+		writer,
+	)
+}
+
+func (client Client) download(
+	path string,
+	options DownloadOptions,
+	writer io.Writer,
+) (int64, error) {
+	request, err := client.getRequest("GET", path, nil)
+	if err != nil {
+		return options.Offset, err
+	}
+
+	if options.Offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", options.Offset))
+	}
+
+	written := options.Offset
+	err = client.sendDecode(
+		request,
+		func(response *http.Response) error {
+			total := response.ContentLength
+			if total >= 0 {
+				total += options.Offset
+			}
+
+			contentRange := response.Header.Get("Content-Range")
+
+			switch response.StatusCode {
+			case http.StatusPartialContent:
+				start, size, ok := parseContentRange(contentRange)
+				if !ok || start != options.Offset {
+					return responseContext(response).
+						Describe("content-range", contentRange).
+						Reason("unexpected range in response")
+				}
+
+				total = size
+
+			case http.StatusRequestedRangeNotSatisfiable:
+				_, size, ok := parseContentRange(contentRange)
+				if !ok || size != options.Offset {
+					return responseContext(response).
+						Describe("content-range", contentRange).
+						Describe("offset", options.Offset).
+						Reason("offset is beyond the end of the file")
+				}
+
+				// everything is downloaded already
+				return nil
+
+			default:
+				if options.Offset > 0 {
+					// server ignored Range, so the whole body is sent
+					total = response.ContentLength
+
+					_, err := io.CopyN(io.Discard, response.Body, options.Offset)
+					if err != nil {
+						return responseContext(response).Format(
+							err,
+							"skip downloaded bytes",
+						)
+					}
+				}
+			}
+
+			progress := &progressWriter{
+				writer:   writer,
+				written:  options.Offset,
+				total:    total,
+				progress: options.Progress,
+			}
+
+			_, err := io.Copy(progress, response.Body)
+			written = progress.written
+			if err != nil {
+				return responseContext(response).Format(
+					err,
+					"copy response body",
+				)
+			}
+
+			return nil
+		},
+		http.StatusOK,
+		http.StatusPartialContent,
+		http.StatusRequestedRangeNotSatisfiable,
+	)
+
+	return written, err
+}
+
+// progressWriter counts bytes written and reports them to progress.
+type progressWriter struct {
+	writer   io.Writer
+	written  int64
+	total    int64
+	progress func(DownloadProgress)
+}
+
+func (writer *progressWriter) Write(data []byte) (int, error) {
+	n, err := writer.writer.Write(data)
+	writer.written += int64(n)
+
+	if writer.progress != nil && n > 0 {
+		writer.progress(DownloadProgress{
+			Written: writer.written,
+			Total:   writer.total,
+		})
+	}
+
+	return n, err
+}
+
+// parseContentRange parses Content-Range header like "bytes 10-99/100" or
+// "bytes */100", returning -1 for unknown start or size.
+func parseContentRange(header string) (start, size int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+
+	span, length, found := strings.Cut(spec, "/")
+	if !found {
+		return 0, 0, false
+	}
+
+	size = -1
+	if length != "*" {
+		parsed, err := strconv.ParseInt(length, 10, 64)
+		if err != nil {
+			return 0, 0, false
+		}
+
+		size = parsed
+	}
+
+	if span == "*" {
+		return -1, size, true
+	}
+
+	first, _, found := strings.Cut(span, "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	return start, size, true
+}
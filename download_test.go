@@ -0,0 +1,105 @@
+package stash
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDownloadRawFile(t *testing.T) {
+	content := "0123456789abcdef"
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantPath := "/rest/api/1.0/projects/PRJ/repos/widge/raw/assets/big.bin"
+		if r.URL.Path != wantPath {
+			t.Fatalf("Want %s but found %s\n", wantPath, r.URL.Path)
+		}
+
+		if r.URL.Query().Get("at") != "cafebabe" {
+			t.Fatalf("Want at=cafebabe but found %s\n", r.URL.RawQuery)
+		}
+
+		http.ServeContent(w, r, "big.bin", time.Time{}, strings.NewReader(content))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	var (
+		buffer   bytes.Buffer
+		progress []DownloadProgress
+	)
+
+	buffer.WriteString(content[:10])
+
+	written, err := stashClient.DownloadRawFile(
+		"PRJ", "widge", "assets/big.bin",
+		DownloadOptions{
+			At:     "cafebabe",
+			Offset: 10,
+			Progress: func(report DownloadProgress) {
+				progress = append(progress, report)
+			},
+		},
+		&buffer,
+	)
+	if err != nil {
+		t.Fatalf("Want no error but got %s\n", err)
+	}
+
+	if written != 16 || buffer.String() != content {
+		t.Fatalf("Want 16 bytes %q but got %d bytes %q\n", content, written, buffer.String())
+	}
+
+	if len(progress) == 0 || progress[len(progress)-1] != (DownloadProgress{16, 16}) {
+		t.Fatalf("Want final progress 16/16 but got %v\n", progress)
+	}
+
+	// file is already complete, server replies with 416
+	written, err = stashClient.DownloadRawFile(
+		"PRJ", "widge", "assets/big.bin",
+		DownloadOptions{At: "cafebabe", Offset: 16},
+		&buffer,
+	)
+	if err != nil || written != 16 || buffer.String() != content {
+		t.Fatalf("Want complete download to be no-op but got %d bytes, %v\n", written, err)
+	}
+}
+
+func TestDownloadArchiveIgnoredRange(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "bytes=4-" {
+			t.Fatalf("Want Range bytes=4- but found %s\n", r.Header.Get("Range"))
+		}
+
+		if r.URL.Query().Get("format") != "zip" {
+			t.Fatalf("Want format=zip but found %s\n", r.URL.RawQuery)
+		}
+
+		// archives are streamed, Range is ignored
+		w.Write([]byte("PK\x03\x04rest"))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	var buffer bytes.Buffer
+	written, err := stashClient.DownloadArchive(
+		"PRJ", "widge", ArchiveFormatZip,
+		DownloadOptions{Offset: 4},
+		&buffer,
+	)
+	if err != nil {
+		t.Fatalf("Want no error but got %s\n", err)
+	}
+
+	if written != 8 || buffer.String() != "rest" {
+		t.Fatalf("Want skipped downloaded bytes but got %d bytes %q\n", written, buffer.String())
+	}
+}
@@ -0,0 +1,79 @@
+package stash
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+type (
+	// DryRunRecorder collects mutating requests which client configured
+	// with WithDryRun would send.
+	DryRunRecorder struct {
+		mutex    sync.Mutex
+		requests []RecordedRequest
+	}
+
+	// RecordedRequest is a request which would be sent. Sensitive query
+	// parameters like UPM token are redacted from URL.
+	RecordedRequest struct {
+		Method  string
+		URL     string
+		Payload []byte
+	}
+)
+
+// WithDryRun makes client record mutating requests into recorder instead of
+// sending them. Such calls return zero values and no error, while read-only
+// requests are still sent, so a plan of changes can be built on top of
+// real server state.
+func WithDryRun(recorder *DryRunRecorder) Option {
+	return func(client *Client) {
+		client.dryRun = recorder
+	}
+}
+
+// Requests returns requests recorded so far in the order they were made.
+func (recorder *DryRunRecorder) Requests() []RecordedRequest {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return append([]RecordedRequest(nil), recorder.requests...)
+}
+
+func (recorder *DryRunRecorder) record(method, url string, payload []byte) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.requests = append(recorder.requests, RecordedRequest{
+		Method:  method,
+		URL:     url,
+		Payload: payload,
+	})
+}
+
+// skipDryRun records the request and reports whether it should not be sent.
+func (client Client) skipDryRun(request *http.Request) bool {
+	if client.dryRun == nil {
+		return false
+	}
+
+	switch request.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return false
+	}
+
+	var payload []byte
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err == nil {
+			payload, _ = ioutil.ReadAll(body)
+		}
+	}
+
+	client.dryRun.record(
+		request.Method, redactURL(request.URL.String()), payload,
+	)
+
+	return true
+}
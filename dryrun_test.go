@@ -0,0 +1,77 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("Not expecting %s request in dry run mode\n", r.Method)
+		}
+		fmt.Fprint(w, `{"id": 1, "slug": "widge"}`)
+	}))
+	defer testServer.Close()
+
+	recorder := &DryRunRecorder{}
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithDryRun(recorder))
+
+	repository, err := stashClient.GetRepository("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if repository.Slug != "widge" {
+		t.Fatalf("Want widge but got %s\n", repository.Slug)
+	}
+
+	repository, err = stashClient.CreateRepository("PRJ", "gadget")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if repository.Slug != "" {
+		t.Fatalf("Want zero repository but got %+v\n", repository)
+	}
+
+	err = stashClient.DeleteBranch("PRJ", "widge", "issue/1")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	requests := recorder.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("Want 2 recorded requests but got %d\n", len(requests))
+	}
+	if requests[0].Method != "POST" || requests[0].URL != testServer.URL+"/rest/api/1.0/projects/PRJ/repos" {
+		t.Fatalf("Unexpected recorded request %+v\n", requests[0])
+	}
+	if string(requests[0].Payload) != `{"name":"gadget","scmId":"git"}` {
+		t.Fatalf("Unexpected recorded payload %s\n", requests[0].Payload)
+	}
+	if requests[1].Method != "DELETE" {
+		t.Fatalf("Want DELETE but got %s\n", requests[1].Method)
+	}
+}
+
+func TestDryRunInstallAddonRedactsToken(t *testing.T) {
+	recorder := &DryRunRecorder{}
+
+	url, _ := url.Parse("http://stash.local")
+	stashClient := NewClient("u", "p", url, WithDryRun(recorder))
+
+	_, err := stashClient.InstallAddon("s3cr3t-upm", "addon.jar")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	requests := recorder.Requests()
+	if len(requests) != 1 || strings.Contains(requests[0].URL, "s3cr3t-upm") {
+		t.Fatalf("Want UPM token redacted but got %+v\n", requests)
+	}
+}
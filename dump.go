@@ -0,0 +1,87 @@
+package stash
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"strings"
+)
+
+var (
+	reSensitiveHeader = regexp.MustCompile(
+		`(?im)^((?:Authorization|Proxy-Authorization|Cookie|Set-Cookie|Upm-Token):).*$`,
+	)
+
+	reSensitiveQuery = regexp.MustCompile(
+		`(?i)([?&](?:` + strings.Join(quoteKeys(sensitiveKeys), "|") + `)=)[^&\s]*`,
+	)
+)
+
+// WithHTTPDump makes client write requests and responses in wire format to
+// Log, with credentials, tokens and licenses redacted. Only requests for
+// which dump returns true are written, nil dump writes all of them. Bodies
+// are written only when they are already in memory or look like text, so
+// addon uploads and binary downloads are not buffered.
+func WithHTTPDump(dump func(request *http.Request) bool) Option {
+	return func(client *Client) {
+		if dump == nil {
+			dump = func(*http.Request) bool { return true }
+		}
+
+		client.dump = dump
+	}
+}
+
+// roundTrip sends single request, dumping it if needed.
+func (client Client) roundTrip(request *http.Request) (*http.Response, error) {
+	if !client.dumpRequest(request) {
+		return client.httpClient().Do(request)
+	}
+
+	response, err := client.httpClient().Do(request)
+	if err != nil {
+		Log.Printf("request failed: %s", redactError(err))
+		return nil, err
+	}
+
+	dumpResponse(response)
+
+	return response, nil
+}
+
+func (client Client) dumpRequest(request *http.Request) bool {
+	if client.dump == nil || !client.dump(request) {
+		return false
+	}
+
+	data, err := httputil.DumpRequestOut(request, request.GetBody != nil)
+	if err != nil {
+		Log.Printf("unable to dump request: %s", redactError(err))
+		return true
+	}
+
+	Log.Printf("request:\n%s", client.redactAuthHeaders(redactDump(data)))
+
+	return true
+}
+
+func dumpResponse(response *http.Response) {
+	contentType := response.Header.Get("Content-Type")
+	body := strings.Contains(contentType, "json") ||
+		strings.HasPrefix(contentType, "text/")
+
+	data, err := httputil.DumpResponse(response, body)
+	if err != nil {
+		Log.Printf("unable to dump response: %s", err)
+		return
+	}
+
+	Log.Printf("response:\n%s", redactDump(data))
+}
+
+func redactDump(data []byte) string {
+	dump := reSensitiveHeader.ReplaceAllString(string(data), "$1 "+redacted)
+	dump = reSensitiveQuery.ReplaceAllString(dump, "${1}"+redacted)
+
+	return redactBody([]byte(dump))
+}
@@ -0,0 +1,88 @@
+package stash
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWithHTTPDump(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"rawLicense": "OLD-LICENSE"}`)
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"rawLicense": "SERVER-LICENSE", "valid": false}`)
+	}))
+	defer testServer.Close()
+
+	var output bytes.Buffer
+	defer func(logger *log.Logger) {
+		Log = logger
+	}(Log)
+	Log = log.New(&output, "", 0)
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithHTTPDump(
+		func(request *http.Request) bool {
+			return request.Method == "PUT"
+		},
+	))
+
+	stashClient.SetAddonLicense("addon", "CLIENT-LICENSE")
+
+	dump := output.String()
+	if strings.Count(dump, "request:\n") != 1 {
+		t.Fatalf("Want only PUT request dumped but got:\n%s\n", dump)
+	}
+	if !strings.Contains(dump, "PUT /rest/plugins/1.0/addon-key/license") {
+		t.Fatalf("Want request line in dump but got:\n%s\n", dump)
+	}
+	if !strings.Contains(dump, "400 Bad Request") {
+		t.Fatalf("Want response status in dump but got:\n%s\n", dump)
+	}
+	for _, secret := range []string{"LICENSE", "Basic dTpw"} {
+		if strings.Contains(dump, secret) {
+			t.Fatalf("Want %s redacted but got:\n%s\n", secret, dump)
+		}
+	}
+}
+
+func TestRedactDumpQuery(t *testing.T) {
+	dump := redactDump([]byte("POST /rest/plugins/1.0/?token=abc&x=1 HTTP/1.1\r\n"))
+	if dump != "POST /rest/plugins/1.0/?token=REDACTED&x=1 HTTP/1.1\r\n" {
+		t.Fatalf("Want token redacted but got %q\n", dump)
+	}
+}
+
+func TestHTTPDumpRedactsUPMToken(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("upm-token", "UPM-SECRET")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	var output bytes.Buffer
+	defer func(logger *log.Logger) {
+		Log = logger
+	}(Log)
+	Log = log.New(&output, "", 0)
+
+	url, _ := url.Parse(testServer.URL)
+	token, err := NewClient("u", "p", url, WithHTTPDump(nil)).GetUPMToken()
+	if err != nil || token != "UPM-SECRET" {
+		t.Fatalf("Want UPM token but got %q, %v\n", token, err)
+	}
+
+	dump := output.String()
+	if strings.Contains(dump, "UPM-SECRET") || !strings.Contains(dump, "Upm-Token: REDACTED") {
+		t.Fatalf("Want UPM token redacted but got:\n%s\n", dump)
+	}
+}
@@ -0,0 +1,277 @@
+package stash
+
+type (
+	// PullRequestState is a state of pull request, also used to filter
+	// pull requests by GetPullRequests.
+	PullRequestState string
+
+	// ParticipantRole is a role of user participating in pull request.
+	ParticipantRole string
+
+	// ParticipantStatus is a review status set by pull request participant.
+	ParticipantStatus string
+
+	// Permission is a global, project or repository permission level.
+	Permission string
+
+	// MergeStrategy is an identifier of pull request merge strategy.
+	MergeStrategy string
+
+	// BranchTypeID is a type of branch in branching model.
+	BranchTypeID string
+
+	// MergeOutcome is a result of trial merge of pull request.
+	MergeOutcome string
+
+	// ChangeType is a type of change made to a file by commit.
+	ChangeType string
+
+	// RefRestrictionType is a kind of restriction applied to matching refs.
+	RefRestrictionType string
+
+	// RefMatcherKind tells how RefMatcher ID is matched against refs.
+	RefMatcherKind string
+
+	// WebhookEvent is an event which triggers webhook.
+	WebhookEvent string
+
+	// WebhookScope is a level at which webhook is defined.
+	WebhookScope string
+
+	// WebhookOutcome is a result of webhook delivery.
+	WebhookOutcome string
+
+	// PatchFormat is a format pull request changes are exported in.
+	PatchFormat string
+
+	// ArchiveFormat is a format repository archive is downloaded in.
+	ArchiveFormat string
+
+	// ActivityAction is a kind of pull request activity.
+	ActivityAction string
+
+	// CommentAction tells what happened to comment in COMMENTED activity.
+	CommentAction string
+
+	// PermissionScope is a level at which permission is granted.
+	PermissionScope string
+
+	// ChangeAction is a kind of change planned for a repository resource.
+	ChangeAction string
+
+	// TaskState is a state of pull request task.
+	TaskState string
+
+	// CommentLineType is a type of diff line inline comment is placed on.
+	CommentLineType string
+
+	// CommentFileType tells which side of diff inline comment is placed on.
+	CommentFileType string
+
+	// RefSyncState tells why fork ref can't be synchronized automatically.
+	RefSyncState string
+
+	// RefSyncAction is a way to synchronize the fork ref, see SynchronizeRef.
+	RefSyncAction string
+)
+
+const (
+	PullRequestStateOpen     PullRequestState = "OPEN"
+	PullRequestStateMerged   PullRequestState = "MERGED"
+	PullRequestStateDeclined PullRequestState = "DECLINED"
+	// PullRequestStateAll is only valid as a filter.
+	PullRequestStateAll PullRequestState = "ALL"
+)
+
+const (
+	ParticipantRoleAuthor      ParticipantRole = "AUTHOR"
+	ParticipantRoleReviewer    ParticipantRole = "REVIEWER"
+	ParticipantRoleParticipant ParticipantRole = "PARTICIPANT"
+)
+
+const (
+	ParticipantStatusApproved   ParticipantStatus = "APPROVED"
+	ParticipantStatusUnapproved ParticipantStatus = "UNAPPROVED"
+	ParticipantStatusNeedsWork  ParticipantStatus = "NEEDS_WORK"
+)
+
+const (
+	PermissionLicensedUser  Permission = "LICENSED_USER"
+	PermissionProjectCreate Permission = "PROJECT_CREATE"
+	PermissionAdmin         Permission = "ADMIN"
+	PermissionSysAdmin      Permission = "SYS_ADMIN"
+
+	PermissionProjectView  Permission = "PROJECT_VIEW"
+	PermissionProjectRead  Permission = "PROJECT_READ"
+	PermissionProjectWrite Permission = "PROJECT_WRITE"
+	PermissionProjectAdmin Permission = "PROJECT_ADMIN"
+
+	PermissionRepoRead  Permission = "REPO_READ"
+	PermissionRepoWrite Permission = "REPO_WRITE"
+	PermissionRepoAdmin Permission = "REPO_ADMIN"
+)
+
+const (
+	MergeStrategyNoFastForward       MergeStrategy = "no-ff"
+	MergeStrategyFastForward         MergeStrategy = "ff"
+	MergeStrategyFastForwardOnly     MergeStrategy = "ff-only"
+	MergeStrategyRebaseNoFastForward MergeStrategy = "rebase-no-ff"
+	MergeStrategyRebaseFastForward   MergeStrategy = "rebase-ff-only"
+	MergeStrategySquash              MergeStrategy = "squash"
+	MergeStrategySquashFastForward   MergeStrategy = "squash-ff-only"
+)
+
+const (
+	BranchTypeBugfix  BranchTypeID = "BUGFIX"
+	BranchTypeFeature BranchTypeID = "FEATURE"
+	BranchTypeHotfix  BranchTypeID = "HOTFIX"
+	BranchTypeRelease BranchTypeID = "RELEASE"
+)
+
+const (
+	MergeOutcomeClean      MergeOutcome = "CLEAN"
+	MergeOutcomeConflicted MergeOutcome = "CONFLICTED"
+	MergeOutcomeUnknown    MergeOutcome = "UNKNOWN"
+)
+
+const (
+	ChangeTypeAdd     ChangeType = "ADD"
+	ChangeTypeCopy    ChangeType = "COPY"
+	ChangeTypeDelete  ChangeType = "DELETE"
+	ChangeTypeModify  ChangeType = "MODIFY"
+	ChangeTypeMove    ChangeType = "MOVE"
+	ChangeTypeUnknown ChangeType = "UNKNOWN"
+)
+
+const (
+	RefRestrictionReadOnly        RefRestrictionType = "read-only"
+	RefRestrictionNoDeletes       RefRestrictionType = "no-deletes"
+	RefRestrictionFastForwardOnly RefRestrictionType = "fast-forward-only"
+	RefRestrictionPullRequestOnly RefRestrictionType = "pull-request-only"
+)
+
+const (
+	RefMatcherBranch        RefMatcherKind = "BRANCH"
+	RefMatcherPattern       RefMatcherKind = "PATTERN"
+	RefMatcherModelCategory RefMatcherKind = "MODEL_CATEGORY"
+	RefMatcherModelBranch   RefMatcherKind = "MODEL_BRANCH"
+
+	// RefMatcherAnyRef matches any ref, it's used by default tasks.
+	RefMatcherAnyRef RefMatcherKind = "ANY_REF"
+)
+
+const (
+	ChangeActionCreate ChangeAction = "create"
+	ChangeActionUpdate ChangeAction = "update"
+	ChangeActionDelete ChangeAction = "delete"
+)
+
+const (
+	PermissionScopeGlobal     PermissionScope = "GLOBAL"
+	PermissionScopeProject    PermissionScope = "PROJECT"
+	PermissionScopeRepository PermissionScope = "REPOSITORY"
+)
+
+const (
+	WebhookEventRefsChanged    WebhookEvent = "repo:refs_changed"
+	WebhookEventRepoModified   WebhookEvent = "repo:modified"
+	WebhookEventRepoForked     WebhookEvent = "repo:forked"
+	WebhookEventCommentAdded   WebhookEvent = "repo:comment:added"
+	WebhookEventCommentEdited  WebhookEvent = "repo:comment:edited"
+	WebhookEventCommentDeleted WebhookEvent = "repo:comment:deleted"
+	WebhookEventMirrorSynced   WebhookEvent = "mirror:repo_synchronized"
+
+	WebhookEventPullRequestOpened          WebhookEvent = "pr:opened"
+	WebhookEventPullRequestFromRefUpdated  WebhookEvent = "pr:from_ref_updated"
+	WebhookEventPullRequestToRefUpdated    WebhookEvent = "pr:to_ref_updated"
+	WebhookEventPullRequestModified        WebhookEvent = "pr:modified"
+	WebhookEventPullRequestReviewerUpdated WebhookEvent = "pr:reviewer:updated"
+	WebhookEventPullRequestApproved        WebhookEvent = "pr:reviewer:approved"
+	WebhookEventPullRequestUnapproved      WebhookEvent = "pr:reviewer:unapproved"
+	WebhookEventPullRequestNeedsWork       WebhookEvent = "pr:reviewer:needs_work"
+	WebhookEventPullRequestMerged          WebhookEvent = "pr:merged"
+	WebhookEventPullRequestDeclined        WebhookEvent = "pr:declined"
+	WebhookEventPullRequestDeleted         WebhookEvent = "pr:deleted"
+	WebhookEventPullRequestCommentAdded    WebhookEvent = "pr:comment:added"
+	WebhookEventPullRequestCommentEdited   WebhookEvent = "pr:comment:edited"
+	WebhookEventPullRequestCommentDeleted  WebhookEvent = "pr:comment:deleted"
+)
+
+const (
+	// PatchFormatDiff is a plain unified diff of all changes.
+	PatchFormatDiff PatchFormat = "diff"
+	// PatchFormatPatch is a series of commits as produced by git
+	// format-patch.
+	PatchFormatPatch PatchFormat = "patch"
+)
+
+const (
+	ArchiveFormatZip   ArchiveFormat = "zip"
+	ArchiveFormatTar   ArchiveFormat = "tar"
+	ArchiveFormatTarGz ArchiveFormat = "tar.gz"
+)
+
+const (
+	ActivityActionApproved   ActivityAction = "APPROVED"
+	ActivityActionCommented  ActivityAction = "COMMENTED"
+	ActivityActionDeclined   ActivityAction = "DECLINED"
+	ActivityActionMerged     ActivityAction = "MERGED"
+	ActivityActionOpened     ActivityAction = "OPENED"
+	ActivityActionReopened   ActivityAction = "REOPENED"
+	ActivityActionRescoped   ActivityAction = "RESCOPED"
+	ActivityActionReviewed   ActivityAction = "REVIEWED"
+	ActivityActionUnapproved ActivityAction = "UNAPPROVED"
+	ActivityActionUpdated    ActivityAction = "UPDATED"
+)
+
+const (
+	CommentActionAdded   CommentAction = "ADDED"
+	CommentActionEdited  CommentAction = "EDITED"
+	CommentActionDeleted CommentAction = "DELETED"
+	CommentActionReplied CommentAction = "REPLIED"
+)
+
+const (
+	WebhookOutcomeSuccess WebhookOutcome = "SUCCESS"
+	WebhookOutcomeFailure WebhookOutcome = "FAILURE"
+	WebhookOutcomeError   WebhookOutcome = "ERROR"
+)
+
+const (
+	WebhookScopeProject    WebhookScope = "project"
+	WebhookScopeRepository WebhookScope = "repository"
+)
+
+const (
+	TaskStateOpen     TaskState = "OPEN"
+	TaskStateResolved TaskState = "RESOLVED"
+)
+
+const (
+	CommentLineTypeAdded   CommentLineType = "ADDED"
+	CommentLineTypeRemoved CommentLineType = "REMOVED"
+	CommentLineTypeContext CommentLineType = "CONTEXT"
+)
+
+const (
+	// CommentFileTypeFrom is the source side of diff, used with removed
+	// lines.
+	CommentFileTypeFrom CommentFileType = "FROM"
+	// CommentFileTypeTo is the destination side of diff.
+	CommentFileTypeTo CommentFileType = "TO"
+)
+
+const (
+	// RefSyncStateAhead is a ref with commits which origin doesn't have.
+	RefSyncStateAhead RefSyncState = "AHEAD"
+	// RefSyncStateDiverged is a ref both fork and origin have new commits
+	// on.
+	RefSyncStateDiverged RefSyncState = "DIVERGED"
+	// RefSyncStateOrphaned is a ref deleted in origin.
+	RefSyncStateOrphaned RefSyncState = "ORPHANED"
+)
+
+const (
+	RefSyncActionMerge   RefSyncAction = "MERGE"
+	RefSyncActionDiscard RefSyncAction = "DISCARD"
+)
@@ -0,0 +1,157 @@
+package stash
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/reconquest/karma-go"
+)
+
+// NewClientFromEnv creates client using STASH_URL, STASH_USERNAME and
+// STASH_TOKEN environment variables. If credentials are not set in
+// environment, they are looked up in .netrc file (path can be overridden by
+// NETRC variable) by the host of STASH_URL.
+func NewClientFromEnv(options ...Option) (Stash, error) {
+	rawURL := os.Getenv("STASH_URL")
+	if rawURL == "" {
+		return nil, errors.New("STASH_URL environment variable is not set")
+	}
+
+	baseURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, karma.Format(err, "unable to parse STASH_URL: %s", rawURL)
+	}
+
+	userName := os.Getenv("STASH_USERNAME")
+	password := os.Getenv("STASH_TOKEN")
+
+	if userName == "" || password == "" {
+		path, err := netrcPath()
+		if err != nil {
+			return nil, err
+		}
+
+		login, secret, err := readNetrc(path, baseURL.Hostname())
+		if err != nil && !os.IsNotExist(err) {
+			return nil, karma.Format(err, "unable to read %s", path)
+		}
+
+		if userName == "" {
+			userName = login
+		}
+
+		if password == "" && userName == login {
+			password = secret
+		}
+	}
+
+	return NewClient(userName, password, baseURL, options...), nil
+}
+
+func netrcPath() (string, error) {
+	if path := os.Getenv("NETRC"); path != "" {
+		return path, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", karma.Format(err, "unable to find home directory")
+	}
+
+	return filepath.Join(home, ".netrc"), nil
+}
+
+// readNetrc returns login and password for the given host, falling back to
+// the default entry.
+func readNetrc(path, host string) (string, string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+
+	type entry struct {
+		login, password string
+		found           bool
+	}
+
+	var (
+		machine, fallback entry
+		current           *entry
+		tokens            = strings.Fields(stripNetrcMacros(string(data)))
+	)
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "machine":
+			current = nil
+			if i+1 < len(tokens) {
+				i++
+				if tokens[i] == host && !machine.found {
+					machine.found = true
+					current = &machine
+				}
+			}
+
+		case "default":
+			current = nil
+			if !fallback.found {
+				fallback.found = true
+				current = &fallback
+			}
+
+		case "login", "password", "account":
+			if i+1 >= len(tokens) {
+				continue
+			}
+
+			i++
+			if current == nil {
+				continue
+			}
+
+			switch tokens[i-1] {
+			case "login":
+				current.login = tokens[i]
+			case "password":
+				current.password = tokens[i]
+			}
+		}
+	}
+
+	if machine.found {
+		return machine.login, machine.password, nil
+	}
+
+	return fallback.login, fallback.password, nil
+}
+
+// stripNetrcMacros removes macdef definitions, which last until an empty
+// line and may contain arbitrary tokens.
+func stripNetrcMacros(data string) string {
+	var (
+		lines []string
+		macro bool
+	)
+
+	for _, line := range strings.Split(data, "\n") {
+		if macro {
+			if strings.TrimSpace(line) == "" {
+				macro = false
+			}
+			continue
+		}
+
+		if fields := strings.Fields(line); len(fields) > 0 && fields[0] == "macdef" {
+			macro = true
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,63 @@
+package stash
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const netrc = `machine other.example.com login alice password secret
+macdef init
+machine stash.example.com login mallory password stolen
+
+machine stash.example.com
+    login bob
+    password token
+default login anonymous password guest
+`
+
+func TestNewClientFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "netrc")
+	if err := os.WriteFile(path, []byte(netrc), 0600); err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	t.Setenv("NETRC", path)
+	t.Setenv("STASH_URL", "https://stash.example.com/bitbucket")
+	t.Setenv("STASH_USERNAME", "")
+	t.Setenv("STASH_TOKEN", "")
+
+	stashClient, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	client := stashClient.(Client)
+	if client.userName != "bob" || client.password != "token" {
+		t.Fatalf("Want bob:token but got %s:%s\n", client.userName, client.password)
+	}
+	if client.baseURL.Path != "/bitbucket" {
+		t.Fatalf("Want /bitbucket but got %s\n", client.baseURL.Path)
+	}
+
+	t.Setenv("STASH_USERNAME", "carol")
+	t.Setenv("STASH_TOKEN", "pat")
+
+	stashClient, err = NewClientFromEnv()
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	client = stashClient.(Client)
+	if client.userName != "carol" || client.password != "pat" {
+		t.Fatalf("Want carol:pat but got %s:%s\n", client.userName, client.password)
+	}
+}
+
+func TestNewClientFromEnvWithoutURL(t *testing.T) {
+	t.Setenv("STASH_URL", "")
+
+	if _, err := NewClientFromEnv(); err == nil {
+		t.Fatalf("Want error but got none\n")
+	}
+}
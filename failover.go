@@ -0,0 +1,59 @@
+package stash
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithFailoverURLs configures secondary base URLs, like a smart mirror or a
+// standby node, which are tried in given order when GET or HEAD request to
+// the primary base URL fails to get any response. Requests which change
+// server state are only sent to the primary.
+func WithFailoverURLs(mirrors ...*url.URL) Option {
+	return func(client *Client) {
+		client.mirrors = append(client.mirrors, mirrors...)
+	}
+}
+
+// send sends the request, failing over to secondary base URLs if it's
+// allowed for the request.
+func (client Client) send(request *http.Request) (*http.Response, error) {
+	response, err := client.roundTrip(request)
+	if err == nil || len(client.mirrors) == 0 {
+		return response, err
+	}
+
+	switch request.Method {
+	case http.MethodGet, http.MethodHead:
+	default:
+		return nil, err
+	}
+
+	primary := strings.TrimRight(client.baseURL.String(), "/")
+	path := strings.TrimPrefix(request.URL.String(), primary)
+	if path == request.URL.String() {
+		// absolute URL returned by the server, e.g. UPM task link
+		return nil, err
+	}
+
+	for _, mirror := range client.mirrors {
+		target, parseErr := url.Parse(
+			strings.TrimRight(mirror.String(), "/") + path,
+		)
+		if parseErr != nil {
+			continue
+		}
+
+		failover := request.Clone(request.Context())
+		failover.URL = target
+		failover.Host = ""
+
+		response, mirrorErr := client.roundTrip(failover)
+		if mirrorErr == nil {
+			return response, nil
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,49 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestWithFailoverURLs(t *testing.T) {
+	var mutations int
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			mutations++
+			return
+		}
+		if r.URL.Path != "/stash/rest/api/1.0/projects/PRJ/repos/widge/branches" {
+			t.Fatalf("Want path on mirror with its prefix but found %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Basic dTpw" {
+			t.Fatalf("Want  Basic dTpw but found %s\n", r.Header.Get("Authorization"))
+		}
+		fmt.Fprint(w, branches)
+	}))
+	defer mirror.Close()
+
+	primary := httptest.NewServer(http.NotFoundHandler())
+	primaryURL, _ := url.Parse(primary.URL)
+	primary.Close()
+
+	mirrorURL, _ := url.Parse(mirror.URL + "/stash/")
+	stashClient := NewClient("u", "p", primaryURL, WithFailoverURLs(mirrorURL))
+
+	branches, err := stashClient.GetBranches("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("GetBranches() not expecting an error, but received: %v\n", err)
+	}
+	if len(branches) != 4 {
+		t.Fatalf("Want 4 branches but got %d\n", len(branches))
+	}
+
+	if err := stashClient.DeleteBranch("PRJ", "widge", "master"); err == nil {
+		t.Fatalf("DeleteBranch() expecting an error but received none\n")
+	}
+	if mutations != 0 {
+		t.Fatalf("Want mutating request not sent to mirror but got %d\n", mutations)
+	}
+}
@@ -0,0 +1,267 @@
+package stash
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// binarySniffLength is how many leading bytes are checked for NUL to tell
+// binary files from text ones, the same heuristic git uses.
+const binarySniffLength = 8000
+
+type (
+	RawFileOptions struct {
+		// At is a branch, tag or commit to read file at, default branch is
+		// used if it's empty.
+		At string
+
+		// MaxSize fails reading files larger than given amount of bytes,
+		// so huge files are not loaded into memory, zero is unlimited.
+		MaxSize int64
+	}
+
+	RawFile struct {
+		Data        []byte
+		ContentType string
+
+		// Binary is true if server reports specific non-text content type,
+		// e.g. image/png, or file contains NUL bytes.
+		Binary bool
+	}
+
+	// RawFileReader streams contents of the file, it must be closed.
+	RawFileReader struct {
+		io.ReadCloser
+
+		// Size is -1 if server doesn't report it.
+		Size        int64
+		ContentType string
+	}
+
+	// FileTooLargeError is returned by GetRawFileAt and GetRawFileReader
+	// when file exceeds RawFileOptions.MaxSize.
+	FileTooLargeError struct {
+		Path    string
+		MaxSize int64
+	}
+)
+
+// GetRawFileAt returns contents of the file at the given ref.
+func (client Client) GetRawFileAt(
+	projectKey, repositorySlug, filePath string,
+	options RawFileOptions,
+) (RawFile, error) {
+	var file RawFile
+	err := client.requestDecode(
+		"GET",
+		rawFilePath(projectKey, repositorySlug, filePath, options.At),
+		nil,
+		func(response *http.Response) error {
+			body := io.Reader(response.Body)
+			if options.MaxSize > 0 {
+				body = io.LimitReader(body, options.MaxSize+1)
+			}
+
+			data, err := io.ReadAll(body)
+			if err != nil {
+				return responseContext(response).Format(
+					err,
+					"read response body",
+				)
+			}
+
+			if options.MaxSize > 0 && int64(len(data)) > options.MaxSize {
+				return FileTooLargeError{Path: filePath, MaxSize: options.MaxSize}
+			}
+
+			file = RawFile{
+				Data:        data,
+				ContentType: response.Header.Get("Content-Type"),
+			}
+			file.Binary = isBinary(file.ContentType, data)
+
+			return nil
+		},
+		http.StatusOK,
+	)
+	if err != nil {
+		return RawFile{}, err
+	}
+
+	return file, nil
+}
+
+// GetRawFileReader returns contents of the file at the given ref as a
+// stream, so large files can be copied without loading them into memory.
+// If options.MaxSize is set, files of known larger size are rejected right
+// away and reading fails once more than MaxSize bytes are read.
+func (client Client) GetRawFileReader(
+	projectKey, repositorySlug, filePath string,
+	options RawFileOptions,
+) (RawFileReader, error) {
+	request, err := client.getRequest(
+		"GET",
+		rawFilePath(projectKey, repositorySlug, filePath, options.At),
+		nil,
+	)
+	if err != nil {
+		return RawFileReader{}, err
+	}
+
+	if client.skipDryRun(request) {
+		return RawFileReader{ReadCloser: io.NopCloser(strings.NewReader(""))}, nil
+	}
+
+	response, err := client.sendOpen(request, http.StatusOK)
+	if err != nil {
+		return RawFileReader{}, err
+	}
+
+	reader := RawFileReader{
+		ReadCloser:  response.Body,
+		Size:        response.ContentLength,
+		ContentType: response.Header.Get("Content-Type"),
+	}
+
+	if options.MaxSize > 0 {
+		tooLarge := FileTooLargeError{Path: filePath, MaxSize: options.MaxSize}
+		if reader.Size > options.MaxSize {
+			response.Body.Close()
+			return RawFileReader{}, tooLarge
+		}
+
+		reader.ReadCloser = &sizeLimitedReader{
+			ReadCloser: response.Body,
+			left:       options.MaxSize,
+			err:        tooLarge,
+		}
+	}
+
+	return reader, nil
+}
+
+// sizeLimitedReader fails with err once more than left bytes are read.
+type sizeLimitedReader struct {
+	io.ReadCloser
+	left int64
+	err  error
+}
+
+func (reader *sizeLimitedReader) Read(data []byte) (int, error) {
+	if reader.left < 0 {
+		return 0, reader.err
+	}
+
+	// one extra byte is read to tell file of exactly allowed size from
+	// larger one
+	if int64(len(data)) > reader.left+1 {
+		data = data[:reader.left+1]
+	}
+
+	n, err := reader.ReadCloser.Read(data)
+	reader.left -= int64(n)
+	if reader.left < 0 {
+		return n + int(reader.left), reader.err
+	}
+
+	return n, err
+}
+
+// GetFiles returns paths of all files under the directory at the given ref,
+// recursively. Paths are relative to the directory, empty path lists the
+// whole repository and empty ref selects the default branch.
+func (client Client) GetFiles(
+	projectKey, repositorySlug, path, at string,
+) ([]string, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[string], error) {
+			query := url.Values{}
+			if at != "" {
+				query.Set("at", at)
+			}
+			query.Set("start", strconv.Itoa(start))
+			query.Set("limit", strconv.Itoa(limit))
+
+			var response Paged[string]
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/api/1.0/projects/%s/repos/%s/files%s?%s",
+					projectKey, repositorySlug,
+					rawPath(directoryPath(path)), rawPath(query.Encode()),
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+// rawFilePath returns path of the raw file endpoint, empty ref selects the
+// default branch.
+func rawFilePath(projectKey, repositorySlug, filePath, at string) string {
+	query := url.Values{}
+	if at != "" {
+		query.Set("at", at)
+	}
+
+	return pathf(
+		"/rest/api/1.0/projects/%s/repos/%s/raw/%s?%s",
+		projectKey, repositorySlug,
+		rawPath(escapeFilePath(filePath)), rawPath(query.Encode()),
+	)
+}
+
+func (err FileTooLargeError) Error() string {
+	return fmt.Sprintf(
+		"file %s is larger than %d bytes", err.Path, err.MaxSize,
+	)
+}
+
+// directoryPath returns escaped path suffix of directory endpoints, which is
+// empty for the repository root.
+func directoryPath(path string) string {
+	path = escapeFilePath(path)
+	if path == "" {
+		return ""
+	}
+
+	return "/" + path
+}
+
+// escapeFilePath escapes every segment of the path, keeping slashes.
+func escapeFilePath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// isBinary tells whether file is binary by its content type, falling back
+// to sniffing for generic types.
+func isBinary(contentType string, data []byte) bool {
+	if contentType != "" &&
+		!strings.HasPrefix(contentType, "application/octet-stream") &&
+		!strings.HasPrefix(contentType, "text/") &&
+		!strings.Contains(contentType, "json") &&
+		!strings.Contains(contentType, "xml") &&
+		!strings.Contains(contentType, "javascript") {
+		return true
+	}
+
+	if len(data) > binarySniffLength {
+		data = data[:binarySniffLength]
+	}
+
+	return bytes.IndexByte(data, 0) >= 0
+}
@@ -0,0 +1,83 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const availableAddonsFirstPage string = `
+{
+    "links": {
+        "next": "/rest/plugins/1.0/available?q=jira&offset=1"
+    },
+    "plugins": [
+        {
+            "key": "com.example.jira",
+            "name": "Jira Helper",
+            "version": "1.2.0",
+            "installed": false,
+            "installable": true
+        }
+    ]
+}
+`
+
+const availableAddonsLastPage string = `
+{
+    "links": {},
+    "plugins": [
+        {
+            "key": "com.example.jira-sync",
+            "name": "Jira Sync",
+            "version": "2.0.1",
+            "installed": true,
+            "installable": true
+        }
+    ]
+}
+`
+
+func TestGetAvailableAddons(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Fatalf("wanted GET but found %s\n", r.Method)
+		}
+		url := *r.URL
+		if url.Path != "/rest/plugins/1.0/available" {
+			t.Fatalf("Want /rest/plugins/1.0/available but found %s\n", url.Path)
+		}
+		params := url.Query()
+		if params.Get("q") != "jira" {
+			t.Fatalf("Want jira but found %s\n", params.Get("q"))
+		}
+		switch params.Get("offset") {
+		case "0":
+			fmt.Fprint(w, availableAddonsFirstPage)
+		case "1":
+			fmt.Fprint(w, availableAddonsLastPage)
+		default:
+			t.Fatalf("Unexpected offset %s\n", params.Get("offset"))
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	addons, err := stashClient.GetAvailableAddons("jira")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(addons) != 2 {
+		t.Fatalf("Want 2 but got %d\n", len(addons))
+	}
+	if addons[0].Key != "com.example.jira" {
+		t.Fatalf("Want com.example.jira but got %s\n", addons[0].Key)
+	}
+	if !addons[1].Installed {
+		t.Fatalf("Want installed addon but got %+v\n", addons[1])
+	}
+}
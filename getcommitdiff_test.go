@@ -0,0 +1,58 @@
+package stash
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetCommitDiff(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		if params.Get("whitespace") != "ignore-all" {
+			t.Fatalf("Want ignore-all but found %s\n", params.Get("whitespace"))
+		}
+
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PRJ/repos/widge/commits/e680a10/diff/README.md":
+			fmt.Fprint(w, pullRequestDiffResponse)
+		case "/rest/api/1.0/projects/PRJ/repos/widge/diff/README.md":
+			if params.Get("until") != "e680a10" || params.Has("withComments") {
+				t.Fatalf("Want raw diff until commit but got %s\n", r.URL.RawQuery)
+			}
+			if r.Header.Get("Accept") != "text/plain" {
+				t.Fatalf("Want Accept text/plain but found %s\n", r.Header.Get("Accept"))
+			}
+			fmt.Fprint(w, "diff --git a/README.md b/README.md\n")
+		default:
+			t.Fatalf("Unexpected path %s\n", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	options := DiffOptions{Path: "README.md", IgnoreWhitespace: true}
+
+	diff, err := stashClient.GetCommitDiff("PRJ", "widge", "e680a10", options)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(diff.Diffs) != 1 || diff.Diffs[0].Path() != "README.md" {
+		t.Fatalf("Want diff of README.md but got %+v\n", diff.Diffs)
+	}
+
+	var raw bytes.Buffer
+	err = stashClient.WriteCommitDiff("PRJ", "widge", "e680a10", options, &raw)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if raw.String() != "diff --git a/README.md b/README.md\n" {
+		t.Fatalf("Want raw diff but got %q\n", raw.String())
+	}
+}
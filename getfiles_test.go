@@ -0,0 +1,44 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetFiles(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/rest/api/1.0/projects/PRJ/repos/widge/files/docs/my%20guide" {
+			t.Fatalf("Want escaped files path but found %s\n", r.URL.EscapedPath())
+		}
+
+		params := r.URL.Query()
+		if params.Get("at") != "refs/tags/v1" {
+			t.Fatalf("Want files at v1 but found %s\n", params.Get("at"))
+		}
+
+		switch params.Get("start") {
+		case "0":
+			fmt.Fprint(w, `{"isLastPage": false, "nextPageStart": 1, "values": ["index.md"]}`)
+		case "1":
+			fmt.Fprint(w, `{"isLastPage": true, "values": ["images/logo.png"]}`)
+		default:
+			t.Fatalf("Unexpected query %s\n", r.URL.RawQuery)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	files, err := stashClient.Project("PRJ").Repo("widge").Files("/docs/my guide/", "refs/tags/v1")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(files) != 2 || files[0] != "index.md" || files[1] != "images/logo.png" {
+		t.Fatalf("Want files from both pages but got %v\n", files)
+	}
+}
@@ -0,0 +1,44 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetLastModified(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PRJ/repos/widge/last-modified/src" {
+			t.Fatalf("Want last-modified path but found %s\n", r.URL.Path)
+		}
+
+		if r.URL.Query().Get("at") != "master" {
+			t.Fatalf("Want at master but found %s\n", r.URL.RawQuery)
+		}
+
+		fmt.Fprint(w, `{
+			"files": {
+				"main.go": {"id": "abc", "author": {"name": "alice"}, "authorTimestamp": 1500000000000},
+				"pkg": {"id": "def", "author": {"name": "bob"}}
+			},
+			"latestCommit": {"id": "fff"}
+		}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	modified, err := stashClient.GetLastModified("PRJ", "widge", "src", "master")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(modified.Files) != 2 ||
+		modified.Files["main.go"].Author.Name != "alice" ||
+		modified.LatestCommit.ID != "fff" {
+		t.Fatalf("Want commits per file but got %+v\n", modified)
+	}
+}
@@ -0,0 +1,131 @@
+package stash
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+const pullRequestDiffResponse string = `
+{
+    "fromHash": "aead30bdfe27e176316bb2e2aedd530052730092",
+    "toHash": "e680a10f3e0afb5e3a5978dea02d37ac884da21",
+    "contextLines": 5,
+    "whitespace": "IGNORE_ALL",
+    "diffs": [
+        {
+            "source": {"toString": "README.md", "name": "README.md"},
+            "destination": {"toString": "README.md", "name": "README.md"},
+            "hunks": [
+                {
+                    "sourceLine": 1,
+                    "sourceSpan": 2,
+                    "destinationLine": 1,
+                    "destinationSpan": 2,
+                    "segments": [
+                        {"type": "CONTEXT", "lines": [{"source": 1, "destination": 1, "line": "Stash tools"}]},
+                        {"type": "REMOVED", "lines": [{"source": 2, "destination": 2, "line": "==========="}]},
+                        {"type": "ADDED", "lines": [{"source": 3, "destination": 2, "line": "=====", "commentIds": [7]}]}
+                    ]
+                }
+            ]
+        }
+    ]
+}
+`
+
+func TestGetPullRequestDiff(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		url := *r.URL
+		if url.Path != "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/1/diff/README.md" {
+			t.Fatalf("Want /rest/api/1.0/projects/PRJ/repos/widge/pull-requests/1/diff/README.md but found %s\n", url.Path)
+		}
+		params := url.Query()
+		if params.Get("contextLines") != "5" {
+			t.Fatalf("Want 5 context lines but found %s\n", params.Get("contextLines"))
+		}
+		if params.Get("whitespace") != "ignore-all" {
+			t.Fatalf("Want ignore-all but found %s\n", params.Get("whitespace"))
+		}
+		if params.Get("withComments") != "true" {
+			t.Fatalf("Want withComments but found %s\n", params.Get("withComments"))
+		}
+		fmt.Fprint(w, pullRequestDiffResponse)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	diff, err := stashClient.GetPullRequestDiff("PRJ", "widge", "1", DiffOptions{
+		Path:             "README.md",
+		ContextLines:     5,
+		IgnoreWhitespace: true,
+		WithComments:     true,
+	})
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(diff.Diffs) != 1 || diff.Diffs[0].Path() != "README.md" {
+		t.Fatalf("Unexpected diffs %+v\n", diff.Diffs)
+	}
+	segments := diff.Diffs[0].Hunks[0].Segments
+	if len(segments) != 3 || segments[2].Lines[0].CommentIDs[0] != 7 {
+		t.Fatalf("Unexpected segments %+v\n", segments)
+	}
+}
+
+func TestGetPullRequestDiffEscapesPath(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/1/diff/docs/a%23b%20c%3Fd.md"
+		if r.URL.EscapedPath() != want {
+			t.Fatalf("Want %s but found %s\n", want, r.URL.EscapedPath())
+		}
+		if r.URL.Query().Get("withComments") != "false" {
+			t.Fatalf("Want path kept out of query but got %s\n", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, pullRequestDiffResponse)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	_, err := stashClient.GetPullRequestDiff("PRJ", "widge", "1", DiffOptions{
+		Path: "docs/a#b c?d.md",
+	})
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+}
+
+func TestWritePullRequestPatch(t *testing.T) {
+	const patch = "From aead30b Mon Sep 17 00:00:00 2001\nSubject: [PATCH] Fix README\n"
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/1.0/application-properties" {
+			fmt.Fprint(w, `{"version": "8.9.0"}`)
+			return
+		}
+		if r.URL.Path != "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/5.patch" {
+			t.Fatalf("Want /rest/api/1.0/projects/PRJ/repos/widge/pull-requests/5.patch but found %s\n", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, patch)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	var output bytes.Buffer
+	err := stashClient.WritePullRequestPatch("PRJ", "widge", "5", PatchFormatPatch, &output)
+	if err != nil {
+		t.Fatalf("WritePullRequestPatch() not expecting an error, but received: %v\n", err)
+	}
+	if output.String() != patch {
+		t.Fatalf("Want patch written as is but got %q\n", output.String())
+	}
+}
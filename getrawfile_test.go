@@ -1,7 +1,9 @@
 package stash
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -41,3 +43,107 @@ func TestGetRawFile(t *testing.T) {
 		t.Fatalf("Want hello, but got <%s>\n", string(data))
 	}
 }
+
+func TestGetRawFileAt(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("at") != "refs/tags/v1.0" {
+			t.Fatalf("Want at=refs/tags/v1.0 but found %s\n", r.URL.RawQuery)
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/rest/api/1.0/projects/PRJ/repos/widge/raw/docs/read%20me.md":
+			w.Header().Set("Content-Type", "text/plain;charset=UTF-8")
+			fmt.Fprint(w, "hello")
+		case "/rest/api/1.0/projects/PRJ/repos/widge/raw/logo.bin":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte{0x89, 'P', 'N', 'G', 0x00, 0x01})
+		default:
+			t.Fatalf("Unexpected path %s\n", r.URL.EscapedPath())
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	file, err := stashClient.GetRawFileAt("PRJ", "widge", "docs/read me.md", RawFileOptions{
+		At: "refs/tags/v1.0",
+	})
+	if err != nil {
+		t.Fatalf("GetRawFileAt() not expecting an error, but received: %v\n", err)
+	}
+	if string(file.Data) != "hello" || file.Binary {
+		t.Fatalf("Want text file hello but got %+v\n", file)
+	}
+
+	file, err = stashClient.GetRawFileAt("PRJ", "widge", "logo.bin", RawFileOptions{
+		At: "refs/tags/v1.0",
+	})
+	if err != nil {
+		t.Fatalf("GetRawFileAt() not expecting an error, but received: %v\n", err)
+	}
+	if !file.Binary {
+		t.Fatalf("Want binary file but got %+v\n", file)
+	}
+
+	_, err = stashClient.GetRawFileAt("PRJ", "widge", "docs/read me.md", RawFileOptions{
+		At:      "refs/tags/v1.0",
+		MaxSize: 3,
+	})
+	var tooLarge FileTooLargeError
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("Want FileTooLargeError but got %v\n", err)
+	}
+}
+
+func TestGetRawFileReader(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/rest/api/1.0/projects/PRJ/repos/widge/raw/dump.sql" {
+			t.Fatalf("Unexpected path %s\n", r.URL.EscapedPath())
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		if r.URL.Query().Get("at") == "chunked" {
+			// size is unknown to the client
+			w.Write([]byte("select "))
+			w.(http.Flusher).Flush()
+		}
+		w.Write([]byte("1;"))
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	reader, err := stashClient.GetRawFileReader("PRJ", "widge", "dump.sql", RawFileOptions{})
+	if err != nil {
+		t.Fatalf("GetRawFileReader() not expecting an error, but received: %v\n", err)
+	}
+	defer reader.Close()
+
+	data, _ := io.ReadAll(reader)
+	if string(data) != "1;" || reader.Size != 2 || reader.ContentType != "text/plain" {
+		t.Fatalf("Want 2 bytes of text but got %q, %d, %s\n", data, reader.Size, reader.ContentType)
+	}
+
+	var tooLarge FileTooLargeError
+
+	_, err = stashClient.GetRawFileReader("PRJ", "widge", "dump.sql", RawFileOptions{MaxSize: 1})
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("Want FileTooLargeError for known size but got %v\n", err)
+	}
+
+	reader, err = stashClient.GetRawFileReader("PRJ", "widge", "dump.sql", RawFileOptions{
+		At:      "chunked",
+		MaxSize: 8,
+	})
+	if err != nil {
+		t.Fatalf("GetRawFileReader() not expecting an error, but received: %v\n", err)
+	}
+	defer reader.Close()
+
+	data, err = io.ReadAll(reader)
+	if !errors.As(err, &tooLarge) || string(data) != "select 1" {
+		t.Fatalf("Want FileTooLargeError after 8 bytes but got %q, %v\n", data, err)
+	}
+}
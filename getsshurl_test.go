@@ -26,3 +26,33 @@ func TestGetSshUrlMissing(t *testing.T) {
 		t.Fatalf("Want no url but got %s\n", sshURL)
 	}
 }
+
+func TestGetHttpUrl(t *testing.T) {
+	clones := []Clone{
+		{Name: "ssh", HREF: "ssh-url"},
+		{Name: "https", HREF: "https-url"},
+	}
+	links := Links{Clones: clones}
+	repository := Repository{Links: links}
+	httpURL := repository.HttpUrl()
+	if httpURL != "https-url" {
+		t.Fatalf("Want https-url but got %s\n", httpURL)
+	}
+}
+
+func TestGetCloneURL(t *testing.T) {
+	clones := []Clone{
+		{Name: "ssh", HREF: "ssh-url"},
+		{Name: "http", HREF: "http-url"},
+	}
+	links := Links{Clones: clones}
+	repository := Repository{Links: links}
+	cloneURL := repository.CloneURL("HTTPS")
+	if cloneURL != "http-url" {
+		t.Fatalf("Want http-url but got %s\n", cloneURL)
+	}
+	cloneURL = repository.CloneURL("git")
+	if cloneURL != "" {
+		t.Fatalf("Want no url but got %s\n", cloneURL)
+	}
+}
@@ -2,6 +2,9 @@ module github.com/reconquest/stash-go
 
 go 1.23
 
-require github.com/reconquest/karma-go v0.0.0-20200326104714-79480464fdb5
+require (
+	github.com/reconquest/karma-go v0.0.0-20200326104714-79480464fdb5
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require github.com/stretchr/testify v1.7.0 // indirect
@@ -0,0 +1,63 @@
+package stash
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetGroups returns groups which names contain filter, all groups are
+// returned if it's empty.
+func (client Client) GetGroups(filter string) ([]Group, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[Group], error) {
+			query := url.Values{}
+			if filter != "" {
+				query.Set("filter", filter)
+			}
+			query.Set("start", strconv.Itoa(start))
+			query.Set("limit", strconv.Itoa(limit))
+
+			var response Paged[Group]
+			err := client.requestJSON(
+				"GET",
+				"/rest/api/1.0/admin/groups?"+query.Encode(),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+// CreateGroup creates group in the internal directory.
+func (client Client) CreateGroup(name string) (Group, error) {
+	return client.changeGroup("POST", name)
+}
+
+// DeleteGroup deletes the group and returns it, permissions granted to the
+// group are revoked.
+func (client Client) DeleteGroup(name string) (Group, error) {
+	return client.changeGroup("DELETE", name)
+}
+
+func (client Client) changeGroup(method, name string) (Group, error) {
+	if name == "" {
+		return Group{}, ValidationError{Reason: "group name is empty"}
+	}
+
+	var response Group
+	err := client.requestJSON(
+		method, "/rest/api/1.0/admin/groups?name="+url.QueryEscape(name),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Group{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,53 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGroups(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/admin/groups" {
+			t.Fatalf("Want /rest/api/1.0/admin/groups but found %s\n", r.URL.Path)
+		}
+
+		params := r.URL.Query()
+		switch r.Method {
+		case "GET":
+			if params.Get("filter") != "dev" {
+				t.Fatalf("Want groups filtered by dev but got %s\n", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"isLastPage": true, "values": [
+				{"name": "developers", "deletable": true},
+				{"name": "devops", "deletable": false}
+			]}`)
+		case "POST", "DELETE":
+			if params.Get("name") != "qa team" {
+				t.Fatalf("Want qa team but got %s\n", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"name": "qa team", "deletable": true}`)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("admin", "p", url)
+
+	groups, err := stashClient.GetGroups("dev")
+	if err != nil || len(groups) != 2 || groups[1].Deletable {
+		t.Fatalf("Want 2 groups but got %+v, %v\n", groups, err)
+	}
+
+	group, err := stashClient.CreateGroup("qa team")
+	if err != nil || group.Name != "qa team" {
+		t.Fatalf("Want created group but got %+v, %v\n", group, err)
+	}
+
+	group, err = stashClient.DeleteGroup("qa team")
+	if err != nil || group.Name != "qa team" {
+		t.Fatalf("Want deleted group but got %+v, %v\n", group, err)
+	}
+}
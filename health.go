@@ -0,0 +1,76 @@
+package stash
+
+import "net/http"
+
+type (
+	// HealthCheck is a result of a single check run by Atlassian
+	// Troubleshooting and Support Tools addon.
+	HealthCheck struct {
+		// CompleteKey identifies check across runs, e.g.
+		// com.atlassian.troubleshooting.plugin-bitbucket:databaseCheck.
+		CompleteKey string `json:"completeKey"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+
+		// Tag is a category of the check, e.g. Database, Mail or License.
+		Tag string `json:"tag"`
+
+		Healthy       bool   `json:"isHealthy"`
+		FailureReason string `json:"failureReason"`
+
+		// Severity of the failure, e.g. warning, major or critical.
+		Severity string `json:"severity"`
+
+		// Time is a time check was run at in milliseconds.
+		Time int64 `json:"time"`
+
+		Documentation string `json:"documentation"`
+	}
+
+	// HealthChecks are results of all health checks.
+	HealthChecks []HealthCheck
+)
+
+// RunHealthChecks runs all health checks and returns their results,
+// requires Atlassian Troubleshooting and Support Tools addon which is
+// bundled with Bitbucket Server.
+func (client Client) RunHealthChecks() (HealthChecks, error) {
+	var response struct {
+		Statuses HealthChecks `json:"statuses"`
+	}
+	err := client.requestJSON(
+		"GET", "/rest/troubleshooting/1.0/check/",
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response.Statuses, nil
+}
+
+// Failed returns checks which are not healthy.
+func (checks HealthChecks) Failed() HealthChecks {
+	var failed HealthChecks
+	for _, check := range checks {
+		if !check.Healthy {
+			failed = append(failed, check)
+		}
+	}
+
+	return failed
+}
+
+// Tagged returns checks of the given category.
+func (checks HealthChecks) Tagged(tag string) HealthChecks {
+	var tagged HealthChecks
+	for _, check := range checks {
+		if check.Tag == tag {
+			tagged = append(tagged, check)
+		}
+	}
+
+	return tagged
+}
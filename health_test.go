@@ -0,0 +1,43 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRunHealthChecks(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/troubleshooting/1.0/check/" {
+			t.Fatalf("Want /rest/troubleshooting/1.0/check/ but found %s\n", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"statuses": [
+			{"completeKey": "db", "name": "Database", "tag": "Database", "isHealthy": true, "severity": "undefined"},
+			{"completeKey": "mail", "name": "Mail server", "tag": "Mail", "isHealthy": false, "failureReason": "Mail server is not configured", "severity": "warning"},
+			{"completeKey": "license", "name": "License", "tag": "License", "isHealthy": false, "failureReason": "License expires in 5 days", "severity": "major"}
+		]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	checks, err := stashClient.RunHealthChecks()
+	if err != nil {
+		t.Fatalf("RunHealthChecks() not expecting an error, but received: %v\n", err)
+	}
+	if len(checks) != 3 {
+		t.Fatalf("Want 3 checks but got %+v\n", checks)
+	}
+
+	failed := checks.Failed()
+	if len(failed) != 2 || failed[0].CompleteKey != "mail" || failed[1].Severity != "major" {
+		t.Fatalf("Unexpected failed checks %+v\n", failed)
+	}
+
+	if tagged := checks.Tagged("Database"); len(tagged) != 1 || !tagged[0].Healthy {
+		t.Fatalf("Unexpected database checks %+v\n", tagged)
+	}
+}
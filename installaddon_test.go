@@ -0,0 +1,59 @@
+package stash
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInstallAddon(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/plugins/1.0/":
+			if r.Method != "POST" {
+				t.Fatalf("wanted POST but found %s\n", r.Method)
+			}
+			if r.URL.Query().Get("token") != "upm" {
+				t.Fatalf("Want upm token but found %s\n", r.URL.Query().Get("token"))
+			}
+			file, _, err := r.FormFile("plugin")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			data, _ := ioutil.ReadAll(file)
+			if string(data) != "jar contents" {
+				t.Fatalf("Want jar contents but found %s\n", data)
+			}
+			w.WriteHeader(http.StatusAccepted)
+			fmt.Fprint(w, `{"links": {"alternate": "/rest/plugins/1.0/pending/1"}}`)
+		case "/rest/plugins/1.0/pending/1":
+			fmt.Fprint(w, `{"done": true, "links": {"result": "/rest/plugins/1.0/com.example-key"}}`)
+		case "/rest/plugins/1.0/com.example-key":
+			fmt.Fprint(w, `{"key": "com.example"}`)
+		default:
+			t.Fatalf("Unexpected request to %s\n", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	path := filepath.Join(t.TempDir(), "addon.jar")
+	err := os.WriteFile(path, []byte("jar contents"), 0644)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v\n", err)
+	}
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	key, err := stashClient.InstallAddon("upm", path)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if key != "com.example" {
+		t.Fatalf("Want com.example but got %s\n", key)
+	}
+}
@@ -0,0 +1,166 @@
+// Package integration runs Bitbucket Server in a container for testing
+// stash-go against a real server.
+//
+// Bitbucket requires a license to finish setup, so harness is only usable
+// when a (timebomb) license is given, see Options.
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/reconquest/karma-go"
+	stash "github.com/reconquest/stash-go"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	DefaultImage    = "atlassian/bitbucket:8.19"
+	DefaultUser     = "admin"
+	DefaultPassword = "admin"
+
+	httpPort = "7990/tcp"
+)
+
+type (
+	Options struct {
+		// Image is a Bitbucket Server image, DefaultImage is used if empty.
+		Image string
+
+		// License is a Bitbucket Server license, required to finish
+		// unattended setup.
+		License string
+
+		// User and Password of the system administrator created during
+		// setup, DefaultUser and DefaultPassword are used if empty.
+		User     string
+		Password string
+
+		// StartupTimeout limits how long to wait for the server to become
+		// available, default is 10 minutes.
+		StartupTimeout time.Duration
+	}
+
+	// Server is a running Bitbucket Server container.
+	Server struct {
+		URL      *url.URL
+		User     string
+		Password string
+
+		container testcontainers.Container
+	}
+)
+
+// OptionsFromEnv returns options configured by STASH_IT_IMAGE and
+// STASH_IT_LICENSE environment variables.
+func OptionsFromEnv() Options {
+	return Options{
+		Image:   os.Getenv("STASH_IT_IMAGE"),
+		License: os.Getenv("STASH_IT_LICENSE"),
+	}
+}
+
+// Start launches Bitbucket Server container, bootstraps license and
+// administrator user and waits until the server is running.
+func Start(ctx context.Context, options Options) (*Server, error) {
+	if options.License == "" {
+		return nil, fmt.Errorf("license is required to set up Bitbucket Server")
+	}
+
+	if options.Image == "" {
+		options.Image = DefaultImage
+	}
+
+	if options.User == "" {
+		options.User = DefaultUser
+	}
+
+	if options.Password == "" {
+		options.Password = DefaultPassword
+	}
+
+	if options.StartupTimeout == 0 {
+		options.StartupTimeout = 10 * time.Minute
+	}
+
+	request := testcontainers.ContainerRequest{
+		Image:        options.Image,
+		ExposedPorts: []string{httpPort},
+		Env: map[string]string{
+			"SETUP_DISPLAYNAME":           "stash-go",
+			"SETUP_LICENSE":               options.License,
+			"SETUP_SYSADMIN_USERNAME":     options.User,
+			"SETUP_SYSADMIN_PASSWORD":     options.Password,
+			"SETUP_SYSADMIN_DISPLAYNAME":  "Administrator",
+			"SETUP_SYSADMIN_EMAILADDRESS": options.User + "@example.com",
+			"SEARCH_ENABLED":              "false",
+		},
+		WaitingFor: wait.ForHTTP("/status").
+			WithPort(httpPort).
+			WithResponseMatcher(isRunning).
+			WithStartupTimeout(options.StartupTimeout),
+	}
+
+	container, err := testcontainers.GenericContainer(
+		ctx,
+		testcontainers.GenericContainerRequest{
+			ContainerRequest: request,
+			Started:          true,
+		},
+	)
+	if err != nil {
+		return nil, karma.
+			Describe("image", options.Image).
+			Format(err, "start Bitbucket Server container")
+	}
+
+	endpoint, err := container.PortEndpoint(ctx, httpPort, "http")
+	if err != nil {
+		container.Terminate(ctx)
+		return nil, err
+	}
+
+	baseURL, err := url.Parse(endpoint)
+	if err != nil {
+		container.Terminate(ctx)
+		return nil, err
+	}
+
+	return &Server{
+		URL:       baseURL,
+		User:      options.User,
+		Password:  options.Password,
+		container: container,
+	}, nil
+}
+
+// Client returns client authenticated as the administrator.
+func (server *Server) Client(options ...stash.Option) stash.Stash {
+	return stash.NewClient(server.User, server.Password, server.URL, options...)
+}
+
+// Terminate stops and removes the container.
+func (server *Server) Terminate(ctx context.Context) error {
+	return server.container.Terminate(ctx)
+}
+
+// isRunning checks whether /status reports server finished setup, since it
+// replies with 200 while starting too.
+func isRunning(body io.Reader) bool {
+	var status struct {
+		State string `json:"state"`
+	}
+
+	err := json.NewDecoder(body).Decode(&status)
+	if err != nil {
+		return false
+	}
+
+	return status.State == "RUNNING"
+}
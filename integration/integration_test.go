@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	stash "github.com/reconquest/stash-go"
+)
+
+var server *Server
+
+func TestMain(m *testing.M) {
+	options := OptionsFromEnv()
+	if options.License == "" {
+		fmt.Println("STASH_IT_LICENSE is not set, skipping integration tests")
+		os.Exit(0)
+	}
+
+	var err error
+	server, err = Start(context.Background(), options)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+
+	server.Terminate(context.Background())
+
+	os.Exit(code)
+}
+
+func TestServerVersion(t *testing.T) {
+	version, err := server.Client().GetServerVersion()
+	if err != nil {
+		t.Fatalf("GetServerVersion() not expecting an error, but received: %v\n", err)
+	}
+
+	if version.Major < 5 {
+		t.Fatalf("Want server version 5 or newer but got %s\n", version)
+	}
+}
+
+func TestRepositoryLifecycle(t *testing.T) {
+	client := server.Client()
+
+	project, err := client.CreateProject("IT")
+	if err != nil {
+		t.Fatalf("CreateProject() not expecting an error, but received: %v\n", err)
+	}
+
+	repository, err := client.CreateRepository(project.Key, "lifecycle")
+	if err != nil {
+		t.Fatalf("CreateRepository() not expecting an error, but received: %v\n", err)
+	}
+
+	_, err = client.CreateRepository(project.Key, "lifecycle")
+	if !stash.IsRepositoryExists(err) {
+		t.Fatalf("Want conflict on duplicate repository but got %v\n", err)
+	}
+
+	repositories, err := client.ListProjectRepositories(project.Key)
+	if err != nil {
+		t.Fatalf("ListProjectRepositories() not expecting an error, but received: %v\n", err)
+	}
+	if len(repositories) != 1 || repositories[0].Slug != repository.Slug {
+		t.Fatalf("Want only %s in project but got %+v\n", repository.Slug, repositories)
+	}
+
+	branches, err := client.ListBranches(project.Key, repository.Slug)
+	if err != nil {
+		t.Fatalf("ListBranches() not expecting an error, but received: %v\n", err)
+	}
+	if len(branches) != 0 {
+		t.Fatalf("Want no branches in new repository but got %+v\n", branches)
+	}
+
+	webhook, err := client.CreateWebhook(project.Key, repository.Slug, stash.Webhook{
+		Name:   "ci",
+		URL:    "http://example.com/hook",
+		Events: []stash.WebhookEvent{stash.WebhookEventRefsChanged},
+		Active: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWebhook() not expecting an error, but received: %v\n", err)
+	}
+
+	plan, err := client.PlanRepository(stash.RepositorySpec{
+		ProjectKey: project.Key,
+		Slug:       repository.Slug,
+		Webhooks:   []stash.Webhook{},
+	})
+	if err != nil {
+		t.Fatalf("PlanRepository() not expecting an error, but received: %v\n", err)
+	}
+	if len(plan.Changes) != 1 || plan.Changes[0].Name != webhook.Name {
+		t.Fatalf("Want webhook deletion planned but got %v\n", plan.Changes)
+	}
+
+	if err := plan.Apply(); err != nil {
+		t.Fatalf("Apply() not expecting an error, but received: %v\n", err)
+	}
+
+	if err := client.RemoveRepository(project.Key, repository.Slug); err != nil {
+		t.Fatalf("RemoveRepository() not expecting an error, but received: %v\n", err)
+	}
+
+	_, err = client.GetRepository(project.Key, repository.Slug)
+	if !stash.IsRepositoryNotFound(err) {
+		t.Fatalf("Want removed repository to be not found but got %v\n", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Package redact hides credentials and licenses in URLs and JSON bodies,
+// it's shared by the client and the vcr package.
+package redact
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Marker replaces redacted values.
+const Marker = "REDACTED"
+
+// Keys lists names of query parameters and JSON fields which hold
+// credentials or licenses, compared case-insensitively.
+var Keys = []string{
+	"token",
+	"access_token",
+	"password",
+	"passwordConfirm",
+	"secret",
+	"license",
+	"rawLicense",
+}
+
+var reSensitiveJSON = regexp.MustCompile(
+	`(?i)("(?:` + strings.Join(QuoteKeys(Keys), "|") + `)"\s*:\s*)"(?:[^"\\]|\\.)*"`,
+)
+
+// QuoteKeys quotes keys to be used in regular expressions.
+func QuoteKeys(keys []string) []string {
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = regexp.QuoteMeta(key)
+	}
+
+	return quoted
+}
+
+func IsSensitiveKey(key string) bool {
+	for _, sensitive := range Keys {
+		if strings.EqualFold(key, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// URL hides password in user info and values of sensitive query parameters,
+// like UPM token. Relative URLs like request URIs are supported too.
+func URL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	query := parsed.Query()
+	changed := false
+	for key := range query {
+		if IsSensitiveKey(key) {
+			query.Set(key, Marker)
+			changed = true
+		}
+	}
+
+	if changed {
+		parsed.RawQuery = query.Encode()
+	}
+
+	return parsed.Redacted()
+}
+
+// Body hides values of sensitive fields in JSON body. Body which isn't JSON
+// is returned as is.
+func Body(data []byte) string {
+	return reSensitiveJSON.ReplaceAllString(string(data), `$1"`+Marker+`"`)
+}
@@ -0,0 +1,91 @@
+package stash
+
+import (
+	"context"
+	"iter"
+)
+
+// PullRequestsOptions selects pull requests for AllPullRequests.
+type PullRequestsOptions struct {
+	ProjectKey     string
+	RepositorySlug string
+
+	// State filters pull requests by state, server returns only open ones
+	// if it's empty.
+	State PullRequestState
+}
+
+// AllRepositories returns iterator over all repositories, which fetches next
+// page only when values of the previous one are consumed. Pages are fetched
+// with ctx, so iteration stops after the first error or when ctx is done,
+// even while waiting for a response.
+func (client Client) AllRepositories(
+	ctx context.Context,
+) iter.Seq2[Repository, error] {
+	client.ctx = ctx
+
+	return iteratePages(
+		ctx,
+		func(start, limit int) (Paged[Repository], error) {
+			return client.GetRepositoriesPage(start, limit)
+		},
+	)
+}
+
+// AllPullRequests returns lazy iterator over pull requests of the repository
+// like AllRepositories does.
+func (client Client) AllPullRequests(
+	ctx context.Context,
+	options PullRequestsOptions,
+) iter.Seq2[PullRequest, error] {
+	client.ctx = ctx
+
+	return iteratePages(
+		ctx,
+		func(start, limit int) (Paged[PullRequest], error) {
+			return client.getPullRequestsPage(
+				options.ProjectKey,
+				options.RepositorySlug,
+				options.State,
+				start,
+				limit,
+			)
+		},
+	)
+}
+
+// iteratePages yields values of pages returned by fetch one page at a time.
+func iteratePages[T any](
+	ctx context.Context,
+	fetch func(start, limit int) (Paged[T], error),
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		start := 0
+		for {
+			if err := ctx.Err(); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			page, err := fetch(start, stashPageLimit)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+
+			for _, value := range page.Values {
+				if !yield(value, nil) {
+					return
+				}
+			}
+
+			if page.IsLastPage {
+				return
+			}
+
+			start = page.NextPageStart
+		}
+	}
+}
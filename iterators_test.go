@@ -0,0 +1,138 @@
+package stash
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAllRepositories(t *testing.T) {
+	const total = 60
+
+	var requests int
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		response := Repositories{}
+		for i := start; i < start+limit && i < total; i++ {
+			response.Values = append(response.Values, Repository{ID: i})
+		}
+		response.IsLastPage = start+limit >= total
+		response.NextPageStart = start + limit
+
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	var ids []int
+	for repo, err := range stashClient.AllRepositories(context.Background()) {
+		if err != nil {
+			t.Fatalf("Not expecting error: %v\n", err)
+		}
+		ids = append(ids, repo.ID)
+	}
+	if len(ids) != total || requests != 3 {
+		t.Fatalf("Want %d repositories in 3 requests but got %d in %d\n", total, len(ids), requests)
+	}
+
+	requests = 0
+	for repo := range stashClient.AllRepositories(context.Background()) {
+		if repo.ID == 10 {
+			break
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("Want iteration stopped after the first page but got %d requests\n", requests)
+	}
+}
+
+func TestAllPullRequests(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests" {
+			t.Fatalf("Want pull requests path but found %s\n", r.URL.Path)
+		}
+		if r.URL.Query().Get("state") != "MERGED" {
+			t.Fatalf("Want state=MERGED but found %s\n", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"errors": [{"message": "boom"}]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	var errs int
+	for _, err := range stashClient.AllPullRequests(
+		context.Background(),
+		PullRequestsOptions{
+			ProjectKey:     "PRJ",
+			RepositorySlug: "widge",
+			State:          PullRequestStateMerged,
+		},
+	) {
+		if err == nil {
+			t.Fatalf("Want error but got none\n")
+		}
+		errs++
+	}
+	if errs != 1 {
+		t.Fatalf("Want iteration stopped after error but got %d errors\n", errs)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, err := range stashClient.AllRepositories(ctx) {
+		if err != context.Canceled {
+			t.Fatalf("Want context.Canceled but got %v\n", err)
+		}
+	}
+}
+
+func TestAllRepositoriesCancelDuringRequest(t *testing.T) {
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	done := make(chan error)
+	go func() {
+		for _, err := range stashClient.AllRepositories(ctx) {
+			done <- err
+			return
+		}
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+			t.Fatalf("Want context canceled error but got %v\n", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Want blocked request canceled with ctx\n")
+	}
+}
@@ -0,0 +1,65 @@
+package stash
+
+import "regexp"
+
+// jiraKeyPattern matches issue keys the same way Jira and Bitbucket do:
+// project key starting with an uppercase letter, dash and issue number. Go
+// regexp doesn't support lookarounds, so boundaries are checked separately.
+var jiraKeyPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9_])([A-Z][A-Z0-9_]+-[1-9][0-9]*)`)
+
+// ExtractJiraKeys returns unique Jira issue keys mentioned in given texts in
+// order of appearance.
+func ExtractJiraKeys(texts ...string) []string {
+	var (
+		keys []string
+		seen = map[string]bool{}
+	)
+
+	for _, text := range texts {
+		for _, match := range jiraKeyPattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := match[2], match[3]
+			if end < len(text) && isJiraKeyBoundary(text[end]) {
+				continue
+			}
+
+			key := text[start:end]
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	return keys
+}
+
+func isJiraKeyBoundary(char byte) bool {
+	switch {
+	case char >= 'a' && char <= 'z',
+		char >= 'A' && char <= 'Z',
+		char >= '0' && char <= '9',
+		char == '_':
+		return true
+	default:
+		return false
+	}
+}
+
+// JiraKeys returns Jira issue keys of the commit, either provided by server
+// or extracted from the commit message.
+func (commit Commit) JiraKeys() []string {
+	return ExtractJiraKeys(
+		append(append([]string{}, commit.Attributes.JiraKeys...), commit.Message)...,
+	)
+}
+
+// JiraKeys returns Jira issue keys mentioned in the branch name.
+func (branch Branch) JiraKeys() []string {
+	return ExtractJiraKeys(branch.DisplayID)
+}
+
+// JiraKeys returns Jira issue keys mentioned in the pull request title,
+// description or source branch name.
+func (pr PullRequest) JiraKeys() []string {
+	return ExtractJiraKeys(pr.Title, pr.FromRef.DisplayID, pr.Description)
+}
@@ -0,0 +1,38 @@
+package stash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractJiraKeys(t *testing.T) {
+	tests := map[string][]string{
+		"PRJ-447: fix things":            {"PRJ-447"},
+		"feature/PRJ-447-and-ABC_2-13":   {"PRJ-447", "ABC_2-13"},
+		"bugfix-WEB-3":                   {"WEB-3"},
+		"refs PRJ-1, PRJ-1 and OPS-0":    {"PRJ-1"},
+		"lowercase prj-1 or xPRJ-2":      nil,
+		"PRJ-12abc is not a key":         nil,
+		"merge (PRJ-9) into release/1.2": {"PRJ-9"},
+	}
+
+	for text, want := range tests {
+		keys := ExtractJiraKeys(text)
+		if !reflect.DeepEqual(keys, want) {
+			t.Fatalf("Want %v for %q but got %v\n", want, text, keys)
+		}
+	}
+}
+
+func TestPullRequestJiraKeys(t *testing.T) {
+	pr := PullRequest{
+		Title:       "Fix login",
+		Description: "Also closes WEB-12",
+		FromRef:     Ref{DisplayID: "bugfix/WEB-11-login"},
+	}
+
+	keys := pr.JiraKeys()
+	if !reflect.DeepEqual(keys, []string{"WEB-11", "WEB-12"}) {
+		t.Fatalf("Want [WEB-11 WEB-12] but got %v\n", keys)
+	}
+}
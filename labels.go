@@ -0,0 +1,103 @@
+package stash
+
+import "net/http"
+
+type Label struct {
+	Name string `json:"name"`
+}
+
+// GetLabels returns all labels defined on the server.
+func (client Client) GetLabels() ([]Label, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[Label], error) {
+			var response Paged[Label]
+			err := client.requestJSON(
+				"GET",
+				pathf("/rest/api/1.0/labels?start=%d&limit=%d", start, limit),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+// GetRepositoriesByLabel returns repositories labeled with the label which
+// are visible to the user.
+func (client Client) GetRepositoriesByLabel(label string) ([]Repository, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[Repository], error) {
+			var response Paged[Repository]
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/api/1.0/labels/%s/labeled?type=REPOSITORY&start=%d&limit=%d",
+					label, start, limit,
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+func (client Client) GetRepositoryLabels(
+	projectKey, repositorySlug string,
+) ([]Label, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[Label], error) {
+			var response Paged[Label]
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/api/1.0/projects/%s/repos/%s/labels?start=%d&limit=%d",
+					projectKey, repositorySlug, start, limit,
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+// AddRepositoryLabel labels repository, label is created if it doesn't
+// exist yet.
+func (client Client) AddRepositoryLabel(
+	projectKey, repositorySlug, label string,
+) error {
+	_, err := client.request(
+		"POST",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/labels",
+			projectKey, repositorySlug,
+		),
+		Label{Name: label},
+		http.StatusOK,
+	)
+
+	return err
+}
+
+func (client Client) RemoveRepositoryLabel(
+	projectKey, repositorySlug, label string,
+) error {
+	_, err := client.request(
+		"DELETE",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/labels/%s",
+			projectKey, repositorySlug, label,
+		),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
@@ -0,0 +1,56 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestLabels(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/1.0/labels":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"name": "critical"}, {"name": "legacy"}]}`)
+		case "GET /rest/api/1.0/labels/critical/labeled":
+			if r.URL.Query().Get("type") != "REPOSITORY" {
+				t.Fatalf("Want type=REPOSITORY but found %s\n", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"slug": "widge", "project": {"key": "PRJ"}, "labelableType": "REPOSITORY"}]}`)
+		case "POST /rest/api/1.0/projects/PRJ/repos/gadget/labels":
+			fmt.Fprint(w, `{"name": "critical"}`)
+		case "DELETE /rest/api/1.0/projects/PRJ/repos/gadget/labels/legacy":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	labels, err := stashClient.GetLabels()
+	if err != nil {
+		t.Fatalf("GetLabels() not expecting an error, but received: %v\n", err)
+	}
+	if len(labels) != 2 || labels[0].Name != "critical" {
+		t.Fatalf("Unexpected labels %+v\n", labels)
+	}
+
+	repositories, err := stashClient.GetRepositoriesByLabel("critical")
+	if err != nil {
+		t.Fatalf("GetRepositoriesByLabel() not expecting an error, but received: %v\n", err)
+	}
+	if len(repositories) != 1 || repositories[0].Slug != "widge" {
+		t.Fatalf("Unexpected repositories %+v\n", repositories)
+	}
+
+	if err := stashClient.AddRepositoryLabel("PRJ", "gadget", "critical"); err != nil {
+		t.Fatalf("AddRepositoryLabel() not expecting an error, but received: %v\n", err)
+	}
+	if err := stashClient.RemoveRepositoryLabel("PRJ", "gadget", "legacy"); err != nil {
+		t.Fatalf("RemoveRepositoryLabel() not expecting an error, but received: %v\n", err)
+	}
+}
@@ -0,0 +1,69 @@
+package stash
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// limitedBody releases limiter slot once response body is closed.
+type limitedBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+// WithMaxConcurrentRequests limits amount of requests client has in flight
+// at the same time, including ones made by helpers which fan out like
+// DeleteBranches or WithPagePrefetch. Request holds its slot until response
+// body is read.
+func WithMaxConcurrentRequests(limit int) Option {
+	return func(client *Client) {
+		if limit > 0 {
+			client.limiter = make(chan struct{}, limit)
+		}
+	}
+}
+
+// do sends the request, waiting for a free slot if the client is
+// configured with WithMaxConcurrentRequests. Response body must be closed.
+func (client Client) do(request *http.Request) (*http.Response, error) {
+	// cache is dropped both before and after mutation, so listing made
+	// while it's in flight isn't kept either
+	client.invalidateCache(request)
+	defer client.invalidateCache(request)
+
+	if client.limiter == nil {
+		response, err := client.sendRetrying(request)
+		return response, redactError(err)
+	}
+
+	select {
+	case client.limiter <- struct{}{}:
+	case <-request.Context().Done():
+		return nil, request.Context().Err()
+	}
+
+	release := func() {
+		<-client.limiter
+	}
+
+	response, err := client.sendRetrying(request)
+	if err != nil {
+		release()
+		return nil, redactError(err)
+	}
+
+	response.Body = &limitedBody{
+		ReadCloser: response.Body,
+		release:    release,
+	}
+
+	return response, nil
+}
+
+func (body *limitedBody) Close() error {
+	err := body.ReadCloser.Close()
+	body.once.Do(body.release)
+	return err
+}
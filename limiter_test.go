@@ -0,0 +1,53 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithMaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusNoContent)
+		fmt.Fprint(w, "")
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithMaxConcurrentRequests(2))
+
+	var branches []string
+	for i := 0; i < 10; i++ {
+		branches = append(branches, fmt.Sprintf("issue/%d", i))
+	}
+
+	results := stashClient.DeleteBranches(
+		"PROJ", "slug", branches,
+		DeleteBranchesOptions{Concurrency: 10},
+	)
+	for _, result := range results {
+		if result.Err != nil {
+			t.Fatalf("Not expecting error: %v\n", result.Err)
+		}
+	}
+
+	if atomic.LoadInt32(&maxInFlight) > 2 {
+		t.Fatalf("Want at most 2 requests in flight but got %d\n", maxInFlight)
+	}
+}
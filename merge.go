@@ -0,0 +1,86 @@
+package stash
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+type (
+	// Mergeability tells whether pull request can be merged right now.
+	Mergeability struct {
+		CanMerge   bool         `json:"canMerge"`
+		Conflicted bool         `json:"conflicted"`
+		Outcome    MergeOutcome `json:"outcome"`
+
+		// Vetoes are merge checks which are not satisfied yet, e.g.
+		// missing approvals or successful builds.
+		Vetoes []Veto `json:"vetoes"`
+	}
+
+	MergeOptions struct {
+		// Version is a version of pull request to merge.
+		Version int
+
+		// Strategy overrides merge strategy configured for repository.
+		Strategy MergeStrategy
+
+		// Message overrides merge commit message.
+		Message string
+	}
+)
+
+// GetPullRequestMergeability checks whether pull request can be merged,
+// running all merge checks.
+func (client Client) GetPullRequestMergeability(
+	projectKey, repositorySlug, identifier string,
+) (Mergeability, error) {
+	var response Mergeability
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/merge",
+			projectKey, repositorySlug, identifier,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Mergeability{}, err
+	}
+
+	return response, nil
+}
+
+// MergePullRequestWithOptions merges pull request, unlike MergePullRequest
+// it fails if pull request can't be merged, vetoes are available with
+// ResponseVetoes.
+func (client Client) MergePullRequestWithOptions(
+	projectKey, repositorySlug, identifier string,
+	options MergeOptions,
+) (PullRequest, error) {
+	query := url.Values{}
+	query.Set("version", strconv.Itoa(options.Version))
+
+	var response PullRequest
+	err := client.requestJSON(
+		"POST",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/merge?%s",
+			projectKey, repositorySlug, identifier, rawPath(query.Encode()),
+		),
+		struct {
+			Version  int           `json:"version"`
+			Strategy MergeStrategy `json:"strategyId,omitempty"`
+			Message  string        `json:"message,omitempty"`
+		}{options.Version, options.Strategy, options.Message},
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	return response, nil
+}
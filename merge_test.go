@@ -0,0 +1,43 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestGetPullRequestMergeability(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantPath := "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/42/merge"
+		if r.Method != "GET" || r.URL.Path != wantPath {
+			t.Fatalf("Want GET %s but found %s %s\n", wantPath, r.Method, r.URL.Path)
+		}
+		fmt.Fprint(w, `{
+			"canMerge": false,
+			"conflicted": true,
+			"outcome": "CONFLICTED",
+			"vetoes": [{
+				"summaryMessage": "Requires approvals",
+				"detailedMessage": "You need 2 more approvals before this pull request can be merged."
+			}]
+		}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	mergeability, err := stashClient.Project("PRJ").Repo("widge").PullRequest(42).Mergeability()
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if mergeability.CanMerge || !mergeability.Conflicted || mergeability.Outcome != MergeOutcomeConflicted {
+		t.Fatalf("Want conflicted pull request but got %+v\n", mergeability)
+	}
+
+	if len(mergeability.Vetoes) != 1 || mergeability.Vetoes[0].SummaryMessage != "Requires approvals" {
+		t.Fatalf("Want approvals veto but got %+v\n", mergeability.Vetoes)
+	}
+}
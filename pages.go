@@ -0,0 +1,123 @@
+package stash
+
+import "sync"
+
+// collectPages walks through all pages returned by fetch and returns their
+// values in the server order. Pages are fetched one by one unless client is
+// configured with WithPagePrefetch.
+func collectPages[T any](
+	client Client,
+	fetch func(start, limit int) (Paged[T], error),
+) ([]T, error) {
+	page, err := fetch(0, stashPageLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	values := page.Values
+	if values == nil {
+		values = []T{}
+	}
+
+	if page.IsLastPage {
+		return values, nil
+	}
+
+	// pages can be prefetched only if server returns as many values as
+	// asked for, otherwise their starts can't be computed ahead
+	if client.prefetch > 1 && page.NextPageStart == stashPageLimit {
+		return prefetchPages(client.prefetch, values, page, fetch)
+	}
+
+	return fetchPages(values, page, fetch)
+}
+
+// fetchPages fetches pages after the given one sequentially, chaining on
+// nextPageStart.
+func fetchPages[T any](
+	values []T,
+	page Paged[T],
+	fetch func(start, limit int) (Paged[T], error),
+) ([]T, error) {
+	var err error
+	for !page.IsLastPage {
+		page, err = fetch(page.NextPageStart, stashPageLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, page.Values...)
+	}
+
+	return values, nil
+}
+
+// prefetchPages fetches pages after given full one using bounded amount of
+// workers. Since page starts are computed ahead of time, workers may fetch a
+// few empty pages past the last one, which are discarded. If some page
+// comes back short, pages after it are fetched one by one.
+func prefetchPages[T any](
+	workers int,
+	values []T,
+	first Paged[T],
+	fetch func(start, limit int) (Paged[T], error),
+) ([]T, error) {
+	type result struct {
+		page Paged[T]
+		err  error
+	}
+
+	var (
+		mutex   sync.Mutex
+		group   sync.WaitGroup
+		results = map[int]result{}
+		offset  = first.NextPageStart
+		next    = 0
+		last    = -1
+	)
+
+	for i := 0; i < workers; i++ {
+		group.Add(1)
+		go func() {
+			defer group.Done()
+
+			for {
+				mutex.Lock()
+				if last >= 0 && next > last {
+					mutex.Unlock()
+					return
+				}
+
+				index := next
+				next++
+				mutex.Unlock()
+
+				start := offset + index*stashPageLimit
+				page, err := fetch(start, stashPageLimit)
+
+				mutex.Lock()
+				results[index] = result{page: page, err: err}
+				short := page.NextPageStart != start+stashPageLimit
+				if err != nil || page.IsLastPage || short {
+					if last < 0 || index < last {
+						last = index
+					}
+				}
+				mutex.Unlock()
+			}
+		}()
+	}
+
+	group.Wait()
+
+	for index := 0; index <= last; index++ {
+		result := results[index]
+		if result.err != nil {
+			return nil, result.err
+		}
+
+		values = append(values, result.page.Values...)
+	}
+
+	return fetchPages(values, results[last].page, fetch)
+}
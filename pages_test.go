@@ -0,0 +1,97 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestListTagsWithPagePrefetch(t *testing.T) {
+	const total = 60
+
+	var requests int32
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		response := Tags{}
+		for i := start; i < start+limit && i < total; i++ {
+			response.Values = append(response.Values, Tag{DisplayID: fmt.Sprint(i)})
+		}
+		response.Start = start
+		response.Size = len(response.Values)
+		response.IsLastPage = start+limit >= total
+		response.NextPageStart = start + limit
+
+		json.NewEncoder(w).Encode(response)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithPagePrefetch(4))
+	tags, err := stashClient.ListTags("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(tags) != total {
+		t.Fatalf("Want %d tags but got %d\n", total, len(tags))
+	}
+	for i, tag := range tags {
+		if tag.DisplayID != fmt.Sprint(i) {
+			t.Fatalf("Want tag %d at position %d but got %s\n", i, i, tag.DisplayID)
+		}
+	}
+	if atomic.LoadInt32(&requests) < 3 {
+		t.Fatalf("Want at least 3 requests but got %d\n", requests)
+	}
+}
+
+func TestPagePrefetchShortPages(t *testing.T) {
+	const total = 100
+
+	for _, capped := range []int{10, 50} {
+		testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+			// server returns less values than asked for after capped
+			if start >= capped {
+				limit = 10
+			}
+
+			response := Tags{}
+			for i := start; i < start+limit && i < total; i++ {
+				response.Values = append(response.Values, Tag{DisplayID: fmt.Sprint(i)})
+			}
+			response.IsLastPage = start+limit >= total
+			response.NextPageStart = start + limit
+
+			json.NewEncoder(w).Encode(response)
+		}))
+
+		url, _ := url.Parse(testServer.URL)
+		tags, err := NewClient("u", "p", url, WithPagePrefetch(4)).
+			ListTags("PRJ", "widge")
+		testServer.Close()
+		if err != nil {
+			t.Fatalf("Not expecting error: %v\n", err)
+		}
+
+		if len(tags) != total {
+			t.Fatalf("Want %d tags but got %d\n", total, len(tags))
+		}
+		for i, tag := range tags {
+			if tag.DisplayID != fmt.Sprint(i) {
+				t.Fatalf("Want tag %d at position %d but got %s\n", i, i, tag.DisplayID)
+			}
+		}
+	}
+}
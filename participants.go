@@ -0,0 +1,44 @@
+package stash
+
+import "net/http"
+
+// SetParticipantStatus sets review status of the pull request participant,
+// server allows to change it only for the authenticated user, whose slug
+// is looked up with GetCurrentUser if userSlug is empty. User is added as a
+// participant if needed.
+func (client Client) SetParticipantStatus(
+	projectKey, repositorySlug, identifier, userSlug string,
+	status ParticipantStatus,
+) (Reviewer, error) {
+	user := User{Name: userSlug, Slug: userSlug}
+	if userSlug == "" {
+		var err error
+		user, err = client.GetCurrentUser()
+		if err != nil {
+			return Reviewer{}, err
+		}
+	}
+
+	payload := Reviewer{
+		User:     User{Name: user.Name, Slug: user.Slug},
+		Approved: status == ParticipantStatusApproved,
+		Status:   status,
+	}
+
+	var response Reviewer
+	err := client.requestJSON(
+		"PUT",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/participants/%s",
+			projectKey, repositorySlug, identifier, user.Slug,
+		),
+		payload,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Reviewer{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,55 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSetParticipantStatus(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/application-properties":
+			w.Header().Set("X-AUSERNAME", "bob@example.com")
+			fmt.Fprint(w, `{"version": "8.9.0"}`)
+			return
+		case "/rest/api/1.0/users":
+			if r.URL.Query().Get("filter") != "bob@example.com" {
+				t.Fatalf("Unexpected filter %s\n", r.URL.Query().Get("filter"))
+			}
+			fmt.Fprint(w, `{"isLastPage": true, "values": [
+				{"name": "bob@example.com.au", "slug": "bob_example.com.au"},
+				{"name": "bob@example.com", "slug": "bob_example.com"}
+			]}`)
+			return
+		}
+
+		wantPath := "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/42/participants/bob_example.com"
+		if r.Method != "PUT" || r.URL.Path != wantPath {
+			t.Fatalf("Want PUT %s but found %s %s\n", wantPath, r.Method, r.URL.Path)
+		}
+
+		var payload Reviewer
+		json.NewDecoder(r.Body).Decode(&payload)
+		if payload.Status != ParticipantStatusNeedsWork || payload.Approved || payload.User.Name != "bob@example.com" {
+			t.Fatalf("Unexpected payload %+v\n", payload)
+		}
+
+		fmt.Fprint(w, `{"user": {"name": "bob"}, "role": "REVIEWER", "status": "NEEDS_WORK"}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("", "", url, WithTrustedHeader("X-Forwarded-User", "bob@example.com"))
+	participant, err := stashClient.Project("PRJ").Repo("widge").PullRequest(42).
+		SetStatus(ParticipantStatusNeedsWork)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if participant.Status != ParticipantStatusNeedsWork || participant.Role != ParticipantRoleReviewer {
+		t.Fatalf("Want reviewer with NEEDS_WORK status but got %+v\n", participant)
+	}
+}
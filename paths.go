@@ -0,0 +1,43 @@
+package stash
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+)
+
+// emptySegment replaces empty arguments of pathf, so validateRequest can
+// reject such paths before sending them. It's a control character, so path
+// containing it can't be sent by accident.
+const emptySegment = "\x00"
+
+// rawPath is a part of request path which is already escaped, e.g. encoded
+// query or file path, so pathf passes it as is.
+type rawPath string
+
+// pathf formats request path like fmt.Sprintf does, but escapes string
+// arguments as path segments, so project keys, slugs, branch names like
+// feature/foo#1 and other user input can't break the path or leak into
+// query. Query values must be encoded with url.Values and passed as rawPath.
+func pathf(format string, args ...any) string {
+	escaped := make([]any, len(args))
+	for i, arg := range args {
+		escaped[i] = arg
+
+		if _, ok := arg.(rawPath); ok {
+			continue
+		}
+
+		// typed strings like PullRequestState are escaped as well
+		value := reflect.ValueOf(arg)
+		if value.Kind() == reflect.String {
+			if value.Len() == 0 {
+				escaped[i] = emptySegment
+			} else {
+				escaped[i] = url.PathEscape(value.String())
+			}
+		}
+	}
+
+	return fmt.Sprintf(format, escaped...)
+}
@@ -0,0 +1,43 @@
+package stash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestPathf(t *testing.T) {
+	path := pathf(
+		"/rest/api/1.0/projects/%s/repos/%s/branches/%s?%s",
+		"~BOB", "my repo", "feature/foo#1", rawPath("at=a%2Fb"),
+	)
+	want := "/rest/api/1.0/projects/~BOB/repos/my%20repo/branches/feature%2Ffoo%231?at=a%2Fb"
+	if path != want {
+		t.Fatalf("Want %s but got %s\n", want, path)
+	}
+
+	path = pathf("/rest/api/1.0/projects/%s/repos?state=%s&limit=%d", "PRJ", PullRequestStateOpen, 10)
+	if path != "/rest/api/1.0/projects/PRJ/repos?state=OPEN&limit=10" {
+		t.Fatalf("Unexpected path %s\n", path)
+	}
+}
+
+func TestEscapedRequestPath(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "/rest/api/1.0/projects/PRJ/repos/widge/labels/needs%20review"
+		if r.URL.EscapedPath() != want {
+			t.Fatalf("Want %s but found %s\n", want, r.URL.EscapedPath())
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	err := stashClient.RemoveRepositoryLabel("PRJ", "widge", "needs review")
+	if err != nil {
+		t.Fatalf("RemoveRepositoryLabel() not expecting an error, but received: %v\n", err)
+	}
+}
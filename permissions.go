@@ -0,0 +1,253 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type (
+	UserPermission struct {
+		User       User       `json:"user"`
+		Permission Permission `json:"permission"`
+	}
+
+	GroupPermission struct {
+		Group      Group      `json:"group"`
+		Permission Permission `json:"permission"`
+	}
+
+	Group struct {
+		Name string `json:"name"`
+		// Deletable is false for groups from read-only directories.
+		Deletable bool `json:"deletable,omitempty"`
+	}
+)
+
+// GetGlobalUserPermissions returns users which are granted global
+// permission explicitly.
+func (client Client) GetGlobalUserPermissions() ([]UserPermission, error) {
+	return listPermissions[UserPermission](
+		client, "/rest/api/1.0/admin/permissions/users",
+	)
+}
+
+// GetGlobalGroupPermissions returns groups which are granted global
+// permission explicitly.
+func (client Client) GetGlobalGroupPermissions() ([]GroupPermission, error) {
+	return listPermissions[GroupPermission](
+		client, "/rest/api/1.0/admin/permissions/groups",
+	)
+}
+
+// GetProjectUserPermissions returns users which are granted permission
+// on the given project explicitly.
+func (client Client) GetProjectUserPermissions(
+	projectKey string,
+) ([]UserPermission, error) {
+	return listPermissions[UserPermission](
+		client,
+		pathf("/rest/api/1.0/projects/%s/permissions/users", projectKey),
+	)
+}
+
+// GetProjectGroupPermissions returns groups which are granted permission
+// on the given project explicitly.
+func (client Client) GetProjectGroupPermissions(
+	projectKey string,
+) ([]GroupPermission, error) {
+	return listPermissions[GroupPermission](
+		client,
+		pathf("/rest/api/1.0/projects/%s/permissions/groups", projectKey),
+	)
+}
+
+// GetRepositoryUserPermissions returns users which are granted permission
+// on the given repository explicitly.
+func (client Client) GetRepositoryUserPermissions(
+	projectKey, repositorySlug string,
+) ([]UserPermission, error) {
+	return listPermissions[UserPermission](
+		client,
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/permissions/users",
+			projectKey, repositorySlug,
+		),
+	)
+}
+
+// GetRepositoryGroupPermissions returns groups which are granted permission
+// on the given repository explicitly.
+func (client Client) GetRepositoryGroupPermissions(
+	projectKey, repositorySlug string,
+) ([]GroupPermission, error) {
+	return listPermissions[GroupPermission](
+		client,
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/permissions/groups",
+			projectKey, repositorySlug,
+		),
+	)
+}
+
+func listPermissions[T any](client Client, path string) ([]T, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[T], error) {
+			var response Paged[T]
+			err := client.requestJSON(
+				"GET",
+				fmt.Sprintf("%s?start=%d&limit=%d", path, start, limit),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+func (client Client) GrantRepositoryGroupPermission(
+	projectKey, repositorySlug, group string,
+	permission Permission,
+) error {
+	payload := url.Values{}
+	payload.Set("name", group)
+	payload.Set("permission", string(permission))
+	_, err := client.request(
+		"PUT", pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/permissions/groups?%s",
+			projectKey, repositorySlug, rawPath(payload.Encode()),
+		),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+func (client Client) RevokeRepositoryGroupPermission(
+	projectKey, repositorySlug, group string,
+) error {
+	_, err := client.request(
+		"DELETE", pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/permissions/groups?name=%s",
+			projectKey, repositorySlug, rawPath(url.QueryEscape(group)),
+		),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+func (client Client) GrantProjectUserPermission(
+	projectKey, user string,
+	permission Permission,
+) error {
+	return client.grantPermission(
+		pathf("/rest/api/1.0/projects/%s/permissions/users", projectKey),
+		user, permission,
+	)
+}
+
+func (client Client) RevokeProjectUserPermission(projectKey, user string) error {
+	return client.revokePermission(
+		pathf("/rest/api/1.0/projects/%s/permissions/users", projectKey),
+		user,
+	)
+}
+
+func (client Client) GrantProjectGroupPermission(
+	projectKey, group string,
+	permission Permission,
+) error {
+	return client.grantPermission(
+		pathf("/rest/api/1.0/projects/%s/permissions/groups", projectKey),
+		group, permission,
+	)
+}
+
+func (client Client) RevokeProjectGroupPermission(
+	projectKey, group string,
+) error {
+	return client.revokePermission(
+		pathf("/rest/api/1.0/projects/%s/permissions/groups", projectKey),
+		group,
+	)
+}
+
+// GetProjectDefaultPermission tells whether all licensed users are granted
+// the permission on the project, which is PermissionProjectRead or
+// PermissionProjectWrite.
+func (client Client) GetProjectDefaultPermission(
+	projectKey string,
+	permission Permission,
+) (bool, error) {
+	var response struct {
+		Permitted bool `json:"permitted"`
+	}
+
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/permissions/%s/all",
+			projectKey, permission,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return response.Permitted, nil
+}
+
+// SetProjectDefaultPermission grants or revokes the permission on the
+// project for all licensed users, see GetProjectDefaultPermission.
+func (client Client) SetProjectDefaultPermission(
+	projectKey string,
+	permission Permission,
+	allow bool,
+) error {
+	_, err := client.request(
+		"POST", pathf(
+			"/rest/api/1.0/projects/%s/permissions/%s/all?allow=%t",
+			projectKey, permission, allow,
+		),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+// grantPermission grants permission to user or group using the permissions
+// endpoint at path.
+func (client Client) grantPermission(
+	path, name string,
+	permission Permission,
+) error {
+	payload := url.Values{}
+	payload.Set("name", name)
+	payload.Set("permission", string(permission))
+	_, err := client.request(
+		"PUT", path+"?"+payload.Encode(),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+func (client Client) revokePermission(path, name string) error {
+	_, err := client.request(
+		"DELETE", path+"?name="+url.QueryEscape(name),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
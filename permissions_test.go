@@ -0,0 +1,64 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestProjectPermissions(t *testing.T) {
+	var requests []string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+
+		if r.Method == "GET" {
+			fmt.Fprint(w, `{"permitted": true}`)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	project := stashClient.Project("PRJ")
+
+	for _, err := range []error{
+		project.GrantUserPermission("alice", PermissionProjectWrite),
+		project.RevokeUserPermission("alice"),
+		project.GrantGroupPermission("dev ops", PermissionProjectAdmin),
+		project.RevokeGroupPermission("dev ops"),
+		stashClient.SetProjectDefaultPermission("PRJ", PermissionProjectRead, true),
+	} {
+		if err != nil {
+			t.Fatalf("Not expecting error: %v\n", err)
+		}
+	}
+
+	permitted, err := stashClient.GetProjectDefaultPermission("PRJ", PermissionProjectRead)
+	if err != nil || !permitted {
+		t.Fatalf("Want read permitted for all users but got %t, %v\n", permitted, err)
+	}
+
+	want := []string{
+		"PUT /rest/api/1.0/projects/PRJ/permissions/users?name=alice&permission=PROJECT_WRITE",
+		"DELETE /rest/api/1.0/projects/PRJ/permissions/users?name=alice",
+		"PUT /rest/api/1.0/projects/PRJ/permissions/groups?name=dev+ops&permission=PROJECT_ADMIN",
+		"DELETE /rest/api/1.0/projects/PRJ/permissions/groups?name=dev+ops",
+		"POST /rest/api/1.0/projects/PRJ/permissions/PROJECT_READ/all?allow=true",
+		"GET /rest/api/1.0/projects/PRJ/permissions/PROJECT_READ/all?",
+	}
+
+	if len(requests) != len(want) {
+		t.Fatalf("Want %d requests but got %q\n", len(want), requests)
+	}
+
+	for i := range want {
+		if requests[i] != want[i] {
+			t.Fatalf("Want %s but got %s\n", want[i], requests[i])
+		}
+	}
+}
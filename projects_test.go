@@ -0,0 +1,52 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestProjectCRUD(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PRJ" {
+			t.Fatalf("Want /rest/api/1.0/projects/PRJ but found %s\n", r.URL.Path)
+		}
+
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"id": 1, "key": "PRJ", "name": "Project", "description": "old", "public": false, "type": "NORMAL"}`)
+		case "PUT":
+			var payload map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&payload)
+			if fmt.Sprint(payload) != "map[description:new public:true]" {
+				t.Fatalf("Want only description and visibility changed but got %v\n", payload)
+			}
+			fmt.Fprint(w, `{"id": 1, "key": "PRJ", "name": "Project", "description": "new", "public": true}`)
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	project := NewClient("u", "p", url).Project("PRJ")
+
+	got, err := project.Get()
+	if err != nil || got.Description != "old" || got.Type != "NORMAL" {
+		t.Fatalf("Want project details but got %+v, %v\n", got, err)
+	}
+
+	description, public := "new", true
+	got, err = project.Update(ProjectUpdate{Description: &description, Public: &public})
+	if err != nil || got.Description != "new" || !got.Public {
+		t.Fatalf("Want updated project but got %+v, %v\n", got, err)
+	}
+
+	err = project.Delete()
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+}
@@ -0,0 +1,115 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type (
+	// PullRequestTask is a checklist item attached to pull request comment.
+	PullRequestTask struct {
+		ID          int        `json:"id,omitempty"`
+		Text        string     `json:"text,omitempty"`
+		State       TaskState  `json:"state,omitempty"`
+		Author      User       `json:"author"`
+		CreatedDate int64      `json:"createdDate,omitempty"`
+		Anchor      TaskAnchor `json:"anchor"`
+	}
+
+	// createTaskPayload is what server expects to create task, server sets
+	// author itself.
+	createTaskPayload struct {
+		Text   string     `json:"text"`
+		Anchor TaskAnchor `json:"anchor"`
+	}
+
+	// updateTaskPayload changes only given fields of the task, author and
+	// anchor can't be changed.
+	updateTaskPayload struct {
+		ID    int       `json:"id"`
+		Text  string    `json:"text,omitempty"`
+		State TaskState `json:"state,omitempty"`
+	}
+
+	// TaskAnchor is a comment the task is attached to.
+	TaskAnchor struct {
+		ID   int    `json:"id"`
+		Type string `json:"type"`
+	}
+)
+
+// GetPullRequestTasks returns tasks attached to comments of the pull request.
+func (client Client) GetPullRequestTasks(
+	projectKey, repositorySlug, identifier string,
+) ([]PullRequestTask, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[PullRequestTask], error) {
+			var response Paged[PullRequestTask]
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/tasks?start=%d&limit=%d",
+					projectKey, repositorySlug, identifier, start, limit,
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+// CreateTask attaches a new open task to the pull request comment.
+func (client Client) CreateTask(commentID int, text string) (PullRequestTask, error) {
+	return client.saveTask("POST", "/rest/api/1.0/tasks", createTaskPayload{
+		Text:   text,
+		Anchor: TaskAnchor{ID: commentID, Type: "COMMENT"},
+	})
+}
+
+// UpdateTask changes text and state of the task with the given ID, empty
+// fields are left as is.
+func (client Client) UpdateTask(task PullRequestTask) (PullRequestTask, error) {
+	return client.saveTask(
+		"PUT",
+		fmt.Sprintf("/rest/api/1.0/tasks/%d", task.ID),
+		updateTaskPayload{ID: task.ID, Text: task.Text, State: task.State},
+	)
+}
+
+// ResolveTask marks the task as resolved.
+func (client Client) ResolveTask(id int) (PullRequestTask, error) {
+	return client.UpdateTask(PullRequestTask{ID: id, State: TaskStateResolved})
+}
+
+func (client Client) DeleteTask(id int) error {
+	_, err := client.request(
+		"DELETE",
+		fmt.Sprintf("/rest/api/1.0/tasks/%d", id),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+func (client Client) saveTask(
+	method, path string,
+	payload interface{},
+) (PullRequestTask, error) {
+	var response PullRequestTask
+	err := client.requestJSON(
+		method, path,
+		payload,
+		&response,
+		http.StatusOK,
+		http.StatusCreated,
+	)
+	if err != nil {
+		return PullRequestTask{}, err
+	}
+
+	return response, nil
+}
@@ -0,0 +1,62 @@
+package stash
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestPullRequestTasks(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/1.0/projects/PRJ/repos/widge/pull-requests/42/tasks":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [
+				{"id": 5, "text": "Fix typo", "state": "OPEN", "anchor": {"id": 17, "type": "COMMENT"}}
+			]}`)
+		case "POST /rest/api/1.0/tasks":
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != `{"text":"Add tests","anchor":{"id":17,"type":"COMMENT"}}` {
+				t.Fatalf("Want task anchored to comment 17 but got %s\n", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": 6, "text": "Add tests", "state": "OPEN"}`)
+		case "PUT /rest/api/1.0/tasks/5":
+			body, _ := ioutil.ReadAll(r.Body)
+			if string(body) != `{"id":5,"state":"RESOLVED"}` {
+				t.Fatalf("Want only state to be resolved but got %s\n", body)
+			}
+			fmt.Fprint(w, `{"id": 5, "text": "Fix typo", "state": "RESOLVED"}`)
+		case "DELETE /rest/api/1.0/tasks/6":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	tasks, err := stashClient.Project("PRJ").Repo("widge").PullRequest(42).Tasks()
+	if err != nil || len(tasks) != 1 || tasks[0].Anchor.ID != 17 {
+		t.Fatalf("Want single task on comment 17 but got %+v, %v\n", tasks, err)
+	}
+
+	task, err := stashClient.CreateTask(17, "Add tests")
+	if err != nil || task.ID != 6 || task.State != TaskStateOpen {
+		t.Fatalf("Want created open task but got %+v, %v\n", task, err)
+	}
+
+	task, err = stashClient.ResolveTask(5)
+	if err != nil || task.State != TaskStateResolved {
+		t.Fatalf("Want resolved task but got %+v, %v\n", task, err)
+	}
+
+	err = stashClient.DeleteTask(6)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+}
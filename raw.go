@@ -0,0 +1,113 @@
+package stash
+
+import "encoding/json"
+
+// Entities returned by the server keep the JSON they were decoded from in
+// the Raw field, so fields which are not covered by the typed models (e.g.
+// added by plugins) are still accessible without querying server again:
+//
+//	var extra struct {
+//	    Public bool `json:"public"`
+//	}
+//	err := json.Unmarshal(repository.Raw, &extra)
+//
+// Only entities which are not comparable anyway keep JSON: Repository,
+// PullRequest and Commit. Project, Branch and Tag are compared with == and
+// used as map keys, so they keep only typed fields.
+
+func (repo *Repository) UnmarshalJSON(data []byte) error {
+	type plain Repository
+	err := json.Unmarshal(data, (*plain)(repo))
+	if err != nil {
+		return err
+	}
+
+	repo.Raw = copyRaw(data)
+
+	return nil
+}
+
+// UnmarshalJSON keeps only self link of the project, see SelfURL.
+func (project *Project) UnmarshalJSON(data []byte) error {
+	type plain Project
+	err := json.Unmarshal(data, (*plain)(project))
+	if err != nil {
+		return err
+	}
+
+	var links struct {
+		Links Links `json:"links"`
+	}
+	err = json.Unmarshal(data, &links)
+	if err != nil {
+		return err
+	}
+
+	project.SelfURL = links.Links.self()
+
+	return nil
+}
+
+func (pr *PullRequest) UnmarshalJSON(data []byte) error {
+	type plain PullRequest
+	err := json.Unmarshal(data, (*plain)(pr))
+	if err != nil {
+		return err
+	}
+
+	pr.Raw = copyRaw(data)
+
+	return nil
+}
+
+func (commit *Commit) UnmarshalJSON(data []byte) error {
+	type plain Commit
+	err := json.Unmarshal(data, (*plain)(commit))
+	if err != nil {
+		return err
+	}
+
+	commit.Raw = copyRaw(data)
+
+	return nil
+}
+
+// UnmarshalJSON fills deprecated EmailAddress along with Email.
+func (author *CommitAuthor) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, &author.User)
+	if err != nil {
+		return err
+	}
+
+	author.EmailAddress = author.Email
+
+	return nil
+}
+
+// UnmarshalJSON is required since MergeResult embeds PullRequest, which
+// would otherwise shadow decoding of Errors.
+func (result *MergeResult) UnmarshalJSON(data []byte) error {
+	err := json.Unmarshal(data, &result.PullRequest)
+	if err != nil {
+		return err
+	}
+
+	var errors struct {
+		Errors json.RawMessage
+	}
+	err = json.Unmarshal(data, &errors)
+	if err != nil {
+		return err
+	}
+
+	if len(errors.Errors) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(errors.Errors, &result.Errors)
+}
+
+// copyRaw copies data since decoder may reuse underlying buffer.
+func copyRaw(data []byte) json.RawMessage {
+	return append(json.RawMessage(nil), data...)
+}
@@ -0,0 +1,78 @@
+package stash
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRepositoryRaw(t *testing.T) {
+	var repository Repository
+	err := json.Unmarshal([]byte(`{"id": 1, "slug": "widge", "public": true, "project": {"key": "PRJ", "type": "NORMAL"}}`), &repository)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if repository.Slug != "widge" {
+		t.Fatalf("Want widge but got %s\n", repository.Slug)
+	}
+
+	var extra struct {
+		Public bool `json:"public"`
+	}
+	if err := json.Unmarshal(repository.Raw, &extra); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if !extra.Public {
+		t.Fatalf("Want public repository but got %s\n", repository.Raw)
+	}
+
+	if repository.Project != (Project{Key: "PRJ", Type: "NORMAL"}) {
+		t.Fatalf("Want decoded project equal to built one but got %+v\n", repository.Project)
+	}
+}
+
+// Project, Branch and Tag must stay comparable, this fails to compile
+// otherwise.
+var (
+	_ = Project{} == Project{}
+	_ = Branch{} == Branch{}
+	_ = Tag{} == Tag{}
+)
+
+func TestBranchComparable(t *testing.T) {
+	var branch Branch
+	err := json.Unmarshal([]byte(`{"id": "refs/heads/master",  "displayId": "master"}`), &branch)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if branch != (Branch{ID: "refs/heads/master", DisplayID: "master"}) {
+		t.Fatalf("Want decoded branch equal to built one but got %+v\n", branch)
+	}
+}
+
+func TestMergeResultErrors(t *testing.T) {
+	var result MergeResult
+	err := json.Unmarshal([]byte(`{"errors": [{"conflicted": true, "message": "conflict", "vetoes": [{"summaryMessage": "needs approval"}]}]}`), &result)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if len(result.Errors) != 1 || !result.Errors[0].Conflicted {
+		t.Fatalf("Want conflicted error but got %+v\n", result.Errors)
+	}
+	if result.Errors[0].Vetoes[0].SummaryMessage != "needs approval" {
+		t.Fatalf("Want veto but got %+v\n", result.Errors[0].Vetoes)
+	}
+}
+
+func TestCommitAuthorEmailAddress(t *testing.T) {
+	var commit Commit
+	err := json.Unmarshal([]byte(`{"id": "e680a10", "author": {"name": "alice", "emailAddress": "alice@example.com"}}`), &commit)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if commit.Author.Email != "alice@example.com" || commit.Author.EmailAddress != "alice@example.com" {
+		t.Fatalf("Want author email in both fields but got %+v\n", commit.Author)
+	}
+	if len(commit.Raw) == 0 {
+		t.Fatalf("Want raw commit kept\n")
+	}
+}
@@ -0,0 +1,586 @@
+package stash
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/reconquest/karma-go"
+)
+
+type (
+	// RepositorySpec is a desired state of repository configuration. Zero
+	// fields are not managed and left as they are on the server. Non-nil
+	// maps and slices are authoritative: anything present on the server but
+	// missing in the spec is removed.
+	RepositorySpec struct {
+		ProjectKey string
+		Slug       string
+
+		Description *string
+		Public      *bool
+		Forkable    *bool
+
+		DefaultBranch string
+
+		// UserPermissions and GroupPermissions map user and group names
+		// to permissions.
+		UserPermissions  map[string]Permission
+		GroupPermissions map[string]Permission
+
+		// Webhooks are matched to existing ones by name.
+		Webhooks []Webhook
+
+		// RefRestrictions are matched to existing ones by type and matcher.
+		RefRestrictions []RefRestrictionOptions
+
+		PullRequestSettings *PullRequestSettings
+	}
+
+	// RepositoryChange is a single change needed to bring repository to
+	// the desired state.
+	RepositoryChange struct {
+		Action ChangeAction
+		// Resource is a kind of changed configuration, like "webhook".
+		Resource string
+		// Name identifies changed resource, like webhook name.
+		Name string
+
+		apply func() error
+	}
+
+	// RepositoryPlan is a list of changes in order they are applied.
+	RepositoryPlan struct {
+		ProjectKey string
+		Slug       string
+		Changes    []RepositoryChange
+	}
+)
+
+func (change RepositoryChange) String() string {
+	if change.Name == "" {
+		return fmt.Sprintf("%s %s", change.Action, change.Resource)
+	}
+
+	return fmt.Sprintf("%s %s %s", change.Action, change.Resource, change.Name)
+}
+
+// Empty reports whether repository already is in the desired state.
+func (plan RepositoryPlan) Empty() bool {
+	return len(plan.Changes) == 0
+}
+
+// Apply makes planned changes one by one using client which made the plan,
+// stopping at the first failed one.
+func (plan RepositoryPlan) Apply() error {
+	for _, change := range plan.Changes {
+		err := change.apply()
+		if err != nil {
+			return karma.
+				Describe("repository", plan.ProjectKey+"/"+plan.Slug).
+				Describe("change", change.String()).
+				Reason(err)
+		}
+	}
+
+	return nil
+}
+
+// PlanRepository compares spec with the live repository and returns changes
+// needed to reconcile them without making any of them.
+func (client Client) PlanRepository(spec RepositorySpec) (RepositoryPlan, error) {
+	plan := RepositoryPlan{
+		ProjectKey: spec.ProjectKey,
+		Slug:       spec.Slug,
+	}
+
+	planners := []func(RepositorySpec) ([]RepositoryChange, error){
+		client.planRepositorySettings,
+		client.planDefaultBranch,
+		client.planUserPermissions,
+		client.planGroupPermissions,
+		client.planWebhooks,
+		client.planRefRestrictions,
+		client.planPullRequestSettings,
+	}
+
+	for _, planner := range planners {
+		changes, err := planner(spec)
+		if err != nil {
+			return RepositoryPlan{}, karma.
+				Describe("repository", spec.ProjectKey+"/"+spec.Slug).
+				Reason(err)
+		}
+
+		plan.Changes = append(plan.Changes, changes...)
+	}
+
+	return plan, nil
+}
+
+// ApplyRepository plans and applies changes needed to bring repository to
+// the state described by spec. Returned plan lists all changes, including
+// ones not applied due to error.
+func (client Client) ApplyRepository(spec RepositorySpec) (RepositoryPlan, error) {
+	plan, err := client.PlanRepository(spec)
+	if err != nil {
+		return RepositoryPlan{}, err
+	}
+
+	return plan, plan.Apply()
+}
+
+func (client Client) planRepositorySettings(
+	spec RepositorySpec,
+) ([]RepositoryChange, error) {
+	if spec.Description == nil && spec.Public == nil && spec.Forkable == nil {
+		return nil, nil
+	}
+
+	repository, err := client.GetRepository(spec.ProjectKey, spec.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	var update RepositoryUpdate
+	if spec.Description != nil && *spec.Description != repository.Description {
+		update.Description = spec.Description
+	}
+
+	if spec.Public != nil && *spec.Public != repository.Public {
+		update.Public = spec.Public
+	}
+
+	if spec.Forkable != nil && *spec.Forkable != repository.Forkable {
+		update.Forkable = spec.Forkable
+	}
+
+	if update == (RepositoryUpdate{}) {
+		return nil, nil
+	}
+
+	return []RepositoryChange{{
+		Action:   ChangeActionUpdate,
+		Resource: "settings",
+		apply: func() error {
+			_, err := client.UpdateRepository(spec.ProjectKey, spec.Slug, update)
+			return err
+		},
+	}}, nil
+}
+
+func (client Client) planDefaultBranch(
+	spec RepositorySpec,
+) ([]RepositoryChange, error) {
+	if spec.DefaultBranch == "" {
+		return nil, nil
+	}
+
+	branch, err := client.GetDefaultBranch(spec.ProjectKey, spec.Slug)
+	if err != nil && !IsNotFound(err) {
+		return nil, err
+	}
+
+	if branch.ID == branchRef(spec.DefaultBranch) {
+		return nil, nil
+	}
+
+	return []RepositoryChange{{
+		Action:   ChangeActionUpdate,
+		Resource: "default branch",
+		Name:     spec.DefaultBranch,
+		apply: func() error {
+			return client.SetDefaultBranch(
+				spec.ProjectKey, spec.Slug, spec.DefaultBranch,
+			)
+		},
+	}}, nil
+}
+
+func (client Client) planUserPermissions(
+	spec RepositorySpec,
+) ([]RepositoryChange, error) {
+	if spec.UserPermissions == nil {
+		return nil, nil
+	}
+
+	permissions, err := client.GetRepositoryUserPermissions(
+		spec.ProjectKey, spec.Slug,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]Permission{}
+	for _, permission := range permissions {
+		current[permission.User.Name] = permission.Permission
+	}
+
+	return planPermissions(
+		"user permission",
+		current,
+		spec.UserPermissions,
+		func(user string, permission Permission) error {
+			return client.GrantRepositoryUserPermission(
+				spec.ProjectKey, spec.Slug, user, permission,
+			)
+		},
+		func(user string) error {
+			return client.RevokeRepositoryUserPermission(
+				spec.ProjectKey, spec.Slug, user,
+			)
+		},
+	), nil
+}
+
+func (client Client) planGroupPermissions(
+	spec RepositorySpec,
+) ([]RepositoryChange, error) {
+	if spec.GroupPermissions == nil {
+		return nil, nil
+	}
+
+	permissions, err := client.GetRepositoryGroupPermissions(
+		spec.ProjectKey, spec.Slug,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]Permission{}
+	for _, permission := range permissions {
+		current[permission.Group.Name] = permission.Permission
+	}
+
+	return planPermissions(
+		"group permission",
+		current,
+		spec.GroupPermissions,
+		func(group string, permission Permission) error {
+			return client.GrantRepositoryGroupPermission(
+				spec.ProjectKey, spec.Slug, group, permission,
+			)
+		},
+		func(group string) error {
+			return client.RevokeRepositoryGroupPermission(
+				spec.ProjectKey, spec.Slug, group,
+			)
+		},
+	), nil
+}
+
+func planPermissions(
+	resource string,
+	current, desired map[string]Permission,
+	grant func(name string, permission Permission) error,
+	revoke func(name string) error,
+) []RepositoryChange {
+	var changes []RepositoryChange
+
+	for _, name := range sortedKeys(desired) {
+		permission := desired[name]
+
+		actual, ok := current[name]
+		if ok && actual == permission {
+			continue
+		}
+
+		action := ChangeActionCreate
+		if ok {
+			action = ChangeActionUpdate
+		}
+
+		changes = append(changes, RepositoryChange{
+			Action:   action,
+			Resource: resource,
+			Name:     name,
+			apply: func() error {
+				return grant(name, permission)
+			},
+		})
+	}
+
+	for _, name := range sortedKeys(current) {
+		if _, ok := desired[name]; ok {
+			continue
+		}
+
+		changes = append(changes, RepositoryChange{
+			Action:   ChangeActionDelete,
+			Resource: resource,
+			Name:     name,
+			apply: func() error {
+				return revoke(name)
+			},
+		})
+	}
+
+	return changes
+}
+
+func (client Client) planWebhooks(
+	spec RepositorySpec,
+) ([]RepositoryChange, error) {
+	if spec.Webhooks == nil {
+		return nil, nil
+	}
+
+	webhooks, err := client.GetWebhooks(spec.ProjectKey, spec.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	current := map[string]Webhook{}
+	for _, webhook := range webhooks {
+		current[webhook.Name] = webhook
+	}
+
+	var changes []RepositoryChange
+	desired := map[string]bool{}
+	for _, webhook := range spec.Webhooks {
+		desired[webhook.Name] = true
+
+		actual, ok := current[webhook.Name]
+		if !ok {
+			changes = append(changes, RepositoryChange{
+				Action:   ChangeActionCreate,
+				Resource: "webhook",
+				Name:     webhook.Name,
+				apply: func() error {
+					_, err := client.CreateWebhook(
+						spec.ProjectKey, spec.Slug, webhook,
+					)
+					return err
+				},
+			})
+
+			continue
+		}
+
+		if sameWebhook(actual, webhook) {
+			continue
+		}
+
+		webhook.ID = actual.ID
+		changes = append(changes, RepositoryChange{
+			Action:   ChangeActionUpdate,
+			Resource: "webhook",
+			Name:     webhook.Name,
+			apply: func() error {
+				_, err := client.UpdateWebhook(
+					spec.ProjectKey, spec.Slug, webhook,
+				)
+				return err
+			},
+		})
+	}
+
+	for _, webhook := range webhooks {
+		if desired[webhook.Name] {
+			continue
+		}
+
+		changes = append(changes, RepositoryChange{
+			Action:   ChangeActionDelete,
+			Resource: "webhook",
+			Name:     webhook.Name,
+			apply: func() error {
+				return client.DeleteWebhook(
+					spec.ProjectKey, spec.Slug, webhook.ID,
+				)
+			},
+		})
+	}
+
+	return changes, nil
+}
+
+func sameWebhook(actual, desired Webhook) bool {
+	if actual.URL != desired.URL || actual.Active != desired.Active {
+		return false
+	}
+
+	if !reflect.DeepEqual(sortedStrings(actual.Events), sortedStrings(desired.Events)) {
+		return false
+	}
+
+	// server doesn't return secrets, so secret is never compared and only
+	// configuration given in desired webhook is
+	for key, value := range desired.Configuration {
+		if key == webhookSecret {
+			continue
+		}
+
+		if actual.Configuration[key] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (client Client) planRefRestrictions(
+	spec RepositorySpec,
+) ([]RepositoryChange, error) {
+	if spec.RefRestrictions == nil {
+		return nil, nil
+	}
+
+	restrictions, err := client.GetRefRestrictions(spec.ProjectKey, spec.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	return planRefRestrictions(
+		client, spec.ProjectKey, spec.Slug,
+		restrictions, spec.RefRestrictions, true,
+	), nil
+}
+
+// planRefRestrictions returns changes which create desired restrictions
+// and, if prune is set, delete current restrictions which aren't desired.
+// Restriction with changed users or groups is deleted and created again.
+func planRefRestrictions(
+	client Client,
+	projectKey, repositorySlug string,
+	current []RefRestriction,
+	desired []RefRestrictionOptions,
+	prune bool,
+) []RepositoryChange {
+	existing := map[string]RefRestriction{}
+	for _, restriction := range current {
+		existing[refRestrictionKey(restriction.Options())] = restriction
+	}
+
+	create := func(options RefRestrictionOptions) error {
+		_, err := client.CreateRefRestriction(
+			projectKey, repositorySlug, options,
+		)
+		return err
+	}
+
+	var changes []RepositoryChange
+	wanted := map[string]bool{}
+	for _, options := range desired {
+		key := refRestrictionKey(options)
+		wanted[key] = true
+
+		actual, ok := existing[key]
+		if !ok {
+			changes = append(changes, RepositoryChange{
+				Action:   ChangeActionCreate,
+				Resource: "ref restriction",
+				Name:     key,
+				apply: func() error {
+					return create(options)
+				},
+			})
+
+			continue
+		}
+
+		if sameExemptions(actual.Options(), options) {
+			continue
+		}
+
+		changes = append(changes, RepositoryChange{
+			Action:   ChangeActionUpdate,
+			Resource: "ref restriction",
+			Name:     key,
+			apply: func() error {
+				err := client.DeleteRefRestriction(
+					projectKey, repositorySlug, actual.ID,
+				)
+				if err != nil {
+					return err
+				}
+
+				return create(options)
+			},
+		})
+	}
+
+	if !prune {
+		return changes
+	}
+
+	for _, restriction := range current {
+		key := refRestrictionKey(restriction.Options())
+		if wanted[key] {
+			continue
+		}
+
+		changes = append(changes, RepositoryChange{
+			Action:   ChangeActionDelete,
+			Resource: "ref restriction",
+			Name:     key,
+			apply: func() error {
+				return client.DeleteRefRestriction(
+					projectKey, repositorySlug, restriction.ID,
+				)
+			},
+		})
+	}
+
+	return changes
+}
+
+func refRestrictionKey(options RefRestrictionOptions) string {
+	return fmt.Sprintf(
+		"%s %s:%s",
+		options.Type, options.Matcher.Type.ID, options.Matcher.ID,
+	)
+}
+
+func sameExemptions(actual, desired RefRestrictionOptions) bool {
+	return reflect.DeepEqual(sortedStrings(actual.Users), sortedStrings(desired.Users)) &&
+		reflect.DeepEqual(sortedStrings(actual.Groups), sortedStrings(desired.Groups))
+}
+
+func (client Client) planPullRequestSettings(
+	spec RepositorySpec,
+) ([]RepositoryChange, error) {
+	if spec.PullRequestSettings == nil {
+		return nil, nil
+	}
+
+	settings, err := client.GetPullRequestSettings(spec.ProjectKey, spec.Slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if settings == *spec.PullRequestSettings {
+		return nil, nil
+	}
+
+	return []RepositoryChange{{
+		Action:   ChangeActionUpdate,
+		Resource: "pull request settings",
+		apply: func() error {
+			_, err := client.UpdatePullRequestSettings(
+				spec.ProjectKey, spec.Slug, *spec.PullRequestSettings,
+			)
+			return err
+		},
+	}}, nil
+}
+
+func sortedKeys(values map[string]Permission) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// sortedStrings returns sorted copy of values, treating nil as empty.
+func sortedStrings[T ~string](values []T) []T {
+	sorted := append([]T{}, values...)
+	slices.Sort(sorted)
+
+	return sorted
+}
@@ -0,0 +1,153 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func newReconcileServer(t *testing.T, mutations *[]string) *httptest.Server {
+	const base = "/rest/api/1.0/projects/PRJ/repos/widge"
+
+	responses := map[string]string{
+		"GET " + base:                       `{"slug": "widge", "description": "old", "public": false, "forkable": true}`,
+		"GET " + base + "/branches/default": `{"id": "refs/heads/master", "displayId": "master"}`,
+		"GET " + base + "/permissions/users": `{"isLastPage": true, "values": [
+			{"user": {"name": "alice@example.com", "slug": "alice_example.com"}, "permission": "REPO_WRITE"},
+			{"user": {"name": "bob", "slug": "bob"}, "permission": "REPO_READ"}
+		]}`,
+		"GET " + base + "/permissions/groups": `{"isLastPage": true, "values": []}`,
+		"GET " + base + "/webhooks": `{"isLastPage": true, "values": [
+			{"id": 1, "name": "ci", "url": "http://ci/hook", "events": ["repo:refs_changed"], "active": true},
+			{"id": 2, "name": "stale", "url": "http://old/hook", "events": ["repo:refs_changed"], "active": true}
+		]}`,
+		"GET /rest/branch-permissions/2.0/projects/PRJ/repos/widge/restrictions": `{"isLastPage": true, "values": [
+			{"id": 7, "type": "no-deletes", "matcher": {"id": "refs/heads/master", "type": {"id": "BRANCH"}}, "users": [], "groups": []}
+		]}`,
+		"GET " + base + "/settings/pull-requests": `{"requiredApprovers": 1}`,
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.Path
+		if r.Method == "GET" {
+			response, ok := responses[key]
+			if !ok {
+				t.Fatalf("Unexpected request %s\n", key)
+			}
+			fmt.Fprint(w, response)
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.URL.RawQuery != "" {
+			key += "?" + r.URL.RawQuery
+		}
+		*mutations = append(*mutations, key+" "+string(body))
+
+		switch r.Method {
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		case "PUT":
+			if r.URL.Path == base {
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{}`)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		case "POST":
+			if r.URL.Path == base+"/webhooks" {
+				w.WriteHeader(http.StatusCreated)
+			}
+			fmt.Fprint(w, `{}`)
+		}
+	}))
+}
+
+func TestPlanRepository(t *testing.T) {
+	var mutations []string
+	testServer := newReconcileServer(t, &mutations)
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	description := "widgets"
+	forkable := true
+	spec := RepositorySpec{
+		ProjectKey:    "PRJ",
+		Slug:          "widge",
+		Description:   &description,
+		Forkable:      &forkable,
+		DefaultBranch: "main",
+		UserPermissions: map[string]Permission{
+			"alice@example.com": PermissionRepoWrite,
+			"carol":             PermissionRepoAdmin,
+		},
+		Webhooks: []Webhook{
+			{
+				Name: "ci", URL: "http://ci/hook", Events: []WebhookEvent{WebhookEventRefsChanged}, Active: true,
+				Configuration: map[string]string{webhookSecret: "s3cret"},
+			},
+		},
+		RefRestrictions: []RefRestrictionOptions{
+			{
+				Type:    RefRestrictionNoDeletes,
+				Matcher: RefMatcher{ID: "refs/heads/master", Type: RefMatcherType{ID: RefMatcherBranch}},
+			},
+			{
+				Type:    RefRestrictionPullRequestOnly,
+				Matcher: RefMatcher{ID: "refs/heads/master", Type: RefMatcherType{ID: RefMatcherBranch}},
+				Groups:  []string{"release"},
+			},
+		},
+		PullRequestSettings: &PullRequestSettings{RequiredApprovers: 1},
+	}
+
+	plan, err := stashClient.PlanRepository(spec)
+	if err != nil {
+		t.Fatalf("PlanRepository() not expecting an error, but received: %v\n", err)
+	}
+
+	var changes []string
+	for _, change := range plan.Changes {
+		changes = append(changes, change.String())
+	}
+
+	want := []string{
+		"update settings",
+		"update default branch main",
+		"create user permission carol",
+		"delete user permission bob",
+		"delete webhook stale",
+		"create ref restriction pull-request-only BRANCH:refs/heads/master",
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("Want changes\n%v\nbut got\n%v\n", want, changes)
+	}
+	if len(mutations) != 0 {
+		t.Fatalf("Want no mutations while planning but got %v\n", mutations)
+	}
+
+	_, err = stashClient.ApplyRepository(spec)
+	if err != nil {
+		t.Fatalf("ApplyRepository() not expecting an error, but received: %v\n", err)
+	}
+
+	wantMutations := []string{
+		`PUT /rest/api/1.0/projects/PRJ/repos/widge {"description":"widgets"}`,
+		`PUT /rest/api/1.0/projects/PRJ/repos/widge/branches/default {"id":"refs/heads/main"}`,
+		`PUT /rest/api/1.0/projects/PRJ/repos/widge/permissions/users?name=carol&permission=REPO_ADMIN `,
+		`DELETE /rest/api/1.0/projects/PRJ/repos/widge/permissions/users?name=bob `,
+		`DELETE /rest/api/1.0/projects/PRJ/repos/widge/webhooks/2 `,
+		`POST /rest/branch-permissions/2.0/projects/PRJ/repos/widge/restrictions {"type":"pull-request-only","matcher":{"id":"refs/heads/master","type":{"id":"BRANCH"}},"users":[],"groups":["release"]}`,
+	}
+	if !reflect.DeepEqual(mutations, wantMutations) {
+		encoded, _ := json.MarshalIndent(mutations, "", "  ")
+		t.Fatalf("Unexpected mutations:\n%s\n", encoded)
+	}
+}
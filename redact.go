@@ -0,0 +1,54 @@
+package stash
+
+import (
+	"errors"
+	"net/url"
+
+	"github.com/reconquest/stash-go/internal/redact"
+)
+
+const redacted = redact.Marker
+
+var sensitiveKeys = redact.Keys
+
+func quoteKeys(keys []string) []string {
+	return redact.QuoteKeys(keys)
+}
+
+// redactURL hides password in user info and values of sensitive query
+// parameters, like UPM token, so URL can be put into errors and logs.
+func redactURL(raw string) string {
+	return redact.URL(raw)
+}
+
+// redactBody hides values of sensitive fields in JSON request or response
+// body. Body which isn't JSON is returned as is.
+func redactBody(data []byte) string {
+	return redact.Body(data)
+}
+
+// redactError hides sensitive parts of URL which net/http puts into
+// errors returned for failed requests.
+func redactError(err error) error {
+	var urlError *url.Error
+	if errors.As(err, &urlError) {
+		urlError.URL = redactURL(urlError.URL)
+	}
+
+	return err
+}
+
+// String hides password, so client can be printed safely.
+func (client Client) String() string {
+	var base string
+	if client.baseURL != nil {
+		base = client.baseURL.Redacted()
+	}
+
+	return "stash.Client{" + client.userName + "@" + base + "}"
+}
+
+// GoString works like String for %#v.
+func (client Client) GoString() string {
+	return client.String()
+}
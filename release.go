@@ -0,0 +1,377 @@
+package stash
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/reconquest/karma-go"
+)
+
+type (
+	// BranchingModel describes branches used for development and releases
+	// and prefixes of branches of each type.
+	BranchingModel struct {
+		Development *Branch `json:"development"`
+
+		// Production is nil if repository default branch is used.
+		Production *Branch `json:"production"`
+
+		// Types lists only enabled branch types.
+		Types []BranchType `json:"types"`
+	}
+
+	BranchType struct {
+		ID          BranchTypeID `json:"id"`
+		DisplayName string       `json:"displayName"`
+		Prefix      string       `json:"prefix"`
+	}
+
+	// DefaultReviewersQuery describes pull request default reviewers are
+	// resolved for.
+	DefaultReviewersQuery struct {
+		SourceRepositoryID int
+		SourceRefID        string
+		TargetRepositoryID int
+		TargetRefID        string
+	}
+
+	// ReleaseOptions configures RunRelease, only Version is required.
+	ReleaseOptions struct {
+		Version string
+
+		// Title and Description of release pull request, title defaults
+		// to "Release <version>".
+		Title       string
+		Description string
+
+		// Strategy overrides merge strategy configured for repository.
+		Strategy MergeStrategy
+
+		// Tag is a name of the tag created at merge commit, default is
+		// Version. TagMessage defaults to pull request title.
+		Tag        string
+		TagMessage string
+
+		// PollInterval is how often merge checks are polled while waiting
+		// for builds and approvals, default is 10 seconds.
+		PollInterval time.Duration
+
+		// Timeout limits waiting for builds and approvals, default is 1
+		// hour.
+		Timeout time.Duration
+	}
+
+	// ReleaseResult lists everything created by RunRelease, it's filled
+	// partially if release fails midway.
+	ReleaseResult struct {
+		Branch      Branch
+		PullRequest PullRequest
+		Tag         Tag
+	}
+)
+
+// GetBranchingModel returns branching model of the repository.
+func (client Client) GetBranchingModel(
+	projectKey, repositorySlug string,
+) (BranchingModel, error) {
+	var response BranchingModel
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/branch-utils/1.0/projects/%s/repos/%s/branchmodel",
+			projectKey, repositorySlug,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return BranchingModel{}, err
+	}
+
+	return response, nil
+}
+
+// Prefix returns prefix of branches of the given type, false is returned
+// if the type is disabled.
+func (model BranchingModel) Prefix(id BranchTypeID) (string, bool) {
+	for _, branchType := range model.Types {
+		if branchType.ID == id {
+			return branchType.Prefix, true
+		}
+	}
+
+	return "", false
+}
+
+// GetDefaultReviewers returns users which are added as reviewers to pull
+// request described by the query.
+func (client Client) GetDefaultReviewers(
+	projectKey, repositorySlug string,
+	query DefaultReviewersQuery,
+) ([]User, error) {
+	values := url.Values{}
+	values.Set("sourceRepoId", strconv.Itoa(query.SourceRepositoryID))
+	values.Set("sourceRefId", query.SourceRefID)
+	values.Set("targetRepoId", strconv.Itoa(query.TargetRepositoryID))
+	values.Set("targetRefId", query.TargetRefID)
+
+	var response []User
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/default-reviewers/1.0/projects/%s/repos/%s/reviewers?%s",
+			projectKey, repositorySlug, rawPath(values.Encode()),
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// RunRelease performs release of the given version:
+//
+//   - creates release branch from the development branch of the branching
+//     model;
+//   - opens pull request to the production branch, or the default branch,
+//     with default reviewers;
+//   - waits until merge checks, like required builds and approvals, pass;
+//   - merges pull request and tags the merge commit.
+//
+// Release fails early if pull request has conflicts or builds fail.
+func (client Client) RunRelease(
+	projectKey, repositorySlug string,
+	options ReleaseOptions,
+) (ReleaseResult, error) {
+	var result ReleaseResult
+
+	if options.Version == "" {
+		return result, ValidationError{Reason: "release version is empty"}
+	}
+
+	if options.Title == "" {
+		options.Title = "Release " + options.Version
+	}
+
+	if options.Tag == "" {
+		options.Tag = options.Version
+	}
+
+	if options.TagMessage == "" {
+		options.TagMessage = options.Title
+	}
+
+	if options.PollInterval == 0 {
+		options.PollInterval = 10 * time.Second
+	}
+
+	if options.Timeout == 0 {
+		options.Timeout = time.Hour
+	}
+
+	model, err := client.GetBranchingModel(projectKey, repositorySlug)
+	if err != nil {
+		return result, karma.Format(err, "get branching model")
+	}
+
+	prefix, ok := model.Prefix(BranchTypeRelease)
+	if !ok || model.Development == nil {
+		return result, errors.New(
+			"branching model has no release branches or development branch",
+		)
+	}
+
+	target := model.Production
+	if target == nil {
+		branch, err := client.GetDefaultBranch(projectKey, repositorySlug)
+		if err != nil {
+			return result, karma.Format(err, "get default branch")
+		}
+
+		target = &branch
+	}
+
+	repository, err := client.GetRepository(projectKey, repositorySlug)
+	if err != nil {
+		return result, err
+	}
+
+	result.Branch, err = client.CreateBranch(
+		projectKey, repositorySlug,
+		prefix+options.Version, model.Development.ID,
+	)
+	if err != nil {
+		return result, karma.
+			Describe("branch", prefix+options.Version).
+			Format(err, "create release branch")
+	}
+
+	reviewers, err := client.GetDefaultReviewers(
+		projectKey, repositorySlug,
+		DefaultReviewersQuery{
+			SourceRepositoryID: repository.ID,
+			SourceRefID:        result.Branch.ID,
+			TargetRepositoryID: repository.ID,
+			TargetRefID:        target.ID,
+		},
+	)
+	if err != nil {
+		return result, karma.Format(err, "get default reviewers")
+	}
+
+	ref := PullRequestRepository{
+		Slug:    repositorySlug,
+		Project: PullRequestProject{Key: projectKey},
+	}
+
+	createOptions := CreatePullRequestOptions{
+		Title:       options.Title,
+		Description: options.Description,
+		FromRef:     PullRequestRef{Id: result.Branch.ID, Repository: ref},
+		ToRef:       PullRequestRef{Id: target.ID, Repository: ref},
+	}
+
+	for _, reviewer := range reviewers {
+		createOptions.Reviewers = append(createOptions.Reviewers, reviewer.Name)
+	}
+
+	result.PullRequest, err = client.CreatePullRequestWithOptions(createOptions)
+	if err != nil {
+		return result, karma.Format(err, "create release pull request")
+	}
+
+	identifier := strconv.Itoa(result.PullRequest.ID)
+
+	err = client.waitMergeable(projectKey, repositorySlug, result.PullRequest, options)
+	if err != nil {
+		return result, karma.
+			Describe("pull_request", identifier).
+			Reason(err)
+	}
+
+	err = client.RetryPullRequest(
+		projectKey, repositorySlug, identifier, 0,
+		func(pullRequest PullRequest) error {
+			merged, err := client.MergePullRequestWithOptions(
+				projectKey, repositorySlug, identifier,
+				MergeOptions{
+					Version:  pullRequest.Version,
+					Strategy: options.Strategy,
+				},
+			)
+			if err == nil {
+				result.PullRequest = merged
+			}
+
+			return err
+		},
+	)
+	if err != nil {
+		return result, karma.
+			Describe("pull_request", identifier).
+			Format(err, "merge release pull request")
+	}
+
+	// merge commit is reported by Bitbucket 5.x or newer, otherwise target
+	// branch is tagged as it points to the merge commit right after merge
+	startPoint := mergeCommit(result.PullRequest)
+	if startPoint == "" {
+		startPoint = target.ID
+	}
+
+	result.Tag, err = client.CreateTag(
+		projectKey, repositorySlug, options.Tag, startPoint, options.TagMessage,
+	)
+	if err != nil {
+		return result, karma.
+			Describe("tag", options.Tag).
+			Format(err, "tag merge commit")
+	}
+
+	return result, nil
+}
+
+// waitMergeable polls merge checks of the pull request until they pass,
+// failing on conflicts, failed builds or timeout.
+func (client Client) waitMergeable(
+	projectKey, repositorySlug string,
+	pullRequest PullRequest,
+	options ReleaseOptions,
+) error {
+	identifier := strconv.Itoa(pullRequest.ID)
+	deadline := time.Now().Add(options.Timeout)
+
+	for {
+		mergeability, err := client.GetPullRequestMergeability(
+			projectKey, repositorySlug, identifier,
+		)
+		if err != nil {
+			return err
+		}
+
+		if mergeability.CanMerge {
+			return nil
+		}
+
+		if mergeability.Conflicted {
+			return errors.New("release pull request has conflicts")
+		}
+
+		stats, err := client.GetBuildStatusStats(pullRequest.FromRef.LatestCommit)
+		if err != nil {
+			return err
+		}
+
+		if stats.Failed > 0 {
+			return karma.
+				Describe("commit", pullRequest.FromRef.LatestCommit).
+				Reason("builds of release branch failed")
+		}
+
+		if time.Now().After(deadline) {
+			context := karma.Describe("timeout", options.Timeout)
+			for _, veto := range mergeability.Vetoes {
+				context = context.Describe("veto", veto.SummaryMessage)
+			}
+
+			return context.Reason("release pull request can't be merged")
+		}
+
+		err = client.sleep(options.PollInterval)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// mergeCommit returns ID of the merge commit of merged pull request, it's
+// not covered by PullRequest fields since older servers don't report it.
+func mergeCommit(pullRequest PullRequest) string {
+	var raw struct {
+		Properties struct {
+			MergeCommit struct {
+				ID string `json:"id"`
+			} `json:"mergeCommit"`
+		} `json:"properties"`
+	}
+
+	if len(pullRequest.Raw) == 0 {
+		return ""
+	}
+
+	err := json.Unmarshal(pullRequest.Raw, &raw)
+	if err != nil {
+		return ""
+	}
+
+	return raw.Properties.MergeCommit.ID
+}
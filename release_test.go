@@ -0,0 +1,116 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestRunRelease(t *testing.T) {
+	var (
+		checks  int
+		created struct {
+			FromRef   PullRequestRef `json:"fromRef"`
+			ToRef     PullRequestRef `json:"toRef"`
+			Reviewers []Reviewer     `json:"reviewers"`
+		}
+		merge struct {
+			Version  int           `json:"version"`
+			Strategy MergeStrategy `json:"strategyId"`
+		}
+		tag struct {
+			Name       string `json:"name"`
+			StartPoint string `json:"startPoint"`
+		}
+	)
+
+	repo := "/rest/api/1.0/projects/PRJ/repos/widge"
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/branch-utils/1.0/projects/PRJ/repos/widge/branchmodel":
+			fmt.Fprint(w, `{
+				"development": {"id": "refs/heads/develop", "displayId": "develop"},
+				"production": {"id": "refs/heads/master", "displayId": "master"},
+				"types": [{"id": "RELEASE", "displayName": "Release", "prefix": "release/"}]
+			}`)
+		case "GET " + repo:
+			fmt.Fprint(w, `{"id": 42, "slug": "widge", "project": {"key": "PRJ"}}`)
+		case "POST " + repo + "/branches":
+			fmt.Fprint(w, `{"id": "refs/heads/release/1.2.0", "displayId": "release/1.2.0"}`)
+		case "GET /rest/default-reviewers/1.0/projects/PRJ/repos/widge/reviewers":
+			query := r.URL.Query()
+			if query.Get("sourceRefId") != "refs/heads/release/1.2.0" ||
+				query.Get("targetRefId") != "refs/heads/master" ||
+				query.Get("targetRepoId") != "42" {
+				t.Fatalf("Unexpected default reviewers query %s\n", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[{"name": "alice"}]`)
+		case "POST " + repo + "/pull-requests":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id": 7, "version": 0, "fromRef": {"latestCommit": "abc"}}`)
+		case "GET " + repo + "/pull-requests/7/merge":
+			checks++
+			if checks == 1 {
+				fmt.Fprint(w, `{"canMerge": false, "vetoes": [{"summaryMessage": "Not approved"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"canMerge": true, "outcome": "CLEAN"}`)
+		case "GET /rest/build-status/1.0/commits/stats/abc":
+			fmt.Fprint(w, `{"successful": 0, "inProgress": 1, "failed": 0}`)
+		case "GET " + repo + "/pull-requests/7":
+			fmt.Fprint(w, `{"id": 7, "version": 3}`)
+		case "POST " + repo + "/pull-requests/7/merge":
+			json.NewDecoder(r.Body).Decode(&merge)
+			fmt.Fprint(w, `{"id": 7, "version": 4, "state": "MERGED",
+				"properties": {"mergeCommit": {"id": "def"}}}`)
+		case "POST " + repo + "/tags":
+			json.NewDecoder(r.Body).Decode(&tag)
+			fmt.Fprint(w, `{"id": "refs/tags/1.2.0", "displayId": "1.2.0", "hash": "def"}`)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	result, err := stashClient.RunRelease("PRJ", "widge", ReleaseOptions{
+		Version:      "1.2.0",
+		Strategy:     MergeStrategySquash,
+		PollInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Want no error but got %s\n", err)
+	}
+
+	if created.FromRef.Id != "refs/heads/release/1.2.0" || created.ToRef.Id != "refs/heads/master" {
+		t.Fatalf("Want release/1.2.0 to master but got %s to %s\n", created.FromRef.Id, created.ToRef.Id)
+	}
+
+	if len(created.Reviewers) != 1 || created.Reviewers[0].User.Name != "alice" {
+		t.Fatalf("Want default reviewer alice but got %v\n", created.Reviewers)
+	}
+
+	if checks != 2 {
+		t.Fatalf("Want merge checks polled twice but got %d\n", checks)
+	}
+
+	if merge.Version != 3 || merge.Strategy != MergeStrategySquash {
+		t.Fatalf("Want squash merge of version 3 but got %+v\n", merge)
+	}
+
+	if tag.Name != "1.2.0" || tag.StartPoint != "def" {
+		t.Fatalf("Want tag 1.2.0 at def but got %+v\n", tag)
+	}
+
+	if result.PullRequest.State != PullRequestStateMerged || result.Tag.Hash != "def" {
+		t.Fatalf("Want merged pull request and tag but got %+v\n", result)
+	}
+}
@@ -0,0 +1,33 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestReopenPullRequest(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantPath := "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/7/reopen"
+		if r.Method != "POST" || r.URL.Path != wantPath {
+			t.Fatalf("Want POST %s but found %s %s\n", wantPath, r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("version") != "5" {
+			t.Fatalf("Want version=5 but found %s\n", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"id": 7, "version": 6, "state": "OPEN", "open": true}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	pullRequest, err := stashClient.ReopenPullRequest("PRJ", "widge", "7", 5)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if !pullRequest.Open || pullRequest.Version != 6 {
+		t.Fatalf("Want open pull request of version 6 but got %+v\n", pullRequest)
+	}
+}
@@ -1,6 +1,7 @@
 package stash
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -249,3 +250,67 @@ func TestGetRepositories500(t *testing.T) {
 		t.Fatalf("GetRepositories() expecting an error, but received none\n")
 	}
 }
+
+func TestMoveRepository(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/rest/api/1.0/projects/OLD/repos/widge" {
+			t.Fatalf("Want PUT /rest/api/1.0/projects/OLD/repos/widge but found %s %s\n", r.Method, r.URL.Path)
+		}
+
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if fmt.Sprint(payload) != "map[name:gadget project:map[key:NEW]]" {
+			t.Fatalf("Unexpected payload %v\n", payload)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"slug": "gadget", "name": "gadget", "project": {"key": "NEW"}}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	repository, err := stashClient.MoveRepository("OLD", "widge", MoveRepositoryOptions{
+		ProjectKey: "NEW",
+		Name:       "gadget",
+	})
+	if err != nil {
+		t.Fatalf("MoveRepository() not expecting an error, but received: %v\n", err)
+	}
+	if repository.Slug != "gadget" || repository.Project.Key != "NEW" {
+		t.Fatalf("Unexpected repository %+v\n", repository)
+	}
+}
+
+func TestGetForkAncestors(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/~BOB/repos/widge":
+			fmt.Fprint(w, `{"slug": "widge", "project": {"key": "~BOB"}, "origin": {"slug": "widge", "project": {"key": "TEAM"}}}`)
+		case "/rest/api/1.0/projects/TEAM/repos/widge":
+			fmt.Fprint(w, `{"slug": "widge", "project": {"key": "TEAM"}, "origin": {"slug": "widge", "project": {"key": "UPSTREAM"}}}`)
+		case "/rest/api/1.0/projects/UPSTREAM/repos/widge":
+			fmt.Fprint(w, `{"slug": "widge", "project": {"key": "UPSTREAM"}}`)
+		default:
+			t.Fatalf("Unexpected request %s\n", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	ancestors, err := stashClient.GetForkAncestors("~BOB", "widge")
+	if err != nil {
+		t.Fatalf("GetForkAncestors() not expecting an error, but received: %v\n", err)
+	}
+	if len(ancestors) != 2 || ancestors[0].Project.Key != "TEAM" || ancestors[1].Project.Key != "UPSTREAM" {
+		t.Fatalf("Unexpected ancestors %+v\n", ancestors)
+	}
+	if !ancestors[0].IsFork() || ancestors[1].IsFork() {
+		t.Fatalf("Want only TEAM/widge to be a fork\n")
+	}
+}
@@ -2,42 +2,161 @@ package stash
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 )
 
 func TestRepositoryNotExists(t *testing.T) {
 	if IsRepositoryExists(nil) {
-		t.Fatalf("nil an errorResponse type")
+		t.Fatalf("nil a ResponseError type")
 	}
 
 	if IsRepositoryExists(errors.New("foo")) {
-		t.Fatalf("Not an errorResponse type")
+		t.Fatalf("Not a ResponseError type")
 	}
 
-	if !IsRepositoryExists(errorResponse{StatusCode: http.StatusConflict}) {
-		t.Fatalf("Want errorResponse.409")
+	if !IsRepositoryExists(ResponseError{StatusCode: http.StatusConflict}) {
+		t.Fatalf("Want ResponseError.409")
 	}
 
-	if IsRepositoryExists(errorResponse{StatusCode: http.StatusNotFound}) {
-		t.Fatalf("Want errorResponse.409")
+	if IsRepositoryExists(ResponseError{StatusCode: http.StatusNotFound}) {
+		t.Fatalf("Want ResponseError.409")
 	}
 }
 
 func TestRepositoryNotFound(t *testing.T) {
 	if IsRepositoryNotFound(nil) {
-		t.Fatalf("nil not an errorResponse type")
+		t.Fatalf("nil not a ResponseError type")
 	}
 
 	if IsRepositoryExists(errors.New("foo")) {
-		t.Fatalf("Not an errorResponse type")
+		t.Fatalf("Not a ResponseError type")
 	}
 
-	if !IsRepositoryNotFound(errorResponse{StatusCode: http.StatusNotFound}) {
-		t.Fatalf("Want errorResponse.404")
+	if !IsRepositoryNotFound(ResponseError{StatusCode: http.StatusNotFound}) {
+		t.Fatalf("Want ResponseError.404")
 	}
 
-	if IsRepositoryNotFound(errorResponse{StatusCode: http.StatusConflict}) {
-		t.Fatalf("Want errorResponse.404")
+	if IsRepositoryNotFound(ResponseError{StatusCode: http.StatusConflict}) {
+		t.Fatalf("Want ResponseError.404")
+	}
+}
+
+func TestStatusPredicates(t *testing.T) {
+	predicates := map[int]func(error) bool{
+		http.StatusNotFound:        IsNotFound,
+		http.StatusUnauthorized:    IsUnauthorized,
+		http.StatusForbidden:       IsForbidden,
+		http.StatusConflict:        IsConflict,
+		http.StatusTooManyRequests: IsRateLimited,
+	}
+
+	for status, predicate := range predicates {
+		if predicate(nil) {
+			t.Fatalf("nil is not a ResponseError.%d", status)
+		}
+
+		if predicate(errors.New("foo")) {
+			t.Fatalf("Not a ResponseError.%d", status)
+		}
+
+		if !predicate(ResponseError{StatusCode: status}) {
+			t.Fatalf("Want ResponseError.%d", status)
+		}
+
+		if predicate(ResponseError{StatusCode: http.StatusInternalServerError}) {
+			t.Fatalf("Want ResponseError.%d", status)
+		}
+	}
+}
+
+func TestStatusPredicateWithErrorBody(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors": [{"message": "Repository PRJ/widge does not exist."}]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	_, err := stashClient.GetRepository("PRJ", "widge")
+	if !IsNotFound(err) {
+		t.Fatalf("Want ResponseError.404 but got %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "does not exist") {
+		t.Fatalf("Want server message in error but got %v", err)
+	}
+}
+
+func TestErrorResponseKeepsBody(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errors": [{"context": "name", "message": "Name is invalid.", "exceptionName": "com.atlassian.bitbucket.ValidationException"}]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	_, err := stashClient.CreateRepository("PRJ", "widge")
+	if err == nil {
+		t.Fatalf("CreateRepository() expecting an error but received none\n")
+	}
+
+	if !strings.Contains(string(ResponseBody(err)), "Name is invalid.") {
+		t.Fatalf("Want response body kept in error but got %q\n", ResponseBody(err))
+	}
+
+	messages := ResponseErrors(err)
+	if len(messages) != 1 || messages[0].Context != "name" ||
+		messages[0].ExceptionName != "com.atlassian.bitbucket.ValidationException" {
+		t.Fatalf("Want parsed error message but got %+v\n", messages)
+	}
+}
+
+func TestUnexpectedStatusShowsBody(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, `moved to maintenance`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	_, err := stashClient.CreateRepository("PRJ", "widge")
+	if err == nil || !strings.Contains(err.Error(), "moved to maintenance") {
+		t.Fatalf("Want response body in error but got %v\n", err)
+	}
+}
+
+func TestSentinelErrors(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"errors": [{"message": "Branch feature already exists."}]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	_, err := stashClient.CreateBranch("PRJ", "widge", "feature", "master")
+
+	wrapped := fmt.Errorf("create branch: %w", err)
+	if !errors.Is(wrapped, ErrConflict) || errors.Is(wrapped, ErrNotFound) {
+		t.Fatalf("Want error to match only ErrConflict but got %v", err)
+	}
+
+	var response ResponseError
+	if !errors.As(wrapped, &response) {
+		t.Fatalf("Want ResponseError but got %T", err)
+	}
+
+	if response.StatusCode != http.StatusConflict ||
+		len(response.Errors) != 1 ||
+		response.Errors[0].Message != "Branch feature already exists." {
+		t.Fatalf("Want status and parsed errors but got %+v", response)
 	}
 }
@@ -32,8 +32,10 @@ func TestStatusGreaterThan400AndTwoErrors(t *testing.T) {
 	defer testServer.Close()
 
 	request, _ := http.NewRequest("GET", testServer.URL, nil)
-	actualStatus, actualBody, actualError := consumeResponse(request)
-	if fmt.Sprint(actualError) != "The name should be between 1 and 255 characters. The email should be a valid email address." {
+	actualStatus, actualBody, actualError := Client{}.consumeResponse(request)
+	wantError := "The name should be between 1 and 255 characters. The email should be a valid email address." +
+		"\n└─ url: " + testServer.URL
+	if fmt.Sprint(actualError) != wantError {
 		t.Fatalf("Want error with two joined messages, but got '%v'", actualError)
 	}
 	if actualStatus != 400 {
@@ -55,7 +57,7 @@ func TestStatusLowerThan400AndInvalidJSON(t *testing.T) {
 	defer testServer.Close()
 
 	request, _ := http.NewRequest("GET", testServer.URL, nil)
-	actualStatus, actualBody, actualError := consumeResponse(request)
+	actualStatus, actualBody, actualError := Client{}.consumeResponse(request)
 	if actualError != nil {
 		t.Fatalf("Want error = nil, but got %v", actualError)
 	}
@@ -0,0 +1,205 @@
+package stash
+
+import (
+	"net/http"
+
+	"github.com/reconquest/karma-go"
+)
+
+type (
+	// RefRestriction is a branch permission of branch-permissions 2.0 API.
+	// Unlike BranchRestriction it may match refs by pattern or branching
+	// model and carries users and groups which are exempt from it.
+	RefRestriction struct {
+		ID      int                `json:"id"`
+		Type    RefRestrictionType `json:"type"`
+		Matcher RefMatcher         `json:"matcher"`
+		Users   []User             `json:"users"`
+		Groups  []string           `json:"groups"`
+	}
+
+	RefMatcher struct {
+		ID        string         `json:"id"`
+		DisplayID string         `json:"displayId,omitempty"`
+		Type      RefMatcherType `json:"type"`
+	}
+
+	RefMatcherType struct {
+		ID   RefMatcherKind `json:"id"`
+		Name string         `json:"name,omitempty"`
+	}
+
+	// RefRestrictionOptions describes restriction to create, Users are
+	// user slugs.
+	RefRestrictionOptions struct {
+		Type    RefRestrictionType `json:"type"`
+		Matcher RefMatcher         `json:"matcher"`
+		Users   []string           `json:"users"`
+		Groups  []string           `json:"groups"`
+	}
+
+	RefRestrictions = Paged[RefRestriction]
+)
+
+// GetRefRestrictions returns all ref restrictions of the given repository.
+func (client Client) GetRefRestrictions(
+	projectKey, repositorySlug string,
+) ([]RefRestriction, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[RefRestriction], error) {
+			var response RefRestrictions
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/branch-permissions/2.0/projects/%s/repos/%s/restrictions?start=%d&limit=%d",
+					projectKey, repositorySlug, start, limit,
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+func (client Client) CreateRefRestriction(
+	projectKey, repositorySlug string,
+	options RefRestrictionOptions,
+) (RefRestriction, error) {
+	if options.Users == nil {
+		options.Users = []string{}
+	}
+
+	if options.Groups == nil {
+		options.Groups = []string{}
+	}
+
+	var response RefRestriction
+	err := client.requestJSON(
+		"POST",
+		pathf(
+			"/rest/branch-permissions/2.0/projects/%s/repos/%s/restrictions",
+			projectKey, repositorySlug,
+		),
+		options,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return RefRestriction{}, err
+	}
+
+	return response, nil
+}
+
+func (client Client) DeleteRefRestriction(
+	projectKey, repositorySlug string,
+	id int,
+) error {
+	_, err := client.request(
+		"DELETE",
+		pathf(
+			"/rest/branch-permissions/2.0/projects/%s/repos/%s/restrictions/%d",
+			projectKey, repositorySlug, id,
+		),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+// Options returns options which create the same restriction.
+func (restriction RefRestriction) Options() RefRestrictionOptions {
+	users := make([]string, len(restriction.Users))
+	for i, user := range restriction.Users {
+		users[i] = user.Slug
+	}
+
+	groups := append([]string{}, restriction.Groups...)
+
+	return RefRestrictionOptions{
+		Type: restriction.Type,
+		Matcher: RefMatcher{
+			ID:   restriction.Matcher.ID,
+			Type: RefMatcherType{ID: restriction.Matcher.Type.ID},
+		},
+		Users:  users,
+		Groups: groups,
+	}
+}
+
+type (
+	// RepositoryRef identifies repository by project key and slug.
+	RepositoryRef struct {
+		ProjectKey string
+		Slug       string
+	}
+
+	// RestrictionsCopy is a result of copying restrictions to a single
+	// target repository.
+	RestrictionsCopy struct {
+		Target  RepositoryRef
+		Changes []RepositoryChange
+		Err     error
+	}
+
+	CopyRefRestrictionsOptions struct {
+		// Prune deletes target restrictions which source doesn't have.
+		Prune bool
+
+		// Concurrency limits amount of targets updated at the same time,
+		// default is 1.
+		Concurrency int
+	}
+)
+
+// CopyRefRestrictions recreates ref restrictions of the source repository
+// on every target repository. Restrictions already present on target with
+// the same users and groups are left untouched, so copying can be repeated
+// safely. Results are returned in order of targets.
+func (client Client) CopyRefRestrictions(
+	source RepositoryRef,
+	targets []RepositoryRef,
+	options CopyRefRestrictionsOptions,
+) ([]RestrictionsCopy, error) {
+	restrictions, err := client.GetRefRestrictions(source.ProjectKey, source.Slug)
+	if err != nil {
+		return nil, karma.
+			Describe("source", source.ProjectKey+"/"+source.Slug).
+			Reason(err)
+	}
+
+	desired := make([]RefRestrictionOptions, len(restrictions))
+	for i, restriction := range restrictions {
+		desired[i] = restriction.Options()
+	}
+
+	results := make([]RestrictionsCopy, len(targets))
+	forEachConcurrently(len(targets), options.Concurrency, func(index int) {
+		target := targets[index]
+		results[index] = RestrictionsCopy{Target: target}
+
+		current, err := client.GetRefRestrictions(target.ProjectKey, target.Slug)
+		if err != nil {
+			results[index].Err = err
+			return
+		}
+
+		plan := RepositoryPlan{
+			ProjectKey: target.ProjectKey,
+			Slug:       target.Slug,
+			Changes: planRefRestrictions(
+				client, target.ProjectKey, target.Slug,
+				current, desired, options.Prune,
+			),
+		}
+
+		results[index].Changes = plan.Changes
+		results[index].Err = plan.Apply()
+	})
+
+	return results, nil
+}
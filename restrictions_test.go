@@ -0,0 +1,88 @@
+package stash
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func TestCopyRefRestrictions(t *testing.T) {
+	const restrictions = "/rest/branch-permissions/2.0/projects/%s/repos/%s/restrictions"
+
+	var (
+		mutex   sync.Mutex
+		created = map[string]string{}
+		deleted []string
+	)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == fmt.Sprintf(restrictions, "PRJ", "template"):
+			fmt.Fprint(w, `{"isLastPage": true, "values": [
+				{"id": 1, "type": "no-deletes", "matcher": {"id": "refs/heads/master", "displayId": "master", "type": {"id": "BRANCH", "name": "Branch"}}, "users": [], "groups": []},
+				{"id": 2, "type": "pull-request-only", "matcher": {"id": "release/*", "type": {"id": "PATTERN"}}, "users": [{"name": "bot", "slug": "bot"}], "groups": ["release"]}
+			]}`)
+		case r.Method == "GET" && r.URL.Path == fmt.Sprintf(restrictions, "PRJ", "new"):
+			fmt.Fprint(w, `{"isLastPage": true, "values": []}`)
+		case r.Method == "GET" && r.URL.Path == fmt.Sprintf(restrictions, "PRJ", "done"):
+			fmt.Fprint(w, `{"isLastPage": true, "values": [
+				{"id": 5, "type": "no-deletes", "matcher": {"id": "refs/heads/master", "type": {"id": "BRANCH"}}, "users": [], "groups": []},
+				{"id": 6, "type": "pull-request-only", "matcher": {"id": "release/*", "type": {"id": "PATTERN"}}, "users": [{"name": "bot", "slug": "bot"}], "groups": ["release"]},
+				{"id": 7, "type": "read-only", "matcher": {"id": "refs/heads/legacy", "type": {"id": "BRANCH"}}, "users": [], "groups": []}
+			]}`)
+		case r.Method == "POST":
+			body, _ := ioutil.ReadAll(r.Body)
+			mutex.Lock()
+			created[r.URL.Path] += string(body) + "\n"
+			mutex.Unlock()
+			fmt.Fprint(w, `{}`)
+		case r.Method == "DELETE":
+			mutex.Lock()
+			deleted = append(deleted, r.URL.Path)
+			mutex.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	results, err := stashClient.CopyRefRestrictions(
+		RepositoryRef{ProjectKey: "PRJ", Slug: "template"},
+		[]RepositoryRef{
+			{ProjectKey: "PRJ", Slug: "new"},
+			{ProjectKey: "PRJ", Slug: "done"},
+		},
+		CopyRefRestrictionsOptions{Concurrency: 2},
+	)
+	if err != nil {
+		t.Fatalf("CopyRefRestrictions() not expecting an error, but received: %v\n", err)
+	}
+
+	for _, result := range results {
+		if result.Err != nil {
+			t.Fatalf("Not expecting error for %+v: %v\n", result.Target, result.Err)
+		}
+	}
+
+	if len(results[0].Changes) != 2 || len(results[1].Changes) != 0 {
+		t.Fatalf("Want 2 changes for new and none for done but got %d and %d\n", len(results[0].Changes), len(results[1].Changes))
+	}
+
+	want := `{"type":"no-deletes","matcher":{"id":"refs/heads/master","type":{"id":"BRANCH"}},"users":[],"groups":[]}
+{"type":"pull-request-only","matcher":{"id":"release/*","type":{"id":"PATTERN"}},"users":["bot"],"groups":["release"]}
+`
+	if created[fmt.Sprintf(restrictions, "PRJ", "new")] != want {
+		t.Fatalf("Unexpected restrictions created:\n%s\n", created[fmt.Sprintf(restrictions, "PRJ", "new")])
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("Want nothing deleted without Prune but got %v\n", deleted)
+	}
+}
@@ -0,0 +1,92 @@
+package stash
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrPullRequestOutOfDate can be returned by function given to
+// RetryPullRequest to retry it with fresh pull request, e.g. when
+// MergeResult.OutOfDate reports stale version.
+var ErrPullRequestOutOfDate = errors.New("pull request version is out of date")
+
+// outOfDateException is reported when pull request was changed since the
+// version given in request.
+const outOfDateException = "PullRequestOutOfDateException"
+
+// RetryPullRequest gets the pull request and calls fn with it, repeating
+// both if fn fails because pull request was modified concurrently, which
+// is detected by IsPullRequestOutOfDate. At most attempts calls are made,
+// default is 3. The last error of fn is returned.
+//
+//	err := client.RetryPullRequest("PRJ", "repo", "1", 0,
+//		func(pr stash.PullRequest) error {
+//			_, err := client.DeclinePullRequest("PRJ", "repo", "1", pr.Version)
+//			return err
+//		},
+//	)
+func (client Client) RetryPullRequest(
+	projectKey, repositorySlug, identifier string,
+	attempts int,
+	fn func(PullRequest) error,
+) error {
+	if attempts < 1 {
+		attempts = 3
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		var pullRequest PullRequest
+		pullRequest, err = client.GetPullRequest(
+			projectKey, repositorySlug, identifier,
+		)
+		if err != nil {
+			return err
+		}
+
+		err = fn(pullRequest)
+		if !IsPullRequestOutOfDate(err) {
+			return err
+		}
+	}
+
+	return err
+}
+
+// IsPullRequestOutOfDate reports whether err is caused by server rejecting
+// stale pull request version.
+func IsPullRequestOutOfDate(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, ErrPullRequestOutOfDate) {
+		return true
+	}
+
+	var response ResponseError
+	if !errors.As(err, &response) || response.StatusCode != http.StatusConflict {
+		return false
+	}
+
+	for _, message := range response.Errors {
+		if strings.HasSuffix(message.ExceptionName, outOfDateException) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OutOfDate reports whether merge was rejected because pull request was
+// modified since the given version.
+func (result MergeResult) OutOfDate() bool {
+	for _, message := range result.Errors {
+		if strings.HasSuffix(message.ExceptionName, outOfDateException) {
+			return true
+		}
+	}
+
+	return false
+}
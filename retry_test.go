@@ -0,0 +1,69 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRetryPullRequest(t *testing.T) {
+	var gets, declines int
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/1.0/projects/PRJ/repos/widge/pull-requests/1":
+			gets++
+			fmt.Fprintf(w, `{"id": 1, "version": %d}`, gets)
+		case "POST /rest/api/1.0/projects/PRJ/repos/widge/pull-requests/1/decline":
+			declines++
+			if r.URL.Query().Get("version") != "2" {
+				w.WriteHeader(http.StatusConflict)
+				fmt.Fprint(w, `{"errors": [{"message": "You are attempting to modify a pull request based on out-of-date information.", "exceptionName": "com.atlassian.bitbucket.pull.PullRequestOutOfDateException"}]}`)
+				return
+			}
+			fmt.Fprint(w, `{"id": 1, "version": 3, "state": "DECLINED"}`)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	err := stashClient.RetryPullRequest("PRJ", "widge", "1", 0, func(pr PullRequest) error {
+		_, err := stashClient.DeclinePullRequest("PRJ", "widge", "1", pr.Version)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("RetryPullRequest() not expecting an error, but received: %v\n", err)
+	}
+	if gets != 2 || declines != 2 {
+		t.Fatalf("Want 2 attempts but got %d gets and %d declines\n", gets, declines)
+	}
+}
+
+func TestRetryPullRequestGivesUp(t *testing.T) {
+	var attempts int
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "version": 1}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	err := stashClient.RetryPullRequest("PRJ", "widge", "1", 2, func(pr PullRequest) error {
+		attempts++
+		return ErrPullRequestOutOfDate
+	})
+	if !IsPullRequestOutOfDate(err) {
+		t.Fatalf("Want out of date error but got %v\n", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("Want 2 attempts but got %d\n", attempts)
+	}
+}
@@ -0,0 +1,154 @@
+package stash
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+type (
+	// Scanner runs function against many repositories concurrently:
+	//
+	//	err := stash.Scanner{Stash: client, Concurrency: 8}.Scan(
+	//		func(repo stash.Repository) error { ... },
+	//	)
+	Scanner struct {
+		Stash Stash
+
+		// ProjectKey limits scan to a single project, all repositories
+		// visible to the user are scanned when it's empty.
+		ProjectKey string
+
+		// Filter skips repositories for which it returns false.
+		Filter func(Repository) bool
+
+		// Concurrency limits amount of repositories scanned at the same
+		// time, default is 1.
+		Concurrency int
+
+		// Progress is called after each repository is scanned. Calls are
+		// never made concurrently.
+		Progress func(ScanProgress)
+	}
+
+	ScanProgress struct {
+		Repository Repository
+		Err        error
+		Done       int
+		Total      int
+	}
+
+	// ScanError is a failure of scan function for a single repository.
+	ScanError struct {
+		Repository Repository
+		Err        error
+	}
+
+	// ScanErrors aggregates all failures of the scan, in order repositories
+	// were listed.
+	ScanErrors []ScanError
+)
+
+// Scan lists repositories and calls fn for each of them which passes the
+// filter. All repositories are scanned even if some fail, failures are
+// returned as ScanErrors.
+func (scanner Scanner) Scan(fn func(Repository) error) error {
+	var (
+		repositories []Repository
+		err          error
+	)
+
+	if scanner.ProjectKey != "" {
+		repositories, err = scanner.Stash.ListProjectRepositories(scanner.ProjectKey)
+	} else {
+		repositories, err = scanner.Stash.ListRepositories()
+	}
+	if err != nil {
+		return err
+	}
+
+	if scanner.Filter != nil {
+		filtered := repositories[:0]
+		for _, repository := range repositories {
+			if scanner.Filter(repository) {
+				filtered = append(filtered, repository)
+			}
+		}
+
+		repositories = filtered
+	}
+
+	var (
+		mutex  sync.Mutex
+		done   int
+		failed = make([]error, len(repositories))
+	)
+
+	forEachConcurrently(len(repositories), scanner.Concurrency, func(index int) {
+		err := fn(repositories[index])
+		failed[index] = err
+
+		if scanner.Progress == nil {
+			return
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		done++
+		scanner.Progress(ScanProgress{
+			Repository: repositories[index],
+			Err:        err,
+			Done:       done,
+			Total:      len(repositories),
+		})
+	})
+
+	var errs ScanErrors
+	for index, err := range failed {
+		if err != nil {
+			errs = append(errs, ScanError{
+				Repository: repositories[index],
+				Err:        err,
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (err ScanError) Error() string {
+	return fmt.Sprintf(
+		"%s/%s: %s", err.Repository.Project.Key, err.Repository.Slug, err.Err,
+	)
+}
+
+func (err ScanError) Unwrap() error {
+	return err.Err
+}
+
+func (errs ScanErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf(
+		"scan failed for %d repositories: %s",
+		len(errs), strings.Join(messages, "; "),
+	)
+}
+
+// Unwrap makes errors.Is and errors.As match any of failures.
+func (errs ScanErrors) Unwrap() []error {
+	unwrapped := make([]error, len(errs))
+	for i, err := range errs {
+		unwrapped[i] = err
+	}
+
+	return unwrapped
+}
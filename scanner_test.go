@@ -0,0 +1,72 @@
+package stash
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PRJ/repos" {
+			t.Fatalf("Want project repositories path but found %s\n", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"isLastPage": true, "values": [
+			{"slug": "one", "project": {"key": "PRJ"}},
+			{"slug": "two", "project": {"key": "PRJ"}},
+			{"slug": "archive", "project": {"key": "PRJ"}},
+			{"slug": "three", "project": {"key": "PRJ"}}
+		]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	errBroken := errors.New("broken")
+
+	var (
+		scanned  int32
+		progress []int
+	)
+	err := Scanner{
+		Stash:       stashClient,
+		ProjectKey:  "PRJ",
+		Concurrency: 3,
+		Filter: func(repository Repository) bool {
+			return repository.Slug != "archive"
+		},
+		Progress: func(status ScanProgress) {
+			if status.Total != 3 {
+				t.Fatalf("Want total of 3 but got %d\n", status.Total)
+			}
+			progress = append(progress, status.Done)
+		},
+	}.Scan(func(repository Repository) error {
+		atomic.AddInt32(&scanned, 1)
+		if repository.Slug == "two" {
+			return errBroken
+		}
+		return nil
+	})
+
+	if scanned != 3 || len(progress) != 3 || progress[2] != 3 {
+		t.Fatalf("Want 3 repositories scanned but got %d with progress %v\n", scanned, progress)
+	}
+
+	var errs ScanErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].Repository.Slug != "two" {
+		t.Fatalf("Want single failure of PRJ/two but got %v\n", err)
+	}
+	if !errors.Is(err, errBroken) {
+		t.Fatalf("Want error to match errBroken but got %v\n", err)
+	}
+	if !strings.Contains(err.Error(), "PRJ/two: broken") {
+		t.Fatalf("Want failed repository in message but got %v\n", err)
+	}
+}
@@ -0,0 +1,326 @@
+package stash
+
+import (
+	"io"
+	"strconv"
+)
+
+type (
+	// ProjectScope exposes methods of Stash bound to a single project, so
+	// project key is not repeated on every call:
+	//
+	//	pr, err := client.Project("KEY").Repo("slug").PullRequest(42).Get()
+	ProjectScope struct {
+		stash Stash
+		Key   string
+	}
+
+	// RepositoryScope exposes methods of Stash bound to a single repository.
+	RepositoryScope struct {
+		stash      Stash
+		ProjectKey string
+		Slug       string
+	}
+
+	// PullRequestScope exposes methods of Stash bound to a single pull
+	// request.
+	PullRequestScope struct {
+		stash      Stash
+		ProjectKey string
+		Slug       string
+		ID         int
+	}
+)
+
+// Project returns methods scoped to the given project.
+func (client Client) Project(projectKey string) ProjectScope {
+	return ProjectScope{stash: client, Key: projectKey}
+}
+
+func (project ProjectScope) Repo(slug string) RepositoryScope {
+	return RepositoryScope{
+		stash:      project.stash,
+		ProjectKey: project.Key,
+		Slug:       slug,
+	}
+}
+
+func (project ProjectScope) Get() (Project, error) {
+	return project.stash.GetProject(project.Key)
+}
+
+func (project ProjectScope) Update(update ProjectUpdate) (Project, error) {
+	return project.stash.UpdateProject(project.Key, update)
+}
+
+func (project ProjectScope) Delete() error {
+	return project.stash.DeleteProject(project.Key)
+}
+
+func (project ProjectScope) CreateRepository(slug string) (Repository, error) {
+	return project.stash.CreateRepository(project.Key, slug)
+}
+
+func (project ProjectScope) Repositories() ([]Repository, error) {
+	return project.stash.ListProjectRepositories(project.Key)
+}
+
+func (project ProjectScope) GrantUserPermission(
+	user string, permission Permission,
+) error {
+	return project.stash.GrantProjectUserPermission(project.Key, user, permission)
+}
+
+func (project ProjectScope) RevokeUserPermission(user string) error {
+	return project.stash.RevokeProjectUserPermission(project.Key, user)
+}
+
+func (project ProjectScope) GrantGroupPermission(
+	group string, permission Permission,
+) error {
+	return project.stash.GrantProjectGroupPermission(project.Key, group, permission)
+}
+
+func (project ProjectScope) RevokeGroupPermission(group string) error {
+	return project.stash.RevokeProjectGroupPermission(project.Key, group)
+}
+
+func (repo RepositoryScope) Get() (Repository, error) {
+	return repo.stash.GetRepository(repo.ProjectKey, repo.Slug)
+}
+
+func (repo RepositoryScope) Rename(newSlug string) error {
+	return repo.stash.RenameRepository(repo.ProjectKey, repo.Slug, newSlug)
+}
+
+func (repo RepositoryScope) Move(options MoveRepositoryOptions) (Repository, error) {
+	return repo.stash.MoveRepository(repo.ProjectKey, repo.Slug, options)
+}
+
+func (repo RepositoryScope) Remove() error {
+	return repo.stash.RemoveRepository(repo.ProjectKey, repo.Slug)
+}
+
+func (repo RepositoryScope) Fork(forkSlug string) (*Repository, error) {
+	return repo.stash.ForkRepository(repo.ProjectKey, repo.Slug, forkSlug)
+}
+
+func (repo RepositoryScope) Branches() ([]Branch, error) {
+	return repo.stash.ListBranches(repo.ProjectKey, repo.Slug)
+}
+
+func (repo RepositoryScope) Tags() ([]Tag, error) {
+	return repo.stash.ListTags(repo.ProjectKey, repo.Slug)
+}
+
+// CreateTag creates annotated tag, or lightweight one if message is empty.
+func (repo RepositoryScope) CreateTag(
+	name, startPoint, message string,
+) (Tag, error) {
+	return repo.stash.CreateTag(
+		repo.ProjectKey, repo.Slug, name, startPoint, message,
+	)
+}
+
+func (repo RepositoryScope) DeleteTag(name string) error {
+	return repo.stash.DeleteTag(repo.ProjectKey, repo.Slug, name)
+}
+
+func (repo RepositoryScope) CreateBranch(
+	branchName, startPoint string,
+) (Branch, error) {
+	return repo.stash.CreateBranch(
+		repo.ProjectKey, repo.Slug, branchName, startPoint,
+	)
+}
+
+func (repo RepositoryScope) DeleteBranch(branchName string) error {
+	return repo.stash.DeleteBranch(repo.ProjectKey, repo.Slug, branchName)
+}
+
+func (repo RepositoryScope) DeleteBranchWithOptions(
+	branchName string,
+	options DeleteBranchOptions,
+) error {
+	return repo.stash.DeleteBranchWithOptions(
+		repo.ProjectKey, repo.Slug, branchName, options,
+	)
+}
+
+func (repo RepositoryScope) CreateBranchRestriction(
+	branch, user string,
+) (BranchRestriction, error) {
+	return repo.stash.CreateBranchRestriction(
+		repo.ProjectKey, repo.Slug, branch, user,
+	)
+}
+
+func (repo RepositoryScope) BranchRestrictions() (BranchRestrictions, error) {
+	return repo.stash.GetBranchRestrictions(repo.ProjectKey, repo.Slug)
+}
+
+func (repo RepositoryScope) DeleteBranchRestriction(id int) error {
+	return repo.stash.DeleteBranchRestriction(repo.ProjectKey, repo.Slug, id)
+}
+
+func (repo RepositoryScope) Commit(commitHash string) (Commit, error) {
+	return repo.stash.GetCommit(repo.ProjectKey, repo.Slug, commitHash)
+}
+
+func (repo RepositoryScope) CommentCommit(
+	commitHash, text string,
+	anchor *CommentAnchor,
+) (Comment, error) {
+	return repo.stash.CreateCommitComment(
+		repo.ProjectKey, repo.Slug, commitHash, text, anchor,
+	)
+}
+
+func (repo RepositoryScope) CommitComments(
+	commitHash, path string,
+) ([]Comment, error) {
+	return repo.stash.GetCommitComments(
+		repo.ProjectKey, repo.Slug, commitHash, path,
+	)
+}
+
+func (repo RepositoryScope) Commits(
+	commitSinceHash, commitUntilHash string,
+) (Commits, error) {
+	return repo.stash.GetCommits(
+		repo.ProjectKey, repo.Slug, commitSinceHash, commitUntilHash,
+	)
+}
+
+func (repo RepositoryScope) PullRequests(
+	state PullRequestState,
+) ([]PullRequest, error) {
+	return repo.stash.GetPullRequests(repo.ProjectKey, repo.Slug, state)
+}
+
+func (repo RepositoryScope) GrantUserPermission(
+	user string, permission Permission,
+) error {
+	return repo.stash.GrantRepositoryUserPermission(
+		repo.ProjectKey, repo.Slug, user, permission,
+	)
+}
+
+func (repo RepositoryScope) RevokeUserPermission(user string) error {
+	return repo.stash.RevokeRepositoryUserPermission(
+		repo.ProjectKey, repo.Slug, user,
+	)
+}
+
+func (repo RepositoryScope) Watch() error {
+	return repo.stash.WatchRepository(repo.ProjectKey, repo.Slug)
+}
+
+func (repo RepositoryScope) Unwatch() error {
+	return repo.stash.UnwatchRepository(repo.ProjectKey, repo.Slug)
+}
+
+func (repo RepositoryScope) Files(path, at string) ([]string, error) {
+	return repo.stash.GetFiles(repo.ProjectKey, repo.Slug, path, at)
+}
+
+func (repo RepositoryScope) UploadAttachment(
+	name string,
+	reader io.Reader,
+) (Attachment, error) {
+	return repo.stash.UploadAttachment(repo.ProjectKey, repo.Slug, name, reader)
+}
+
+func (repo RepositoryScope) PullRequest(id int) PullRequestScope {
+	return PullRequestScope{
+		stash:      repo.stash,
+		ProjectKey: repo.ProjectKey,
+		Slug:       repo.Slug,
+		ID:         id,
+	}
+}
+
+func (pr PullRequestScope) Get() (PullRequest, error) {
+	return pr.stash.GetPullRequest(pr.ProjectKey, pr.Slug, pr.identifier())
+}
+
+func (pr PullRequestScope) Update(
+	version int,
+	title, description, toRef string,
+	reviewers []string,
+) (PullRequest, error) {
+	return pr.stash.UpdatePullRequest(
+		pr.ProjectKey, pr.Slug, pr.identifier(),
+		version,
+		title, description, toRef,
+		reviewers,
+	)
+}
+
+func (pr PullRequestScope) Merge(version int) (*MergeResult, error) {
+	return pr.stash.MergePullRequest(
+		pr.ProjectKey, pr.Slug, pr.identifier(), version,
+	)
+}
+
+func (pr PullRequestScope) Activities() ([]PullRequestActivity, error) {
+	return pr.stash.GetPullRequestActivities(
+		pr.ProjectKey, pr.Slug, pr.identifier(),
+	)
+}
+
+func (pr PullRequestScope) Tasks() ([]PullRequestTask, error) {
+	return pr.stash.GetPullRequestTasks(
+		pr.ProjectKey, pr.Slug, pr.identifier(),
+	)
+}
+
+func (pr PullRequestScope) Mergeability() (Mergeability, error) {
+	return pr.stash.GetPullRequestMergeability(
+		pr.ProjectKey, pr.Slug, pr.identifier(),
+	)
+}
+
+func (pr PullRequestScope) Decline(version int) (PullRequest, error) {
+	return pr.stash.DeclinePullRequest(
+		pr.ProjectKey, pr.Slug, pr.identifier(), version,
+	)
+}
+
+func (pr PullRequestScope) Reopen(version int) (PullRequest, error) {
+	return pr.stash.ReopenPullRequest(
+		pr.ProjectKey, pr.Slug, pr.identifier(), version,
+	)
+}
+
+// SetStatus sets review status of the user client is authenticated as.
+func (pr PullRequestScope) SetStatus(status ParticipantStatus) (Reviewer, error) {
+	return pr.stash.SetParticipantStatus(
+		pr.ProjectKey, pr.Slug, pr.identifier(), "", status,
+	)
+}
+
+func (pr PullRequestScope) Comment(text string) (Comment, error) {
+	return pr.stash.CreateComment(
+		pr.ProjectKey, pr.Slug, pr.identifier(), text,
+	)
+}
+
+func (pr PullRequestScope) InlineComment(
+	text string,
+	anchor CommentAnchor,
+) (Comment, error) {
+	return pr.stash.CreateInlineComment(
+		pr.ProjectKey, pr.Slug, pr.identifier(), text, anchor,
+	)
+}
+
+func (pr PullRequestScope) Reply(parentID int, text string) (Comment, error) {
+	return pr.stash.ReplyComment(
+		pr.ProjectKey, pr.Slug, pr.identifier(), parentID, text,
+	)
+}
+
+func (pr PullRequestScope) identifier() string {
+	return strconv.Itoa(pr.ID)
+}
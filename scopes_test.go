@@ -0,0 +1,54 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestPullRequestScope(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		url := *r.URL
+		if url.Path != "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/42" {
+			t.Fatalf("Want /rest/api/1.0/projects/PRJ/repos/widge/pull-requests/42 but found %s\n", url.Path)
+		}
+		fmt.Fprint(w, `{"id": 42, "title": "a title"}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	pullRequest, err := stashClient.Project("PRJ").Repo("widge").PullRequest(42).Get()
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if pullRequest.ID != 42 {
+		t.Fatalf("Want 42 but got %d\n", pullRequest.ID)
+	}
+}
+
+func TestPullRequestScopeDecline(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		wantPath := "/rest/api/1.0/projects/PRJ/repos/widge/pull-requests/42/decline"
+		if r.Method != "POST" || r.URL.Path != wantPath {
+			t.Fatalf("Want POST %s but found %s %s\n", wantPath, r.Method, r.URL.Path)
+		}
+		if r.URL.Query().Get("version") != "3" {
+			t.Fatalf("Want version=3 but found %s\n", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"id": 42, "version": 4, "state": "DECLINED"}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	pullRequest, err := stashClient.Project("PRJ").Repo("widge").PullRequest(42).Decline(3)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if pullRequest.State != PullRequestStateDeclined || pullRequest.Version != 4 {
+		t.Fatalf("Want declined pull request of version 4 but got %+v\n", pullRequest)
+	}
+}
@@ -0,0 +1,270 @@
+package stash
+
+import (
+	"net/http"
+	"strings"
+)
+
+type (
+	// RepositoryUpdate changes repository settings, nil fields are left
+	// as is.
+	RepositoryUpdate struct {
+		Description *string `json:"description,omitempty"`
+		Public      *bool   `json:"public,omitempty"`
+		Forkable    *bool   `json:"forkable,omitempty"`
+	}
+
+	PullRequestSettings struct {
+		RequiredApprovers        int  `json:"requiredApprovers"`
+		RequiredAllApprovers     bool `json:"requiredAllApprovers"`
+		RequiredAllTasksComplete bool `json:"requiredAllTasksComplete"`
+		RequiredSuccessfulBuilds int  `json:"requiredSuccessfulBuilds"`
+	}
+
+	// MergeConfig lists merge strategies pull requests can be merged with
+	// and templates of merge commit messages.
+	MergeConfig struct {
+		DefaultStrategy MergeStrategyConfig   `json:"defaultStrategy"`
+		Strategies      []MergeStrategyConfig `json:"strategies"`
+
+		CommitMessageTemplate *CommitMessageTemplate `json:"commitMessageTemplate,omitempty"`
+
+		// CommitSummaries is amount of commit summaries included in merge
+		// commit message.
+		CommitSummaries int `json:"commitSummaries,omitempty"`
+
+		// Type tells on which level config is defined, it's set by the
+		// server.
+		Type string `json:"type,omitempty"`
+	}
+
+	MergeStrategyConfig struct {
+		ID      MergeStrategy `json:"id"`
+		Name    string        `json:"name,omitempty"`
+		Enabled bool          `json:"enabled"`
+	}
+
+	CommitMessageTemplate struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+	}
+)
+
+func (client Client) UpdateRepository(
+	projectKey, repositorySlug string,
+	update RepositoryUpdate,
+) (Repository, error) {
+	var response Repository
+	err := client.requestJSON(
+		"PUT",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s",
+			projectKey, repositorySlug,
+		),
+		update,
+		&response,
+		http.StatusOK,
+		http.StatusCreated,
+	)
+	if err != nil {
+		return Repository{}, err
+	}
+
+	return response, nil
+}
+
+func (client Client) GetDefaultBranch(
+	projectKey, repositorySlug string,
+) (Branch, error) {
+	var response Branch
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/branches/default",
+			projectKey, repositorySlug,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Branch{}, err
+	}
+
+	return response, nil
+}
+
+// SetDefaultBranch changes default branch of the repository, branch can be
+// given either as display name or as full ref.
+func (client Client) SetDefaultBranch(
+	projectKey, repositorySlug, branch string,
+) error {
+	_, err := client.request(
+		"PUT",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/branches/default",
+			projectKey, repositorySlug,
+		),
+		struct {
+			ID string `json:"id"`
+		}{
+			ID: branchRef(branch),
+		},
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+// GetInstanceDefaultBranch returns branch name newly created repositories
+// start with, requires Bitbucket 7.5 or newer.
+func (client Client) GetInstanceDefaultBranch() (Branch, error) {
+	err := client.requireVersion("instance default branch", 7, 5)
+	if err != nil {
+		return Branch{}, err
+	}
+
+	var response Branch
+	err = client.requestJSON(
+		"GET", "/rest/api/1.0/admin/default-branch",
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Branch{}, err
+	}
+
+	return response, nil
+}
+
+// SetInstanceDefaultBranch changes branch name newly created repositories
+// start with, e.g. "main". Empty branch resets it to the server default.
+// Requires Bitbucket 7.5 or newer.
+func (client Client) SetInstanceDefaultBranch(branch string) error {
+	err := client.requireVersion("instance default branch", 7, 5)
+	if err != nil {
+		return err
+	}
+
+	if branch == "" {
+		_, err := client.request(
+			"DELETE", "/rest/api/1.0/admin/default-branch",
+			nil,
+			http.StatusNoContent,
+		)
+
+		return err
+	}
+
+	_, err = client.request(
+		"PUT", "/rest/api/1.0/admin/default-branch",
+		struct {
+			ID string `json:"id"`
+		}{
+			ID: branchRef(branch),
+		},
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+func (client Client) GetPullRequestSettings(
+	projectKey, repositorySlug string,
+) (PullRequestSettings, error) {
+	var response PullRequestSettings
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/settings/pull-requests",
+			projectKey, repositorySlug,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return PullRequestSettings{}, err
+	}
+
+	return response, nil
+}
+
+func (client Client) UpdatePullRequestSettings(
+	projectKey, repositorySlug string,
+	settings PullRequestSettings,
+) (PullRequestSettings, error) {
+	var response PullRequestSettings
+	err := client.requestJSON(
+		"POST",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/settings/pull-requests",
+			projectKey, repositorySlug,
+		),
+		settings,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return PullRequestSettings{}, err
+	}
+
+	return response, nil
+}
+
+// GetGlobalMergeConfig returns instance-wide merge config for the
+// repositories of the given SCM, usually "git", which don't override it.
+func (client Client) GetGlobalMergeConfig(scmID string) (MergeConfig, error) {
+	var response struct {
+		MergeConfig MergeConfig `json:"mergeConfig"`
+	}
+	err := client.requestJSON(
+		"GET",
+		pathf("/rest/api/1.0/admin/pull-requests/%s", scmID),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return MergeConfig{}, err
+	}
+
+	return response.MergeConfig, nil
+}
+
+// UpdateGlobalMergeConfig changes instance-wide merge config, it's applied
+// to every project and repository without own merge config.
+func (client Client) UpdateGlobalMergeConfig(
+	scmID string,
+	config MergeConfig,
+) (MergeConfig, error) {
+	config.Type = ""
+
+	var response struct {
+		MergeConfig MergeConfig `json:"mergeConfig"`
+	}
+	err := client.requestJSON(
+		"POST",
+		pathf("/rest/api/1.0/admin/pull-requests/%s", scmID),
+		struct {
+			MergeConfig MergeConfig `json:"mergeConfig"`
+		}{config},
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return MergeConfig{}, err
+	}
+
+	return response.MergeConfig, nil
+}
+
+// branchRef returns full ref of the branch given by display name.
+func branchRef(branch string) string {
+	if strings.HasPrefix(branch, "refs/") {
+		return branch
+	}
+
+	return "refs/heads/" + branch
+}
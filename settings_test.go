@@ -0,0 +1,110 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestInstanceDefaultBranch(t *testing.T) {
+	var requests []string
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/1.0/application-properties" {
+			fmt.Fprint(w, `{"version": "8.9.0"}`)
+			return
+		}
+		if r.URL.Path != "/rest/api/1.0/admin/default-branch" {
+			t.Fatalf("Want /rest/api/1.0/admin/default-branch but found %s\n", r.URL.Path)
+		}
+
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"id": "refs/heads/master", "displayId": "master"}`)
+			return
+		case "PUT":
+			var payload struct {
+				ID string `json:"id"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			requests = append(requests, "PUT "+payload.ID)
+		default:
+			requests = append(requests, r.Method)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	branch, err := stashClient.GetInstanceDefaultBranch()
+	if err != nil {
+		t.Fatalf("GetInstanceDefaultBranch() not expecting an error, but received: %v\n", err)
+	}
+	if branch.DisplayID != "master" {
+		t.Fatalf("Want master but got %+v\n", branch)
+	}
+
+	if err := stashClient.SetInstanceDefaultBranch("main"); err != nil {
+		t.Fatalf("SetInstanceDefaultBranch() not expecting an error, but received: %v\n", err)
+	}
+	if err := stashClient.SetInstanceDefaultBranch(""); err != nil {
+		t.Fatalf("SetInstanceDefaultBranch() not expecting an error, but received: %v\n", err)
+	}
+
+	if fmt.Sprint(requests) != "[PUT refs/heads/main DELETE]" {
+		t.Fatalf("Unexpected requests %v\n", requests)
+	}
+}
+
+func TestGlobalMergeConfig(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/admin/pull-requests/git" {
+			t.Fatalf("Want /rest/api/1.0/admin/pull-requests/git but found %s\n", r.URL.Path)
+		}
+
+		if r.Method == "POST" {
+			var payload struct {
+				MergeConfig map[string]any `json:"mergeConfig"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if _, ok := payload.MergeConfig["type"]; ok {
+				t.Fatalf("Want type omitted but got %v\n", payload.MergeConfig)
+			}
+		}
+
+		fmt.Fprint(w, `{"mergeConfig": {
+			"defaultStrategy": {"id": "squash", "enabled": true},
+			"strategies": [{"id": "squash", "enabled": true}, {"id": "no-ff", "enabled": false}],
+			"commitMessageTemplate": {"title": "Merge {{title}}", "body": ""},
+			"type": "DEFAULT"
+		}}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	config, err := stashClient.GetGlobalMergeConfig("git")
+	if err != nil {
+		t.Fatalf("GetGlobalMergeConfig() not expecting an error, but received: %v\n", err)
+	}
+	if config.DefaultStrategy.ID != MergeStrategySquash || len(config.Strategies) != 2 ||
+		config.CommitMessageTemplate == nil || config.Type != "DEFAULT" {
+		t.Fatalf("Unexpected config %+v\n", config)
+	}
+
+	_, err = stashClient.UpdateGlobalMergeConfig("git", config)
+	if err != nil {
+		t.Fatalf("UpdateGlobalMergeConfig() not expecting an error, but received: %v\n", err)
+	}
+}
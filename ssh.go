@@ -0,0 +1,152 @@
+package stash
+
+import "net/http"
+
+type (
+	// SSHSettings configures SSH access to repositories server-wide.
+	SSHSettings struct {
+		Enabled bool `json:"enabled"`
+
+		// BaseURL is used in SSH clone URLs, e.g. ssh://git@example.com:7999,
+		// server derives it from base URL and port if it's empty.
+		BaseURL string `json:"baseUrl,omitempty"`
+
+		Port int `json:"port,omitempty"`
+
+		// Fingerprint of the server host key, it's set by the server.
+		Fingerprint *SSHFingerprint `json:"fingerprint,omitempty"`
+	}
+
+	SSHFingerprint struct {
+		Algorithm string `json:"algorithm"`
+		Value     string `json:"value"`
+	}
+)
+
+// GetSSHSettings returns server-wide SSH access settings, requires system
+// administrator permission.
+func (client Client) GetSSHSettings() (SSHSettings, error) {
+	var response SSHSettings
+	err := client.requestJSON(
+		"GET", "/rest/ssh/1.0/settings",
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return SSHSettings{}, err
+	}
+
+	return response, nil
+}
+
+// UpdateSSHSettings enables or disables SSH access and sets its base URL and
+// port, zero BaseURL and Port are left unchanged.
+func (client Client) UpdateSSHSettings(settings SSHSettings) error {
+	settings.Fingerprint = nil
+
+	_, err := client.request(
+		"PUT", "/rest/ssh/1.0/settings",
+		settings,
+		http.StatusOK,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+type (
+	// AccessKey is an SSH key granting access to every repository of the
+	// project, unlike user keys it isn't tied to any user.
+	AccessKey struct {
+		Key        SSHKey     `json:"key"`
+		Permission Permission `json:"permission"`
+	}
+
+	SSHKey struct {
+		ID    int    `json:"id,omitempty"`
+		Text  string `json:"text"`
+		Label string `json:"label,omitempty"`
+	}
+)
+
+// GetProjectAccessKeys returns SSH access keys granted on the project.
+func (client Client) GetProjectAccessKeys(projectKey string) ([]AccessKey, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[AccessKey], error) {
+			var response Paged[AccessKey]
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/keys/1.0/projects/%s/ssh?start=%d&limit=%d",
+					projectKey, start, limit,
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+// AddProjectAccessKey grants access to the project with the given public
+// key, permission is either PermissionProjectRead or PermissionProjectWrite.
+func (client Client) AddProjectAccessKey(
+	projectKey string,
+	key SSHKey,
+	permission Permission,
+) (AccessKey, error) {
+	key.ID = 0
+
+	var response AccessKey
+	err := client.requestJSON(
+		"POST",
+		pathf("/rest/keys/1.0/projects/%s/ssh", projectKey),
+		AccessKey{Key: key, Permission: permission},
+		&response,
+		http.StatusCreated,
+	)
+	if err != nil {
+		return AccessKey{}, err
+	}
+
+	return response, nil
+}
+
+// UpdateProjectAccessKeyPermission changes permission granted to the
+// project access key.
+func (client Client) UpdateProjectAccessKeyPermission(
+	projectKey string,
+	keyID int,
+	permission Permission,
+) (AccessKey, error) {
+	var response AccessKey
+	err := client.requestJSON(
+		"PUT",
+		pathf(
+			"/rest/keys/1.0/projects/%s/ssh/%d/permission/%s",
+			projectKey, keyID, permission,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return AccessKey{}, err
+	}
+
+	return response, nil
+}
+
+func (client Client) DeleteProjectAccessKey(projectKey string, keyID int) error {
+	_, err := client.request(
+		"DELETE",
+		pathf("/rest/keys/1.0/projects/%s/ssh/%d", projectKey, keyID),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
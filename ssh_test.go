@@ -0,0 +1,114 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSSHSettings(t *testing.T) {
+	var updated map[string]any
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/ssh/1.0/settings" {
+			t.Fatalf("Want /rest/ssh/1.0/settings but found %s\n", r.URL.Path)
+		}
+
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"enabled": true, "port": 7999, "baseUrl": "ssh://git@example.com:7999", "fingerprint": {"algorithm": "SHA256", "value": "abc"}}`)
+		case "PUT":
+			if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	settings, err := stashClient.GetSSHSettings()
+	if err != nil {
+		t.Fatalf("GetSSHSettings() not expecting an error, but received: %v\n", err)
+	}
+	if !settings.Enabled || settings.Port != 7999 || settings.Fingerprint == nil {
+		t.Fatalf("Unexpected settings %+v\n", settings)
+	}
+
+	settings.Enabled = false
+	err = stashClient.UpdateSSHSettings(settings)
+	if err != nil {
+		t.Fatalf("UpdateSSHSettings() not expecting an error, but received: %v\n", err)
+	}
+	if updated["enabled"] != false || updated["port"] != 7999.0 {
+		t.Fatalf("Unexpected payload %v\n", updated)
+	}
+	if _, ok := updated["fingerprint"]; ok {
+		t.Fatalf("Want fingerprint omitted but got %v\n", updated)
+	}
+}
+
+func TestProjectAccessKeys(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/keys/1.0/projects/PRJ/ssh":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [
+				{"key": {"id": 1, "text": "ssh-ed25519 AAAA ci", "label": "ci"}, "permission": "PROJECT_READ"}
+			]}`)
+		case "POST /rest/keys/1.0/projects/PRJ/ssh":
+			var payload AccessKey
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if payload.Permission != PermissionProjectWrite || payload.Key.Text != "ssh-ed25519 BBBB deploy" {
+				t.Fatalf("Unexpected payload %+v\n", payload)
+			}
+			payload.Key.ID = 2
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(payload)
+		case "PUT /rest/keys/1.0/projects/PRJ/ssh/2/permission/PROJECT_READ":
+			fmt.Fprint(w, `{"key": {"id": 2, "text": "ssh-ed25519 BBBB deploy"}, "permission": "PROJECT_READ"}`)
+		case "DELETE /rest/keys/1.0/projects/PRJ/ssh/2":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	keys, err := stashClient.GetProjectAccessKeys("PRJ")
+	if err != nil {
+		t.Fatalf("GetProjectAccessKeys() not expecting an error, but received: %v\n", err)
+	}
+	if len(keys) != 1 || keys[0].Key.Label != "ci" || keys[0].Permission != PermissionProjectRead {
+		t.Fatalf("Unexpected keys %+v\n", keys)
+	}
+
+	key, err := stashClient.AddProjectAccessKey(
+		"PRJ", SSHKey{Text: "ssh-ed25519 BBBB deploy"}, PermissionProjectWrite,
+	)
+	if err != nil {
+		t.Fatalf("AddProjectAccessKey() not expecting an error, but received: %v\n", err)
+	}
+	if key.Key.ID != 2 {
+		t.Fatalf("Want key ID 2 but got %+v\n", key)
+	}
+
+	key, err = stashClient.UpdateProjectAccessKeyPermission("PRJ", key.Key.ID, PermissionProjectRead)
+	if err != nil || key.Permission != PermissionProjectRead {
+		t.Fatalf("Want key downgraded to read but got %+v, %v\n", key, err)
+	}
+
+	err = stashClient.DeleteProjectAccessKey("PRJ", key.Key.ID)
+	if err != nil {
+		t.Fatalf("DeleteProjectAccessKey() not expecting an error, but received: %v\n", err)
+	}
+}
@@ -4,12 +4,13 @@ package stash
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"iter"
 	"log"
 	"mime/multipart"
 	"net/http"
@@ -26,17 +27,45 @@ var Log *log.Logger = log.New(os.Stdout, "", log.Ldate|log.Ltime|log.Lshortfile)
 type (
 	Stash interface {
 		CreateProject(projectKey string) (Project, error)
+		ListProjects() ([]Project, error)
+		GetProject(projectKey string) (Project, error)
+		UpdateProject(
+			projectKey string,
+			update ProjectUpdate,
+		) (Project, error)
+		DeleteProject(projectKey string) error
 		CreateRepository(projectKey, slug string) (Repository, error)
 		RenameRepository(projectKey, slug, newslug string) error
-		MoveRepository(projectKey, slug, newslug string) error
+		MoveRepository(
+			projectKey, slug string,
+			options MoveRepositoryOptions,
+		) (Repository, error)
 		RemoveRepository(projectKey, slug string) error
 		ForkRepository(projectKey, slug, forkSlug string) (*Repository, error)
 		GetRepositories() (map[int]Repository, error)
 		GetProjectRepositories(projectKey string) (map[int]Repository, error)
+		ListRepositories() ([]Repository, error)
+		ListProjectRepositories(projectKey string) ([]Repository, error)
+		GetRepositoriesPage(start, limit int) (Repositories, error)
+		GetProjectRepositoriesPage(
+			projectKey string, start, limit int,
+		) (Repositories, error)
 		GetBranches(
 			projectKey, repositorySlug string,
 		) (map[string]Branch, error)
+		ListBranches(projectKey, repositorySlug string) ([]Branch, error)
+		GetBranchesPage(
+			projectKey, repositorySlug string, start, limit int,
+		) (Branches, error)
 		GetTags(projectKey, repositorySlug string) (map[string]Tag, error)
+		ListTags(projectKey, repositorySlug string) ([]Tag, error)
+		GetTagsPage(
+			projectKey, repositorySlug string, start, limit int,
+		) (Tags, error)
+		CreateTag(
+			projectKey, repositorySlug, name, startPoint, message string,
+		) (Tag, error)
+		DeleteTag(projectKey, repositorySlug, name string) error
 		CreateBranchRestriction(
 			projectKey, repositorySlug, branch, user string,
 		) (BranchRestriction, error)
@@ -44,21 +73,78 @@ type (
 			projectKey, repositorySlug string,
 		) (BranchRestrictions, error)
 		DeleteBranchRestriction(projectKey, repositorySlug string, id int) error
+		GetLabels() ([]Label, error)
+		GetRepositoriesByLabel(label string) ([]Repository, error)
+		GetRepositoryLabels(projectKey, repositorySlug string) ([]Label, error)
+		AddRepositoryLabel(projectKey, repositorySlug, label string) error
+		RemoveRepositoryLabel(projectKey, repositorySlug, label string) error
+		GetForkAncestors(
+			projectKey, repositorySlug string,
+		) ([]Repository, error)
+		GetRefSyncStatus(
+			projectKey, repositorySlug string,
+		) (RefSyncStatus, error)
+		SetRefSyncEnabled(
+			projectKey, repositorySlug string,
+			enabled bool,
+		) (RefSyncStatus, error)
+		SynchronizeRef(
+			projectKey, repositorySlug, refID string,
+			action RefSyncAction,
+			commitMessage string,
+		) (*RejectedRef, error)
 		GetRepository(projectKey, repositorySlug string) (Repository, error)
 		GetPullRequests(
-			projectKey, repositorySlug, state string,
+			projectKey, repositorySlug string,
+			state PullRequestState,
 		) ([]PullRequest, error)
+		GetPullRequestSuggestions(
+			changesSince time.Duration,
+			limit int,
+		) ([]PullRequestSuggestion, error)
 		GetPullRequest(
 			projectKey, repositorySlug, identifier string,
 		) (PullRequest, error)
 		GetRawFile(
-			projectKey, repositorySlug, branch, filePath string,
+			projectKey, repositorySlug, filePath, branch string,
 		) ([]byte, error)
+		GetRawFileAt(
+			projectKey, repositorySlug, filePath string,
+			options RawFileOptions,
+		) (RawFile, error)
+		GetRawFileReader(
+			projectKey, repositorySlug, filePath string,
+			options RawFileOptions,
+		) (RawFileReader, error)
+		GetFiles(
+			projectKey, repositorySlug, path, at string,
+		) ([]string, error)
+		BrowseFile(
+			projectKey, repositorySlug, filePath string,
+			options BrowseOptions,
+		) (FilePage, error)
+		GetLastModified(
+			projectKey, repositorySlug, path, at string,
+		) (LastModified, error)
+		DownloadRawFile(
+			projectKey, repositorySlug, filePath string,
+			options DownloadOptions,
+			writer io.Writer,
+		) (int64, error)
+		DownloadArchive(
+			projectKey, repositorySlug string,
+			format ArchiveFormat,
+			options DownloadOptions,
+			writer io.Writer,
+		) (int64, error)
 		CreatePullRequest(
 			title, description string,
 			fromRef, toRef PullRequestRef,
 			reviewers []string,
 		) (PullRequest, error)
+		CreatePullRequestWithOptions(
+			options CreatePullRequestOptions,
+		) (PullRequest, error)
 		UpdatePullRequest(
 			projectKey, repositorySlug, identifier string,
 			version int,
@@ -69,14 +155,130 @@ type (
 			projectKey, repositorySlug, identifier string,
 			version int,
 		) (*MergeResult, error)
+		MergePullRequestWithOptions(
+			projectKey, repositorySlug, identifier string,
+			options MergeOptions,
+		) (PullRequest, error)
+		GetPullRequestMergeability(
+			projectKey, repositorySlug, identifier string,
+		) (Mergeability, error)
+		DeclinePullRequest(
+			projectKey, repositorySlug, identifier string,
+			version int,
+		) (PullRequest, error)
+		ReopenPullRequest(
+			projectKey, repositorySlug, identifier string,
+			version int,
+		) (PullRequest, error)
+		GetPullRequestActivities(
+			projectKey, repositorySlug, identifier string,
+		) ([]PullRequestActivity, error)
+		GetPullRequestTasks(
+			projectKey, repositorySlug, identifier string,
+		) ([]PullRequestTask, error)
+		CreateTask(commentID int, text string) (PullRequestTask, error)
+		UpdateTask(task PullRequestTask) (PullRequestTask, error)
+		ResolveTask(id int) (PullRequestTask, error)
+		DeleteTask(id int) error
+		SetParticipantStatus(
+			projectKey, repositorySlug, identifier, userSlug string,
+			status ParticipantStatus,
+		) (Reviewer, error)
+		RetryPullRequest(
+			projectKey, repositorySlug, identifier string,
+			attempts int,
+			fn func(PullRequest) error,
+		) error
+		GetPullRequestDiff(
+			projectKey, repositorySlug, identifier string,
+			options DiffOptions,
+		) (Diff, error)
+		GetCommitDiff(
+			projectKey, repositorySlug, commitHash string,
+			options DiffOptions,
+		) (Diff, error)
+		WriteCommitDiff(
+			projectKey, repositorySlug, commitHash string,
+			options DiffOptions,
+			writer io.Writer,
+		) error
+		WritePullRequestPatch(
+			projectKey, repositorySlug, identifier string,
+			format PatchFormat,
+			writer io.Writer,
+		) error
+		CreateBranch(
+			projectKey, repositorySlug, branchName, startPoint string,
+		) (Branch, error)
+		GetBranchingModel(
+			projectKey, repositorySlug string,
+		) (BranchingModel, error)
+		GetDefaultReviewers(
+			projectKey, repositorySlug string,
+			query DefaultReviewersQuery,
+		) ([]User, error)
+		RunRelease(
+			projectKey, repositorySlug string,
+			options ReleaseOptions,
+		) (ReleaseResult, error)
+		CreateBranches(
+			specs []BranchSpec,
+			options CreateBranchesOptions,
+		) []BranchCreation
 		DeleteBranch(projectKey, repositorySlug, branchName string) error
+		DeleteBranchWithOptions(
+			projectKey, repositorySlug, branchName string,
+			options DeleteBranchOptions,
+		) error
+		DeleteBranches(
+			projectKey, repositorySlug string,
+			branchNames []string,
+			options DeleteBranchesOptions,
+		) []BranchDeletion
 		GetCommit(projectKey, repositorySlug, commitHash string) (Commit, error)
+		GetAheadBehind(
+			projectKey, repositorySlug, ref, base string,
+		) (AheadBehind, error)
+		CompareCommits(
+			projectKey, repositorySlug, from, to string,
+		) ([]Commit, error)
+		CompareChanges(
+			projectKey, repositorySlug, from, to string,
+		) ([]Change, error)
+		CompareDiff(
+			projectKey, repositorySlug, from, to string,
+			options DiffOptions,
+		) (Diff, error)
+		GetBuildStatusStats(commitID string) (BuildStatusStats, error)
+		GetBuildStatusStatsForCommits(
+			commitIDs []string,
+		) (map[string]BuildStatusStats, error)
 		GetCommits(
 			projectKey, repositorySlug, commitSinceHash, commitUntilHash string,
 		) (Commits, error)
 		CreateComment(
 			projectKey, repositorySlug, pullRequest, text string,
 		) (Comment, error)
+		CreateInlineComment(
+			projectKey, repositorySlug, pullRequest, text string,
+			anchor CommentAnchor,
+		) (Comment, error)
+		ReplyComment(
+			projectKey, repositorySlug, pullRequest string,
+			parentID int,
+			text string,
+		) (Comment, error)
+		CreateCommitComment(
+			projectKey, repositorySlug, commitHash, text string,
+			anchor *CommentAnchor,
+		) (Comment, error)
+		GetCommitComments(
+			projectKey, repositorySlug, commitHash, path string,
+		) ([]Comment, error)
+		UploadAttachment(
+			projectKey, repositorySlug, name string,
+			reader io.Reader,
+		) (Attachment, error)
 		GetUPMToken() (string, error)
 		GetAddon(upmToken, addon string) (Addon, error)
 		InstallAddon(upmToken, path string) (string, error)
@@ -84,26 +286,251 @@ type (
 		EnableAddon(upmToken string, addon Addon) error
 		DisableAddon(upmToken string, addon Addon) error
 		SetAddonLicense(addon, license string) error
+		DeleteAddonLicense(addon string) error
+		GetAvailableAddons(query string) ([]AvailableAddon, error)
+		GetUsers(filter string) ([]User, error)
+		GetUser(userSlug string) (User, error)
+		GetCurrentUser() (User, error)
 		CreateUser(name, password, displayName, email string) (User, error)
+		SetUserPassword(name, password string) error
+		UpdateUser(name string, update UserUpdate) (User, error)
+		RenameUser(name, newName string) (User, error)
+		DeleteUser(name string) (User, error)
+		GetGroups(filter string) ([]Group, error)
+		CreateGroup(name string) (Group, error)
+		DeleteGroup(name string) (Group, error)
+		UpdateCurrentUser(update UserUpdate) (User, error)
+		GetUserSettings(userSlug string) (map[string]any, error)
+		UpdateUserSettings(userSlug string, settings map[string]any) error
 		UpdateGitMeshSettings(settings GitMeshSettings) error
+		GetInstanceDefaultBranch() (Branch, error)
+		GetGlobalMergeConfig(scmID string) (MergeConfig, error)
+		UpdateGlobalMergeConfig(
+			scmID string,
+			config MergeConfig,
+		) (MergeConfig, error)
+		SetInstanceDefaultBranch(branch string) error
+		GetSSHSettings() (SSHSettings, error)
+		UpdateSSHSettings(settings SSHSettings) error
+		GetProjectAccessTokens(projectKey string) ([]AccessToken, error)
+		GetRepositoryAccessTokens(
+			projectKey, repositorySlug string,
+		) ([]AccessToken, error)
+		CreateProjectAccessToken(
+			projectKey string,
+			options AccessTokenOptions,
+		) (AccessToken, error)
+		CreateRepositoryAccessToken(
+			projectKey, repositorySlug string,
+			options AccessTokenOptions,
+		) (AccessToken, error)
+		RevokeProjectAccessToken(projectKey, tokenID string) error
+		RevokeRepositoryAccessToken(
+			projectKey, repositorySlug, tokenID string,
+		) error
+		GetProjectAccessKeys(projectKey string) ([]AccessKey, error)
+		AddProjectAccessKey(
+			projectKey string,
+			key SSHKey,
+			permission Permission,
+		) (AccessKey, error)
+		UpdateProjectAccessKeyPermission(
+			projectKey string,
+			keyID int,
+			permission Permission,
+		) (AccessKey, error)
+		DeleteProjectAccessKey(projectKey string, keyID int) error
 		CreateMeshNode(address string) (MeshNode, error)
 		GetMeshNodes() ([]MeshNode, error)
 		DeleteMeshNode(id int, force bool) error
 		GetCluster() (Cluster, error)
+		RunHealthChecks() (HealthChecks, error)
+		GetApplicationProperties() (ApplicationProperties, error)
+		GetServerVersion() (ServerVersion, error)
 		GrantRepositoryUserPermission(
-			projectKey, repositorySlug, user, permission string,
+			projectKey, repositorySlug, user string,
+			permission Permission,
 		) error
 		RevokeRepositoryUserPermission(
 			projectKey, repositorySlug, user string,
 		) error
+		WatchRepository(projectKey, repositorySlug string) error
+		UnwatchRepository(projectKey, repositorySlug string) error
+		GetGlobalUserPermissions() ([]UserPermission, error)
+		GetGlobalGroupPermissions() ([]GroupPermission, error)
+		GetProjectUserPermissions(projectKey string) ([]UserPermission, error)
+		GetProjectGroupPermissions(projectKey string) ([]GroupPermission, error)
+		GrantProjectUserPermission(
+			projectKey, user string,
+			permission Permission,
+		) error
+		RevokeProjectUserPermission(projectKey, user string) error
+		GrantProjectGroupPermission(
+			projectKey, group string,
+			permission Permission,
+		) error
+		RevokeProjectGroupPermission(projectKey, group string) error
+		GetProjectDefaultPermission(
+			projectKey string,
+			permission Permission,
+		) (bool, error)
+		SetProjectDefaultPermission(
+			projectKey string,
+			permission Permission,
+			allow bool,
+		) error
+		GetRepositoryUserPermissions(
+			projectKey, repositorySlug string,
+		) ([]UserPermission, error)
+		GetRepositoryGroupPermissions(
+			projectKey, repositorySlug string,
+		) ([]GroupPermission, error)
+		GrantRepositoryGroupPermission(
+			projectKey, repositorySlug, group string,
+			permission Permission,
+		) error
+		RevokeRepositoryGroupPermission(
+			projectKey, repositorySlug, group string,
+		) error
+		UpdateRepository(
+			projectKey, repositorySlug string,
+			update RepositoryUpdate,
+		) (Repository, error)
+		GetDefaultBranch(projectKey, repositorySlug string) (Branch, error)
+		SetDefaultBranch(projectKey, repositorySlug, branch string) error
+		GetPullRequestSettings(
+			projectKey, repositorySlug string,
+		) (PullRequestSettings, error)
+		UpdatePullRequestSettings(
+			projectKey, repositorySlug string,
+			settings PullRequestSettings,
+		) (PullRequestSettings, error)
+		GetWebhooks(projectKey, repositorySlug string) ([]Webhook, error)
+		ListWebhooks(
+			projectKey, repositorySlug string,
+			filter WebhookFilter,
+		) ([]Webhook, error)
+		CreateWebhook(
+			projectKey, repositorySlug string,
+			webhook Webhook,
+		) (Webhook, error)
+		UpdateWebhook(
+			projectKey, repositorySlug string,
+			webhook Webhook,
+		) (Webhook, error)
+		DeleteWebhook(projectKey, repositorySlug string, id int) error
+		ListProjectWebhooks(
+			projectKey string,
+			filter WebhookFilter,
+		) ([]Webhook, error)
+		CreateProjectWebhook(
+			projectKey string,
+			webhook Webhook,
+		) (Webhook, error)
+		UpdateProjectWebhook(
+			projectKey string,
+			webhook Webhook,
+		) (Webhook, error)
+		DeleteProjectWebhook(projectKey string, id int) error
+		TestWebhook(
+			projectKey, repositorySlug string,
+			webhook Webhook,
+		) (WebhookTest, error)
+		TestProjectWebhook(
+			projectKey string,
+			webhook Webhook,
+		) (WebhookTest, error)
+		GetProjectDefaultTasks(projectKey string) ([]DefaultTask, error)
+		GetRepositoryDefaultTasks(
+			projectKey, repositorySlug string,
+		) ([]DefaultTask, error)
+		CreateProjectDefaultTask(
+			projectKey string,
+			task DefaultTask,
+		) (DefaultTask, error)
+		CreateRepositoryDefaultTask(
+			projectKey, repositorySlug string,
+			task DefaultTask,
+		) (DefaultTask, error)
+		UpdateProjectDefaultTask(
+			projectKey string,
+			task DefaultTask,
+		) (DefaultTask, error)
+		UpdateRepositoryDefaultTask(
+			projectKey, repositorySlug string,
+			task DefaultTask,
+		) (DefaultTask, error)
+		DeleteProjectDefaultTask(projectKey string, id int) error
+		DeleteRepositoryDefaultTask(
+			projectKey, repositorySlug string,
+			id int,
+		) error
+		GetWebhookStatistics(
+			projectKey, repositorySlug string,
+			id int,
+			event WebhookEvent,
+		) (WebhookStatistics, error)
+		GetLatestWebhookInvocation(
+			projectKey, repositorySlug string,
+			id int,
+			filter WebhookInvocationFilter,
+		) (*WebhookInvocation, error)
+		RotateWebhookSecrets(
+			projectKey, secret string,
+			options RotateWebhookSecretsOptions,
+		) ([]WebhookRotation, error)
+		GetRefRestrictions(
+			projectKey, repositorySlug string,
+		) ([]RefRestriction, error)
+		CreateRefRestriction(
+			projectKey, repositorySlug string,
+			options RefRestrictionOptions,
+		) (RefRestriction, error)
+		DeleteRefRestriction(projectKey, repositorySlug string, id int) error
+		CopyRefRestrictions(
+			source RepositoryRef,
+			targets []RepositoryRef,
+			options CopyRefRestrictionsOptions,
+		) ([]RestrictionsCopy, error)
+		PlanRepository(spec RepositorySpec) (RepositoryPlan, error)
+		ApplyRepository(spec RepositorySpec) (RepositoryPlan, error)
+		AuditPermissions(options PermissionAuditOptions) (PermissionAudit, error)
+		Project(projectKey string) ProjectScope
+		WithContext(ctx context.Context) Stash
+		ThrottleStats() ThrottleStats
+		AllRepositories(ctx context.Context) iter.Seq2[Repository, error]
+		AllPullRequests(
+			ctx context.Context,
+			options PullRequestsOptions,
+		) iter.Seq2[PullRequest, error]
 	}
 
 	Client struct {
 		userName string
 		password string
 		baseURL  *url.URL
+		prefetch int
+		version  *versionCache
+		dryRun   *DryRunRecorder
+		limiter  chan struct{}
+		cache    *listingCache
+		mirrors  []*url.URL
+		dump     func(*http.Request) bool
+		http     *http.Client
+		ctx      context.Context
+		tokens   TokenSource
+		retries  *RetryPolicy
+		throttle *throttleCounters
+
+		throttleRetries int
+
+		authHeaders http.Header
+		authCookies []*http.Cookie
 	}
 
+	// Option configures optional Client behavior, see NewClient.
+	Option func(*Client)
+
 	Page struct {
 		IsLastPage    bool `json:"isLastPage"`
 		Size          int  `json:"size"`
@@ -111,31 +538,55 @@ type (
 		NextPageStart int  `json:"nextPageStart"`
 	}
 
-	Repositories struct {
-		IsLastPage    bool         `json:"isLastPage"`
-		Size          int          `json:"size"`
-		Start         int          `json:"start"`
-		NextPageStart int          `json:"nextPageStart"`
-		Repository    []Repository `json:"values"`
+	// Paged is a single page of values returned by listing endpoints.
+	Paged[T any] struct {
+		Page
+		Values []T `json:"values"`
 	}
 
+	Repositories = Paged[Repository]
+
+	Projects = Paged[Project]
+
 	Repository struct {
-		ID      int     `json:"id"`
-		Name    string  `json:"name"`
-		Slug    string  `json:"slug"`
-		Project Project `json:"project"`
-		ScmID   string  `json:"scmId"`
-		Links   Links   `json:"links"`
+		ID          int     `json:"id"`
+		Name        string  `json:"name"`
+		Slug        string  `json:"slug"`
+		Description string  `json:"description"`
+		Public      bool    `json:"public"`
+		Forkable    bool    `json:"forkable"`
+		Project     Project `json:"project"`
+		ScmID       string  `json:"scmId"`
+		Links       Links   `json:"links"`
+
+		// Origin is a repository this one is forked from, it's nil for
+		// repositories which are not forks. Server returns origin only
+		// partially, e.g. without its own origin, see GetForkAncestors.
+		Origin *Repository `json:"origin,omitempty"`
+
+		Raw json.RawMessage `json:"-"`
 	}
 
 	Project struct {
-		ID   int    `json:"id"`
-		Key  string `json:"key"`
-		Name string `json:"name"`
+		ID          int    `json:"id"`
+		Key         string `json:"key"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Public      bool   `json:"public"`
+		Type        string `json:"type"`
+
+		// SelfURL is the self link of the project, it's kept as string
+		// instead of Links, so project stays comparable.
+		SelfURL string `json:"-"`
 	}
 
 	Links struct {
 		Clones []Clone `json:"clone"`
+		Self   []Link  `json:"self"`
+	}
+
+	Link struct {
+		HREF string `json:"href"`
 	}
 
 	Clone struct {
@@ -143,13 +594,7 @@ type (
 		Name string `json:"name"`
 	}
 
-	Branches struct {
-		IsLastPage    bool     `json:"isLastPage"`
-		Size          int      `json:"size"`
-		Start         int      `json:"start"`
-		NextPageStart int      `json:"nextPageStart"`
-		Branch        []Branch `json:"values"`
-	}
+	Branches = Paged[Branch]
 
 	Branch struct {
 		ID              string `json:"id"`
@@ -158,15 +603,15 @@ type (
 		IsDefault       bool   `json:"isDefault"`
 	}
 
-	Tags struct {
-		Page
-		Tags []Tag `json:"values"`
-	}
+	Tags = Paged[Tag]
 
 	Tag struct {
 		ID        string `json:"id"`
 		DisplayID string `json:"displayId"`
 		Hash      string `json:"hash"`
+		// LatestCommit is the tagged commit, Hash is the tag object
+		// itself for annotated tags.
+		LatestCommit string `json:"latestCommit"`
 	}
 
 	BranchRestrictions struct {
@@ -185,25 +630,27 @@ type (
 		Groups []string `json:"groups"`
 	}
 
-	PullRequests struct {
-		Page
-		PullRequests []PullRequest `json:"values"`
-	}
+	PullRequests = Paged[PullRequest]
 
 	PullRequest struct {
-		ID          int        `id:"closed"`
-		Version     int        `            json:"version"`
-		Closed      bool       `            json:"closed"`
-		Open        bool       `            json:"open"`
-		State       string     `            json:"state"`
-		Title       string     `            json:"title"`
-		Description string     `            json:"description"`
-		FromRef     Ref        `            json:"fromRef"`
-		ToRef       Ref        `            json:"toRef"`
-		CreatedDate int64      `            json:"createdDate"`
-		UpdatedDate int64      `            json:"updatedDate"`
-		Reviewers   []Reviewer `            json:"reviewers"`
-		Author      Author     `            json:"author"`
+		ID           int              `id:"closed"`
+		Version      int              `            json:"version"`
+		Closed       bool             `            json:"closed"`
+		Open         bool             `            json:"open"`
+		State        PullRequestState `            json:"state"`
+		Title        string           `            json:"title"`
+		Description  string           `            json:"description"`
+		FromRef      Ref              `            json:"fromRef"`
+		ToRef        Ref              `            json:"toRef"`
+		CreatedDate  int64            `            json:"createdDate"`
+		UpdatedDate  int64            `            json:"updatedDate"`
+		Reviewers    []Reviewer       `            json:"reviewers"`
+		Participants []Reviewer       `            json:"participants"`
+		Author       Author           `            json:"author"`
+		Draft        bool             `            json:"draft"`
+		Links        Links            `            json:"links"`
+
+		Raw json.RawMessage `json:"-"`
 	}
 
 	Cluster struct {
@@ -232,7 +679,18 @@ type (
 	}
 
 	Comment struct {
-		ID int `json:"id"`
+		ID          int    `json:"id"`
+		Version     int    `json:"version"`
+		Text        string `json:"text"`
+		Author      User   `json:"author"`
+		CreatedDate int64  `json:"createdDate"`
+		UpdatedDate int64  `json:"updatedDate"`
+
+		// Anchor is set for inline comments.
+		Anchor *CommentAnchor `json:"anchor,omitempty"`
+
+		// Comments are replies to the comment, each with own replies.
+		Comments []Comment `json:"comments,omitempty"`
 	}
 
 	Ref struct {
@@ -256,35 +714,81 @@ type (
 		}
 	}
 
-	errorResponse struct {
+	// ResponseError is returned when server replies with unexpected
+	// status, it matches sentinel errors like ErrNotFound with errors.Is:
+	//
+	//	if errors.Is(err, stash.ErrNotFound) { ... }
+	ResponseError struct {
 		StatusCode int
 		Reason     string
+
+		// Body is the response body as server sent it.
+		Body []byte
+
+		// Errors are parsed from Body if it's in standard Bitbucket format.
+		Errors []ErrorMessage
+
 		error
 	}
 
 	stashError struct {
-		Errors []struct {
-			Context       string `json:"context"`
-			Message       string `json:"message"`
-			ExceptionName string `json:"exceptionName"`
-		} `json:"errors"`
+		Errors []ErrorMessage `json:"errors"`
+	}
+
+	// ErrorMessage is a single entry of error reported by the server.
+	ErrorMessage struct {
+		Context       string `json:"context"`
+		Message       string `json:"message"`
+		ExceptionName string `json:"exceptionName"`
+
+		// Conflicted and Vetoes are reported when operation is rejected by
+		// the server, e.g. branch deletion blocked by restrictions.
+		Conflicted bool   `json:"conflicted,omitempty"`
+		Vetoes     []Veto `json:"vetoes,omitempty"`
+	}
+
+	// Veto is a reason given by the server or an addon for rejecting an
+	// operation.
+	Veto struct {
+		SummaryMessage  string `json:"summaryMessage"`
+		DetailedMessage string `json:"detailedMessage"`
+	}
+
+	// DeleteBranchOptions configures DeleteBranchWithOptions.
+	DeleteBranchOptions struct {
+		// DryRun only checks that branch can be deleted.
+		DryRun bool
 	}
 
 	// Pull Request Types
 
+	// User fields are omitted when empty, since the same model is sent
+	// when referencing users, e.g. reviewers, and server expects only name
+	// there.
 	User struct {
+		ID          int    `json:"id,omitempty"`
 		Name        string `json:"name"`
-		Email       string `json:"emailAddress"`
+		Slug        string `json:"slug,omitempty"`
+		Email       string `json:"emailAddress,omitempty"`
 		Password    string `json:"password,omitempty"`
-		DisplayName string `json:"displayName"`
+		DisplayName string `json:"displayName,omitempty"`
+		Active      bool   `json:"active,omitempty"`
+		Type        string `json:"type,omitempty"`
+		AvatarURL   string `json:"avatarUrl,omitempty"`
+		Links       *Links `json:"links,omitempty"`
 	}
 
 	Reviewer struct {
-		User User `json:"user"`
+		User     User              `json:"user"`
+		Role     ParticipantRole   `json:"role,omitempty"`
+		Approved bool              `json:"approved,omitempty"`
+		Status   ParticipantStatus `json:"status,omitempty"`
 	}
 
 	Author struct {
-		User User `json:"user"`
+		User     User            `json:"user"`
+		Role     ParticipantRole `json:"role,omitempty"`
+		Approved bool            `json:"approved,omitempty"`
 	}
 
 	PullRequestProject struct {
@@ -309,26 +813,100 @@ type (
 		// FromRef and ToRef should be PullRequestRef but there is interface{}
 		// for omitting empty values. encoding/json can't handle empty structs
 		// and omit them.
-		FromRef   interface{} `json:"fromRef,omitempty"`
-		ToRef     interface{} `json:"toRef,omitempty"`
-		Reviewers []Reviewer  `json:"reviewers,omitempty"`
+		FromRef      interface{}    `json:"fromRef,omitempty"`
+		ToRef        interface{}    `json:"toRef,omitempty"`
+		Reviewers    []Reviewer     `json:"reviewers,omitempty"`
+		Participants []Reviewer     `json:"participants,omitempty"`
+		Draft        bool           `json:"draft,omitempty"`
+		Properties   map[string]any `json:"properties,omitempty"`
+	}
+
+	// CreatePullRequestOptions describes a pull request to create. FromRef
+	// and ToRef may point to different repositories, e.g. to open a pull
+	// request from a fork.
+	CreatePullRequestOptions struct {
+		Title       string
+		Description string
+		FromRef     PullRequestRef
+		ToRef       PullRequestRef
+		// Reviewers is a list of user names.
+		Reviewers []string
+		// Participants are added with the given roles in addition to
+		// Reviewers, ones without role are added as reviewers.
+		Participants []Reviewer
+		// Draft requires Bitbucket 8.18 or newer.
+		Draft bool
+		// Properties are stored with the pull request, they are used
+		// by plugins, e.g. for labels.
+		Properties map[string]any
+	}
+
+	// ProjectUpdate changes project details, nil fields are left as is.
+	ProjectUpdate struct {
+		Name        *string `json:"name,omitempty"`
+		Description *string `json:"description,omitempty"`
+		Public      *bool   `json:"public,omitempty"`
+	}
+
+	// MoveRepositoryOptions describes where MoveRepository moves repository,
+	// empty fields are left unchanged.
+	MoveRepositoryOptions struct {
+		// ProjectKey is a key of the target project.
+		ProjectKey string
+		// Name is a new name of the repository, slug is derived from it.
+		Name string
 	}
 
 	CommentResource struct {
-		Text string `json:"text"`
+		Text   string         `json:"text"`
+		Anchor *CommentAnchor `json:"anchor,omitempty"`
+		Parent *CommentParent `json:"parent,omitempty"`
+	}
+
+	// CommentParent is a comment being replied to.
+	CommentParent struct {
+		ID int `json:"id"`
+	}
+
+	// CommentAnchor places comment on a file or a line of pull request
+	// diff. Line and LineType are omitted for file comments. FromHash and
+	// ToHash pin the comment to the diff between these commits, otherwise
+	// the latest pull request diff is used.
+	CommentAnchor struct {
+		Path     string          `json:"path"`
+		SrcPath  string          `json:"srcPath,omitempty"`
+		Line     int             `json:"line,omitempty"`
+		LineType CommentLineType `json:"lineType,omitempty"`
+		FileType CommentFileType `json:"fileType,omitempty"`
+		FromHash string          `json:"fromHash,omitempty"`
+		ToHash   string          `json:"toHash,omitempty"`
+		DiffType string          `json:"diffType,omitempty"`
 	}
 
 	Commit struct {
 		ID        string `json:"id"`
 		DisplayID string `json:"displayId"`
-		Author    struct {
-			Name         string `json:"name"`
-			EmailAddress string `json:"emailAddress"`
-		} `json:"author"`
-		AuthorTimestamp int64 `json:"authorTimestamp"` // in milliseconds since the epoch
+		// Author contains only name and email unless commit author is
+		// mapped to a Bitbucket user.
+		Author          CommitAuthor `json:"author"`
+		AuthorTimestamp int64        `json:"authorTimestamp"` // in milliseconds since the epoch
+		Message         string       `json:"message"`
 		Attributes      struct {
 			JiraKeys []string `json:"jira-key"`
 		} `json:"attributes"`
+
+		Raw json.RawMessage `json:"-"`
+	}
+
+	// CommitAuthor is a user who authored the commit.
+	CommitAuthor struct {
+		User
+
+		// EmailAddress is the same as Email.
+		//
+		// Deprecated: use Email, EmailAddress is kept for code written
+		// when author had only name and email.
+		EmailAddress string `json:"-"`
 	}
 
 	Commits struct {
@@ -378,30 +956,144 @@ type (
 			Link            string `json:"link"`
 		} `json:"vendor"`
 	}
+
+	AvailableAddon struct {
+		Links struct {
+			Self    string `json:"self"`
+			Details string `json:"details"`
+			Binary  string `json:"binary"`
+		} `json:"links"`
+		Key                  string `json:"key"`
+		Name                 string `json:"name"`
+		Version              string `json:"version"`
+		Summary              string `json:"summary"`
+		Installed            bool   `json:"installed"`
+		Installable          bool   `json:"installable"`
+		Stable               bool   `json:"stable"`
+		Free                 bool   `json:"free"`
+		DataCenterCompatible bool   `json:"dataCenterCompatible"`
+		Vendor               struct {
+			Name            string `json:"name"`
+			MarketplaceLink string `json:"marketplaceLink"`
+			Link            string `json:"link"`
+		} `json:"vendor"`
+	}
+
+	availableAddons struct {
+		Links struct {
+			Next string `json:"next"`
+		} `json:"links"`
+		Plugins []AvailableAddon `json:"plugins"`
+	}
 )
 
 const (
 	stashPageLimit        = 25
 	stashUnexpectedStatus = "unexpected server status"
+
+	// maxErrorBodyLength limits how much of the response body is put into
+	// error message, full body is kept in the error value.
+	maxErrorBodyLength = 512
+)
+
+// Sentinel errors matched by ResponseError of the corresponding status.
+var (
+	// ErrBadRequest is matched by 400, e.g. invalid parameters.
+	ErrBadRequest = errors.New("bad request")
+
+	// ErrUnauthorized is matched by 401, credentials are missing or
+	// invalid.
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden is matched by 403, user lacks permissions.
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrNotFound is matched by 404, requested resource is absent.
+	ErrNotFound = errors.New("not found")
+
+	// ErrConflict is matched by 409, resource state conflicts with the
+	// request, e.g. it already exists.
+	ErrConflict = errors.New("conflict")
+
+	// ErrRateLimited is matched by 429, server rate limiter rejected the
+	// request, see WithThrottleRetries.
+	ErrRateLimited = errors.New("rate limited")
 )
 
-var httpTransport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+var sentinelStatuses = map[error]int{
+	ErrBadRequest:   http.StatusBadRequest,
+	ErrUnauthorized: http.StatusUnauthorized,
+	ErrForbidden:    http.StatusForbidden,
+	ErrNotFound:     http.StatusNotFound,
+	ErrConflict:     http.StatusConflict,
+	ErrRateLimited:  http.StatusTooManyRequests,
+}
+
+func (e ResponseError) Error() string {
+	if e.error != nil {
+		return fmt.Sprintf("%s (%d): %s", e.Reason, e.StatusCode, e.error)
+	}
+
+	if body := strings.TrimSpace(string(e.Body)); body != "" {
+		if len(body) > maxErrorBodyLength {
+			body = body[:maxErrorBodyLength] + "..."
+		}
 
-var httpClient *http.Client = &http.Client{Timeout: 10 * time.Second, Transport: httpTransport}
+		return fmt.Sprintf(
+			"%s (%d): %s", e.Reason, e.StatusCode, redactBody([]byte(body)),
+		)
+	}
 
-func (e errorResponse) Error() string {
 	return fmt.Sprintf("%s (%d)", e.Reason, e.StatusCode)
 }
 
-func NewClient(userName, password string, baseURL *url.URL) Stash {
-	return Client{userName: userName, password: password, baseURL: baseURL}
+func (e ResponseError) Unwrap() error {
+	return e.error
+}
+
+// Is matches sentinel error of the response status code.
+func (e ResponseError) Is(target error) bool {
+	status, ok := sentinelStatuses[target]
+	return ok && e.StatusCode == status
+}
+
+func NewClient(
+	userName, password string,
+	baseURL *url.URL,
+	options ...Option,
+) Stash {
+	client := Client{
+		userName: userName,
+		password: password,
+		baseURL:  baseURL,
+		version:  &versionCache{},
+		http:     newHTTPClient(),
+		throttle: &throttleCounters{},
+
+		throttleRetries: defaultThrottleRetries,
+	}
+	for _, option := range options {
+		option(&client)
+	}
+
+	return client
+}
+
+// WithPagePrefetch makes listings fetch pages after the first one
+// concurrently using given amount of workers. Page starts are computed
+// ahead, so pages are fetched one by one again once server returns less
+// values than asked for.
+func WithPagePrefetch(workers int) Option {
+	return func(client *Client) {
+		client.prefetch = workers
+	}
 }
 
 func (client Client) CreateProject(
 	projectKey string,
 ) (Project, error) {
 	data, err := client.request(
-		"POST", fmt.Sprintf(
+		"POST", pathf(
 			"/rest/api/1.0/projects/",
 		),
 		struct {
@@ -422,11 +1114,83 @@ func (client Client) CreateProject(
 	return response, nil
 }
 
+// ListProjects returns all projects visible to the user, personal projects
+// are not included.
+func (client Client) ListProjects() ([]Project, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[Project], error) {
+			var response Projects
+			err := client.requestJSON(
+				"GET",
+				pathf(
+					"/rest/api/1.0/projects?start=%d&limit=%d",
+					start, limit,
+				),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+// GetProject returns the project with the given key.
+func (client Client) GetProject(projectKey string) (Project, error) {
+	var response Project
+	err := client.requestJSON(
+		"GET",
+		pathf("/rest/api/1.0/projects/%s", projectKey),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Project{}, err
+	}
+
+	return response, nil
+}
+
+// UpdateProject changes name, description or visibility of the project and
+// returns updated project.
+func (client Client) UpdateProject(
+	projectKey string,
+	update ProjectUpdate,
+) (Project, error) {
+	var response Project
+	err := client.requestJSON(
+		"PUT",
+		pathf("/rest/api/1.0/projects/%s", projectKey),
+		update,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Project{}, err
+	}
+
+	return response, nil
+}
+
+// DeleteProject deletes the project, which must have no repositories left.
+func (client Client) DeleteProject(projectKey string) error {
+	_, err := client.request(
+		"DELETE",
+		pathf("/rest/api/1.0/projects/%s", projectKey),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
 func (client Client) CreateRepository(
 	projectKey, repositorySlug string,
 ) (Repository, error) {
 	data, err := client.request(
-		"POST", fmt.Sprintf(
+		"POST", pathf(
 			"/rest/api/1.0/projects/%s/repos",
 			projectKey,
 		),
@@ -530,7 +1294,7 @@ func (client Client) GetMeshNodes() ([]MeshNode, error) {
 }
 
 func (client Client) DeleteMeshNode(id int, force bool) error {
-	uri := fmt.Sprintf("/rest/api/latest/admin/git/mesh/nodes/%d", id)
+	uri := pathf("/rest/api/latest/admin/git/mesh/nodes/%d", id)
 	if force {
 		uri += "?force=true"
 	}
@@ -562,34 +1326,50 @@ func (client Client) GetCluster() (Cluster, error) {
 	return response, nil
 }
 
+// MoveRepository moves repository to another project and/or renames it,
+// returning the repository as it's after the move.
 func (client Client) MoveRepository(
-	projectKey, repositorySlug, newProjectKey string,
-) error {
+	projectKey, repositorySlug string,
+	options MoveRepositoryOptions,
+) (Repository, error) {
 	payload := struct {
-		Project struct {
+		Name    string `json:"name,omitempty"`
+		Project *struct {
 			Key string `json:"key"`
-		} `json:"project"`
-	}{}
+		} `json:"project,omitempty"`
+	}{
+		Name: options.Name,
+	}
 
-	payload.Project.Key = newProjectKey
+	if options.ProjectKey != "" {
+		payload.Project = &struct {
+			Key string `json:"key"`
+		}{options.ProjectKey}
+	}
 
-	_, err := client.request(
+	var response Repository
+	err := client.requestJSON(
 		"PUT",
-		"/rest/api/1.0/projects/%s/repos/%s",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s",
+			projectKey, repositorySlug,
+		),
 		payload,
+		&response,
+		http.StatusOK,
 		http.StatusCreated,
 	)
 	if err != nil {
-		return err
+		return Repository{}, err
 	}
 
-	return nil
+	return response, nil
 }
 
 func (client Client) RemoveRepository(projectKey, repositorySlug string) error {
 	_, err := client.request(
 		"DELETE",
-		fmt.Sprintf(
+		pathf(
 			"/rest/api/1.0/projects/%s/repos/%s",
 			projectKey, repositorySlug,
 		),
@@ -609,7 +1389,7 @@ func (client Client) RenameRepository(
 ) error {
 	_, err := client.request(
 		"PUT",
-		fmt.Sprintf(
+		pathf(
 			"/rest/api/1.0/projects/%s/repos/%s",
 			projectKey,
 			repositorySlug,
@@ -631,147 +1411,278 @@ func (client Client) RenameRepository(
 func (client Client) GetProjectRepositories(
 	projectKey string,
 ) (map[int]Repository, error) {
-	start := 0
+	list, err := client.ListProjectRepositories(projectKey)
+	if err != nil {
+		return nil, err
+	}
+
 	repositories := make(map[int]Repository)
-	morePages := true
-	for morePages {
-		data, err := client.request(
-			"GET",
-			fmt.Sprintf(
-				"/rest/api/1.0/projects/%s/repos?start=%d&limit=%d",
-				projectKey,
-				start, stashPageLimit,
-			),
-			nil,
-			http.StatusOK,
-		)
-		if err != nil {
-			return nil, err
-		}
+	for _, repo := range list {
+		repositories[repo.ID] = repo
+	}
 
-		var response Repositories
-		err = json.Unmarshal(data, &response)
-		if err != nil {
-			return nil, err
-		}
+	return repositories, nil
+}
 
-		for _, repo := range response.Repository {
-			repositories[repo.ID] = repo
-		}
+// ListProjectRepositories returns all repositories of the given project in
+// the order returned by the server.
+func (client Client) ListProjectRepositories(
+	projectKey string,
+) ([]Repository, error) {
+	return cached(
+		client,
+		"projects/"+projectKey+"/repos",
+		func() ([]Repository, error) {
+			return collectPages(
+				client,
+				func(start, limit int) (Paged[Repository], error) {
+					return client.GetProjectRepositoriesPage(
+						projectKey, start, limit,
+					)
+				},
+			)
+		},
+	)
+}
 
-		morePages = !response.IsLastPage
-		start = response.NextPageStart
+// GetProjectRepositoriesPage returns a single page of repositories of the
+// given project along with paging metadata.
+func (client Client) GetProjectRepositoriesPage(
+	projectKey string, start, limit int,
+) (Repositories, error) {
+	var response Repositories
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos?start=%d&limit=%d",
+			projectKey,
+			start, limit,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Repositories{}, err
 	}
 
-	return repositories, nil
+	return response, nil
 }
 
 // GetRepositories returns a map of repositories indexed by repository URL.
 func (client Client) GetRepositories() (map[int]Repository, error) {
-	start := 0
-	repositories := make(map[int]Repository)
-	morePages := true
-	for morePages {
-		data, err := client.request(
-			"GET",
-			fmt.Sprintf(
-				"/rest/api/1.0/repos?start=%d&limit=%d",
-				start, stashPageLimit,
-			),
-			nil,
-			http.StatusOK,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		var response Repositories
-		err = json.Unmarshal(data, &response)
-		if err != nil {
-			return nil, err
-		}
-
-		for _, repo := range response.Repository {
-			repositories[repo.ID] = repo
-		}
+	list, err := client.ListRepositories()
+	if err != nil {
+		return nil, err
+	}
 
-		morePages = !response.IsLastPage
-		start = response.NextPageStart
+	repositories := make(map[int]Repository)
+	for _, repo := range list {
+		repositories[repo.ID] = repo
 	}
 
 	return repositories, nil
 }
 
-// GetBranches returns a map of branches indexed by branch display name for the given repository.
-func (client Client) GetBranches(
-	projectKey, repositorySlug string,
-) (map[string]Branch, error) {
-	start := 0
-	branches := make(map[string]Branch)
-	morePages := true
-	for morePages {
-		data, err := client.request(
-			"GET",
-			fmt.Sprintf(
-				"/rest/api/1.0/projects/%s/repos/%s/branches?start=%d&limit=%d",
-				projectKey, repositorySlug, start, stashPageLimit,
-			),
-			nil,
-			http.StatusOK,
-		)
-		if err != nil {
-			return nil, err
-		}
+// ListRepositories returns all repositories in the order returned by the
+// server.
+func (client Client) ListRepositories() ([]Repository, error) {
+	return cached(
+		client,
+		"repos",
+		func() ([]Repository, error) {
+			return collectPages(
+				client,
+				func(start, limit int) (Paged[Repository], error) {
+					return client.GetRepositoriesPage(start, limit)
+				},
+			)
+		},
+	)
+}
 
-		var response Branches
-		if err := json.Unmarshal(data, &response); err != nil {
-			return nil, err
-		}
+// GetRepositoriesPage returns a single page of repositories along with
+// paging metadata.
+func (client Client) GetRepositoriesPage(start, limit int) (Repositories, error) {
+	var response Repositories
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/repos?start=%d&limit=%d",
+			start, limit,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Repositories{}, err
+	}
 
-		for _, branch := range response.Branch {
-			branches[branch.DisplayID] = branch
-		}
-		morePages = !response.IsLastPage
-		start = response.NextPageStart
+	return response, nil
+}
+
+// GetBranches returns a map of branches indexed by branch display name for the given repository.
+func (client Client) GetBranches(
+	projectKey, repositorySlug string,
+) (map[string]Branch, error) {
+	list, err := client.ListBranches(projectKey, repositorySlug)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make(map[string]Branch)
+	for _, branch := range list {
+		branches[branch.DisplayID] = branch
 	}
 	return branches, nil
 }
 
+// ListBranches returns all branches of the given repository in the order
+// returned by the server.
+func (client Client) ListBranches(
+	projectKey, repositorySlug string,
+) ([]Branch, error) {
+	return cached(
+		client,
+		"projects/"+projectKey+"/repos/"+repositorySlug+"/branches",
+		func() ([]Branch, error) {
+			return collectPages(
+				client,
+				func(start, limit int) (Paged[Branch], error) {
+					return client.GetBranchesPage(
+						projectKey, repositorySlug, start, limit,
+					)
+				},
+			)
+		},
+	)
+}
+
+// GetBranchesPage returns a single page of branches of the given repository
+// along with paging metadata.
+func (client Client) GetBranchesPage(
+	projectKey, repositorySlug string, start, limit int,
+) (Branches, error) {
+	var response Branches
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/branches?start=%d&limit=%d",
+			projectKey, repositorySlug, start, limit,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Branches{}, err
+	}
+
+	return response, nil
+}
+
 // GetTags returns a map of tags indexed by tag display name for the given repository.
 func (client Client) GetTags(
 	projectKey, repositorySlug string,
 ) (map[string]Tag, error) {
-	start := 0
+	list, err := client.ListTags(projectKey, repositorySlug)
+	if err != nil {
+		return nil, err
+	}
+
 	tags := make(map[string]Tag)
-	morePages := true
-	for morePages {
-		data, err := client.request(
-			"GET",
-			fmt.Sprintf(
-				"/rest/api/1.0/projects/%s/repos/%s/tags?start=%d&limit=%d",
-				projectKey, repositorySlug, start, stashPageLimit,
-			),
-			nil,
-			http.StatusOK,
-		)
-		if err != nil {
-			return nil, err
-		}
+	for _, tag := range list {
+		tags[tag.DisplayID] = tag
+	}
 
-		var response Tags
-		if err := json.Unmarshal(data, &response); err != nil {
-			return nil, err
-		}
+	return tags, nil
+}
 
-		for _, tag := range response.Tags {
-			tags[tag.DisplayID] = tag
-		}
+// ListTags returns all tags of the given repository in the order returned by
+// the server.
+func (client Client) ListTags(
+	projectKey, repositorySlug string,
+) ([]Tag, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[Tag], error) {
+			return client.GetTagsPage(
+				projectKey, repositorySlug, start, limit,
+			)
+		},
+	)
+}
 
-		morePages = !response.IsLastPage
-		start = response.NextPageStart
+// GetTagsPage returns a single page of tags of the given repository along
+// with paging metadata.
+func (client Client) GetTagsPage(
+	projectKey, repositorySlug string, start, limit int,
+) (Tags, error) {
+	var response Tags
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/tags?start=%d&limit=%d",
+			projectKey, repositorySlug, start, limit,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Tags{}, err
 	}
 
-	return tags, nil
+	return response, nil
+}
+
+// CreateTag creates annotated tag at the start point, which is a branch,
+// tag or commit. Lightweight tag is created if message is empty.
+func (client Client) CreateTag(
+	projectKey, repositorySlug, name, startPoint, message string,
+) (Tag, error) {
+	var response Tag
+	err := client.requestJSON(
+		"POST",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/tags",
+			projectKey, repositorySlug,
+		),
+		struct {
+			Name       string `json:"name"`
+			StartPoint string `json:"startPoint"`
+			Message    string `json:"message,omitempty"`
+		}{name, startPoint, message},
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return Tag{}, err
+	}
+
+	return response, nil
+}
+
+// DeleteTag deletes the tag with the given name, e.g. release/1.0.
+func (client Client) DeleteTag(
+	projectKey, repositorySlug, name string,
+) error {
+	if name == "" {
+		return ValidationError{Reason: "tag name is empty"}
+	}
+
+	_, err := client.request(
+		"DELETE",
+		pathf(
+			"/rest/git/1.0/projects/%s/repos/%s/tags/%s",
+			projectKey, repositorySlug, rawPath(escapeFilePath(name)),
+		),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
 }
 
 // GetRepository returns a repository representation for the given Stash Project key and repository slug.
@@ -780,7 +1691,7 @@ func (client Client) GetRepository(
 ) (Repository, error) {
 	data, err := client.request(
 		"GET",
-		fmt.Sprintf(
+		pathf(
 			"/rest/api/1.0/projects/%s/repos/%s",
 			projectKey, repositorySlug,
 		),
@@ -811,7 +1722,7 @@ func (client Client) CreateBranchRestriction(
 	}
 
 	data, err := client.request(
-		"POST", fmt.Sprintf(
+		"POST", pathf(
 			"/rest/branch-permissions/1.0/projects/%s/repos/%s/restricted",
 			projectKey, repositorySlug,
 		),
@@ -835,7 +1746,7 @@ func (client Client) GetBranchRestrictions(
 	projectKey, repositorySlug string,
 ) (BranchRestrictions, error) {
 	data, err := client.request(
-		"GET", fmt.Sprintf(
+		"GET", pathf(
 			"/rest/branch-permissions/1.0/projects/%s/repos/%s/restricted",
 			projectKey, repositorySlug,
 		),
@@ -861,7 +1772,7 @@ func (client Client) DeleteBranchRestriction(
 ) error {
 	_, err := client.request(
 		"DELETE",
-		fmt.Sprintf(
+		pathf(
 			"/rest/branch-permissions/1.0/projects/%s/repos/%s/restricted/%d",
 			projectKey, repositorySlug, id,
 		),
@@ -877,44 +1788,49 @@ func (client Client) DeleteBranchRestriction(
 
 // GetPullRequests returns a list of pull requests for a project / slug.
 func (client Client) GetPullRequests(
-	projectKey, repositorySlug, state string,
+	projectKey, repositorySlug string,
+	state PullRequestState,
 ) ([]PullRequest, error) {
-	start := 0
-	pullRequests := make([]PullRequest, 0)
-	morePages := true
-	for morePages {
-		data, err := client.request(
-			"GET",
-			fmt.Sprintf(
-				"/rest/api/1.0/projects/%s/repos/%s/pull-requests?state=%s&start=%d&limit=%d",
-				projectKey,
-				repositorySlug,
-				state,
-				start,
-				stashPageLimit,
-			),
-			nil,
-			http.StatusOK,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		var response PullRequests
-		err = json.Unmarshal(data, &response)
-		if err != nil {
-			return nil, err
-		}
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[PullRequest], error) {
+			return client.getPullRequestsPage(
+				projectKey, repositorySlug, state, start, limit,
+			)
+		},
+	)
+}
 
-		for _, pr := range response.PullRequests {
-			pullRequests = append(pullRequests, pr)
-		}
+func (client Client) getPullRequestsPage(
+	projectKey, repositorySlug string,
+	state PullRequestState,
+	start, limit int,
+) (PullRequests, error) {
+	err := validatePullRequestState(state)
+	if err != nil {
+		return PullRequests{}, err
+	}
 
-		morePages = !response.IsLastPage
-		start = response.NextPageStart
+	var response PullRequests
+	err = client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/pull-requests?state=%s&start=%d&limit=%d",
+			projectKey,
+			repositorySlug,
+			rawPath(url.QueryEscape(string(state))),
+			start,
+			limit,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return PullRequests{}, err
 	}
 
-	return pullRequests, nil
+	return response, nil
 }
 
 // GetPullRequest returns a pull request for a project/slug with specified
@@ -924,7 +1840,7 @@ func (client Client) GetPullRequest(
 ) (PullRequest, error) {
 	data, err := client.request(
 		"GET",
-		fmt.Sprintf(
+		pathf(
 			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s",
 			projectKey, repositorySlug, identifier,
 		),
@@ -948,19 +1864,59 @@ func (client Client) GetPullRequest(
 func (client Client) CreateComment(
 	projectKey, repositorySlug, pullRequest, text string,
 ) (Comment, error) {
-	payload := CommentResource{
-		Text: text,
-	}
+	return client.createComment(
+		projectKey, repositorySlug, pullRequest,
+		CommentResource{Text: text},
+	)
+}
 
-	data, err := client.request(
-		"POST",
-		fmt.Sprintf(
+// CreateInlineComment creates a comment on the line of pull request diff.
+func (client Client) CreateInlineComment(
+	projectKey, repositorySlug, pullRequest, text string,
+	anchor CommentAnchor,
+) (Comment, error) {
+	return client.createComment(
+		projectKey, repositorySlug, pullRequest,
+		CommentResource{Text: text, Anchor: &anchor},
+	)
+}
+
+// ReplyComment creates a reply to the pull request comment with the given
+// ID. Reply is placed on the same line as the parent comment.
+func (client Client) ReplyComment(
+	projectKey, repositorySlug, pullRequest string,
+	parentID int,
+	text string,
+) (Comment, error) {
+	return client.createComment(
+		projectKey, repositorySlug, pullRequest,
+		CommentResource{Text: text, Parent: &CommentParent{ID: parentID}},
+	)
+}
+
+func (client Client) createComment(
+	projectKey, repositorySlug, pullRequest string,
+	payload CommentResource,
+) (Comment, error) {
+	return client.postComment(
+		pathf(
 			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/comments",
 			projectKey,
 			repositorySlug,
 			pullRequest,
 		),
 		payload,
+	)
+}
+
+func (client Client) postComment(
+	path string,
+	payload CommentResource,
+) (Comment, error) {
+	data, err := client.request(
+		"POST",
+		path,
+		payload,
 		http.StatusCreated,
 	)
 	if err != nil {
@@ -982,25 +1938,59 @@ func (client Client) CreatePullRequest(
 	fromRef, toRef PullRequestRef,
 	reviewers []string,
 ) (PullRequest, error) {
-	var users []Reviewer
-	for _, rev := range reviewers {
+	return client.CreatePullRequestWithOptions(CreatePullRequestOptions{
+		Title:       title,
+		Description: description,
+		FromRef:     fromRef,
+		ToRef:       toRef,
+		Reviewers:   reviewers,
+	})
+}
+
+// CreatePullRequestWithOptions creates a pull request described by the given
+// options.
+func (client Client) CreatePullRequestWithOptions(
+	options CreatePullRequestOptions,
+) (PullRequest, error) {
+	var users, participants []Reviewer
+	for _, rev := range options.Reviewers {
 		users = append(users, Reviewer{
 			User: User{Name: rev},
 		})
 	}
 
+	for _, participant := range options.Participants {
+		switch participant.Role {
+		case "", ParticipantRoleReviewer:
+			users = append(users, participant)
+		default:
+			participants = append(participants, participant)
+		}
+	}
+
+	if options.Draft {
+		err := client.requireVersion("draft pull requests", 8, 18)
+		if err != nil {
+			return PullRequest{}, err
+		}
+	}
+
 	payload := PullRequestResource{
-		Title:       title,
-		Description: description,
-		FromRef:     fromRef,
-		ToRef:       toRef,
-		Reviewers:   users,
+		Title:        options.Title,
+		Description:  options.Description,
+		FromRef:      options.FromRef,
+		ToRef:        options.ToRef,
+		Reviewers:    users,
+		Participants: participants,
+		Draft:        options.Draft,
+		Properties:   options.Properties,
 	}
 
 	data, err := client.request(
-		"POST", fmt.Sprintf(
+		"POST", pathf(
 			"/rest/api/1.0/projects/%s/repos/%s/pull-requests",
-			toRef.Repository.Project.Key, toRef.Repository.Slug,
+			options.ToRef.Repository.Project.Key,
+			options.ToRef.Repository.Slug,
 		),
 		payload,
 		http.StatusCreated,
@@ -1026,6 +2016,11 @@ func (client Client) getRequest(
 	method, url string,
 	payload interface{},
 ) (*http.Request, error) {
+	err := client.validateRequest(url)
+	if err != nil {
+		return nil, err
+	}
+
 	var buffer io.Reader
 	if payload != nil {
 		body, err := json.Marshal(payload)
@@ -1036,7 +2031,8 @@ func (client Client) getRequest(
 		buffer = bytes.NewBuffer(body)
 	}
 
-	request, err := http.NewRequest(
+	request, err := http.NewRequestWithContext(
+		client.requestContext(),
 		method,
 		client.getFullURL(url),
 		buffer,
@@ -1052,8 +2048,9 @@ func (client Client) getRequest(
 		request.Header.Set("Content-type", "application/json")
 	}
 
-	if client.userName != "" && client.password != "" {
-		request.SetBasicAuth(client.userName, client.password)
+	err = client.authenticate(request)
+	if err != nil {
+		return nil, err
 	}
 
 	return request, nil
@@ -1067,8 +2064,18 @@ func (client Client) request(
 		return nil, err
 	}
 
-	status, data, err := consumeResponse(request)
+	if client.skipDryRun(request) {
+		// decoding null leaves result untouched, so callers return zero
+		// values
+		return []byte("null"), nil
+	}
+
+	status, data, err := client.consumeResponse(request)
 	if err != nil {
+		if status >= 400 {
+			return nil, unexpectedStatus(status, data, err)
+		}
+
 		return nil, err
 	}
 
@@ -1078,10 +2085,216 @@ func (client Client) request(
 		}
 	}
 
-	return nil, errorResponse{
+	return nil, unexpectedStatus(status, data, nil)
+}
+
+// unexpectedStatus returns error carrying status code, so it can be checked
+// with predicates like IsNotFound, response body and optional error
+// describing what server replied.
+func unexpectedStatus(status int, data []byte, err error) error {
+	var errResponse stashError
+	json.Unmarshal(data, &errResponse)
+
+	return ResponseError{
 		StatusCode: status,
 		Reason:     stashUnexpectedStatus,
+		Body:       data,
+		Errors:     errResponse.Errors,
+		error:      err,
+	}
+}
+
+// ResponseBody returns body of the response which caused err, if it was
+// caused by unexpected server status.
+func ResponseBody(err error) []byte {
+	var response ResponseError
+	if errors.As(err, &response) {
+		return response.Body
+	}
+
+	return nil
+}
+
+// ResponseErrors returns errors reported by the server in the response which
+// caused err.
+func ResponseErrors(err error) []ErrorMessage {
+	var response ResponseError
+	if errors.As(err, &response) {
+		return response.Errors
+	}
+
+	return nil
+}
+
+// ResponseVetoes returns reasons the server gave for rejecting operation
+// which caused err.
+func ResponseVetoes(err error) []Veto {
+	var vetoes []Veto
+	for _, message := range ResponseErrors(err) {
+		vetoes = append(vetoes, message.Vetoes...)
+	}
+
+	return vetoes
+}
+
+// requestJSON works like request, but decodes response body into result
+// while reading it, so large listings are never buffered as a whole.
+func (client Client) requestJSON(
+	method, url string,
+	payload interface{},
+	result interface{},
+	statuses ...int,
+) error {
+	return client.requestDecode(
+		method, url, payload,
+		jsonDecoder(result),
+		statuses...,
+	)
+}
+
+// jsonDecoder returns decode function for sendDecode which decodes response
+// body into result.
+func jsonDecoder(result interface{}) func(response *http.Response) error {
+	return func(response *http.Response) error {
+		err := json.NewDecoder(response.Body).Decode(result)
+		if err != nil {
+			return responseContext(response).Format(
+				err,
+				"decode response body",
+			)
+		}
+
+		return nil
+	}
+}
+
+// requestStream works like request, but copies response body to writer
+// while reading it.
+func (client Client) requestStream(
+	method, url string,
+	payload interface{},
+	writer io.Writer,
+	statuses ...int,
+) error {
+	request, err := client.getRequest(method, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return client.sendStream(request, writer, statuses...)
+}
+
+// sendStream is requestStream for already built request, e.g. with
+// additional headers.
+func (client Client) sendStream(
+	request *http.Request,
+	writer io.Writer,
+	statuses ...int,
+) error {
+	return client.sendDecode(
+		request,
+		func(response *http.Response) error {
+			_, err := io.Copy(writer, response.Body)
+			if err != nil {
+				return responseContext(response).Format(
+					err,
+					"copy response body",
+				)
+			}
+
+			return nil
+		},
+		statuses...,
+	)
+}
+
+// responseContext describes response in errors returned by decode functions
+// of requestDecode.
+func responseContext(response *http.Response) *karma.Context {
+	if response.Request == nil {
+		return karma.Describe("status", response.StatusCode)
+	}
+
+	return karma.Describe("url", redactURL(response.Request.URL.String()))
+}
+
+// requestDecode sends request and passes the response with one of expected
+// statuses to decode, which reads its body.
+func (client Client) requestDecode(
+	method, url string,
+	payload interface{},
+	decode func(response *http.Response) error,
+	statuses ...int,
+) error {
+	request, err := client.getRequest(method, url, payload)
+	if err != nil {
+		return err
+	}
+
+	return client.sendDecode(request, decode, statuses...)
+}
+
+// sendDecode is requestDecode for already built request, e.g. with
+// additional headers.
+func (client Client) sendDecode(
+	request *http.Request,
+	decode func(response *http.Response) error,
+	statuses ...int,
+) error {
+	if client.skipDryRun(request) {
+		return nil
 	}
+
+	response, err := client.sendOpen(request, statuses...)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	// errors are returned as is, so callers can return typed ones, see
+	// responseContext
+	return decode(response)
+}
+
+// sendOpen sends request and returns response with one of expected
+// statuses, which body must be closed by caller.
+func (client Client) sendOpen(
+	request *http.Request,
+	statuses ...int,
+) (*http.Response, error) {
+	context := karma.Describe("url", redactURL(request.URL.String()))
+
+	response, err := client.do(request)
+	if err != nil {
+		return nil, context.Reason(err)
+	}
+
+	for _, expectedStatus := range statuses {
+		if response.StatusCode == expectedStatus {
+			return response, nil
+		}
+	}
+
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, context.Format(
+			err,
+			"read response body",
+		)
+	}
+
+	if response.StatusCode >= 400 {
+		return nil, unexpectedStatus(
+			response.StatusCode,
+			data,
+			parseResponseError(context, response.StatusCode, data),
+		)
+	}
+
+	return nil, unexpectedStatus(response.StatusCode, data, nil)
 }
 
 // UpdatePullRequest update a pull request.
@@ -1119,7 +2332,7 @@ func (client Client) UpdatePullRequest(
 
 	data, err := client.request(
 		"PUT",
-		fmt.Sprintf(
+		pathf(
 			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s",
 			projectKey,
 			repositorySlug,
@@ -1149,7 +2362,7 @@ func (client Client) MergePullRequest(
 ) (*MergeResult, error) {
 	request, err := client.getRequest(
 		"POST",
-		fmt.Sprintf(
+		pathf(
 			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/merge",
 			projectKey,
 			repositorySlug,
@@ -1163,45 +2376,136 @@ func (client Client) MergePullRequest(
 		return nil, err
 	}
 
-	response, err := httpClient.Do(request)
+	if client.skipDryRun(request) {
+		return &MergeResult{}, nil
+	}
+
+	response, err := client.do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusConflict:
+		// ok
+	default:
+		return nil, karma.Format(
+			err,
+			"unexpected status code: %d",
+			response.StatusCode,
+		)
+	}
+
+	var status MergeResult
+
+	err = json.NewDecoder(response.Body).Decode(&status)
+	if err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// DeclinePullRequest declines pull request of the given version.
+func (client Client) DeclinePullRequest(
+	projectKey, repositorySlug, identifier string,
+	version int,
+) (PullRequest, error) {
+	var response PullRequest
+	err := client.requestJSON(
+		"POST",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/decline?version=%d",
+			projectKey, repositorySlug, identifier, version,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return PullRequest{}, err
+	}
+
+	return response, nil
+}
+
+// ReopenPullRequest reopens declined pull request of the given version, the
+// returned pull request has a new version.
+func (client Client) ReopenPullRequest(
+	projectKey, repositorySlug, identifier string,
+	version int,
+) (PullRequest, error) {
+	var response PullRequest
+	err := client.requestJSON(
+		"POST",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/pull-requests/%s/reopen?version=%d",
+			projectKey, repositorySlug, identifier, version,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
 	if err != nil {
-		return nil, err
-	}
-
-	switch response.StatusCode {
-	case http.StatusOK, http.StatusConflict:
-		// ok
-	default:
-		return nil, karma.Format(
-			err,
-			"unexpected status code: %d",
-			response.StatusCode,
-		)
+		return PullRequest{}, err
 	}
 
-	var status MergeResult
+	return response, nil
+}
 
-	err = json.NewDecoder(response.Body).Decode(&status)
+// CreateBranch creates branch pointing to startPoint, which is either
+// commit hash or name of existing branch or tag.
+func (client Client) CreateBranch(
+	projectKey, repositorySlug, branchName, startPoint string,
+) (Branch, error) {
+	var response Branch
+	err := client.requestJSON(
+		"POST",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/branches",
+			projectKey, repositorySlug,
+		),
+		struct {
+			Name       string `json:"name"`
+			StartPoint string `json:"startPoint"`
+		}{branchName, startPoint},
+		&response,
+		http.StatusOK,
+	)
 	if err != nil {
-		return nil, err
+		return Branch{}, err
 	}
 
-	return &status, nil
+	return response, nil
 }
 
 func (client Client) DeleteBranch(
 	projectKey, repositorySlug, branchName string,
+) error {
+	return client.DeleteBranchWithOptions(
+		projectKey, repositorySlug, branchName, DeleteBranchOptions{},
+	)
+}
+
+// DeleteBranchWithOptions deletes branch like DeleteBranch does, but can
+// only check that it can be deleted, see DeleteBranchOptions. Use
+// ResponseVetoes to find out why branch can't be deleted.
+func (client Client) DeleteBranchWithOptions(
+	projectKey, repositorySlug, branchName string,
+	options DeleteBranchOptions,
 ) error {
 	_, err := client.request(
 		"DELETE",
-		fmt.Sprintf(
+		pathf(
 			"/rest/branch-utils/1.0/projects/%s/repos/%s/branches",
 			projectKey, repositorySlug,
 		),
 		struct {
 			Name   string `json:"name"`
 			DryRun bool   `json:"dryRun"`
-		}{"refs/heads/" + branchName, false},
+		}{"refs/heads/" + branchName, options.DryRun},
 		http.StatusNoContent,
 	)
 	if err != nil {
@@ -1211,16 +2515,20 @@ func (client Client) DeleteBranch(
 	return nil
 }
 
+// GetRawFile returns contents of the file at the branch.
+//
+// Deprecated: use GetRawFileAt, which uses REST API and accepts any ref.
 func (client Client) GetRawFile(
 	repositoryProjectKey, repositorySlug, filePath, branch string,
 ) ([]byte, error) {
 	return client.request(
 		"GET",
-		fmt.Sprintf(
+		pathf(
 			"/projects/%s/repos/%s/browse/%s?at=%s&raw",
 			strings.ToLower(repositoryProjectKey),
 			strings.ToLower(repositorySlug),
-			filePath, branch,
+			rawPath(escapeFilePath(filePath)),
+			rawPath(url.QueryEscape(branch)),
 		),
 		nil,
 		http.StatusOK,
@@ -1232,7 +2540,7 @@ func (client Client) GetCommit(
 	projectKey, repositorySlug, commitHash string,
 ) (Commit, error) {
 	data, err := client.request(
-		"GET", fmt.Sprintf(
+		"GET", pathf(
 			"/rest/api/1.0/projects/%s/repos/%s/commits/%s",
 			projectKey, repositorySlug, commitHash,
 		),
@@ -1252,28 +2560,24 @@ func (client Client) GetCommit(
 func (client Client) GetCommits(
 	projectKey, repositorySlug, commitSinceHash, commitUntilHash string,
 ) (Commits, error) {
-	data, err := client.request(
+	var commits Commits
+	err := client.requestJSON(
 		"GET",
-		fmt.Sprintf(
+		pathf(
 			"/rest/api/1.0/projects/%s/repos/%s/commits?since=%s&until=%s&limit=1000",
 			projectKey,
 			repositorySlug,
-			commitSinceHash,
-			commitUntilHash,
+			rawPath(url.QueryEscape(commitSinceHash)),
+			rawPath(url.QueryEscape(commitUntilHash)),
 		),
 		nil,
+		&commits,
 		http.StatusOK,
 	)
 	if err != nil {
 		return Commits{}, err
 	}
 
-	var commits Commits
-	err = json.Unmarshal(data, &commits)
-	if err != nil {
-		return Commits{}, err
-	}
-
 	return commits, nil
 }
 
@@ -1285,11 +2589,13 @@ func (client Client) GetUPMToken() (string, error) {
 		return "", err
 	}
 
-	response, err := httpClient.Do(request)
+	response, err := client.do(request)
 	if err != nil {
 		return "", err
 	}
 
+	defer response.Body.Close()
+
 	if response.StatusCode != http.StatusOK {
 		return "", fmt.Errorf("unexpected status code: %v", response.StatusCode)
 	}
@@ -1301,7 +2607,7 @@ func (client Client) UninstallAddon(
 	token, key string,
 ) error {
 	request, err := client.getRequest(
-		"DELETE", fmt.Sprintf(
+		"DELETE", pathf(
 			"/rest/plugins/1.0/%s-key",
 			key,
 		),
@@ -1311,11 +2617,17 @@ func (client Client) UninstallAddon(
 		return err
 	}
 
-	response, err := httpClient.Do(request)
+	if client.skipDryRun(request) {
+		return nil
+	}
+
+	response, err := client.do(request)
 	if err != nil {
 		return err
 	}
 
+	defer response.Body.Close()
+
 	if response.StatusCode != http.StatusNoContent &&
 		response.StatusCode != http.StatusNotFound {
 		return fmt.Errorf("unexpected status code: %v", response.StatusCode)
@@ -1327,17 +2639,22 @@ func (client Client) UninstallAddon(
 func (client Client) InstallAddon(
 	token, path string,
 ) (string, error) {
-	buffer := bytes.NewBuffer(nil)
-	writer := multipart.NewWriter(buffer)
+	if token == "" {
+		return "", ValidationError{Reason: "UPM token is empty"}
+	}
 
-	err := writer.WriteField("url", "")
+	err := client.validateRequest("/rest/plugins/1.0/")
 	if err != nil {
 		return "", err
 	}
 
-	part, err := writer.CreateFormFile("plugin", path)
-	if err != nil {
-		return "", err
+	if client.dryRun != nil {
+		client.dryRun.record(
+			"POST",
+			redactURL(client.getFullURL("/rest/plugins/1.0/?token="+url.QueryEscape(token))),
+			nil,
+		)
+		return "", nil
 	}
 
 	file, err := os.Open(path)
@@ -1345,36 +2662,71 @@ func (client Client) InstallAddon(
 		return "", err
 	}
 
-	_, err = io.Copy(part, file)
-	if err != nil {
-		return "", err
-	}
+	defer file.Close()
 
-	err = writer.Close()
-	if err != nil {
-		return "", err
-	}
+	// plugin bundles might be pretty large, so body is streamed via pipe
+	// instead of being buffered in memory
+	reader, pipe := io.Pipe()
+	writer := multipart.NewWriter(pipe)
+
+	go func() {
+		err := writer.WriteField("url", "")
+		if err != nil {
+			pipe.CloseWithError(err)
+			return
+		}
+
+		part, err := writer.CreateFormFile("plugin", path)
+		if err != nil {
+			pipe.CloseWithError(err)
+			return
+		}
+
+		_, err = io.Copy(part, file)
+		if err != nil {
+			pipe.CloseWithError(err)
+			return
+		}
 
-	request, err := http.NewRequest(
+		pipe.CloseWithError(writer.Close())
+	}()
+
+	request, err := http.NewRequestWithContext(
+		client.requestContext(),
 		"POST",
-		client.getFullURL("/rest/plugins/1.0/?token="+token),
-		buffer,
+		client.getFullURL("/rest/plugins/1.0/?token="+url.QueryEscape(token)),
+		reader,
 	)
 	if err != nil {
+		reader.CloseWithError(err)
 		return "", err
 	}
 
 	request.Header.Set("Content-Type", writer.FormDataContentType())
 
-	if client.userName != "" && client.password != "" {
-		request.SetBasicAuth(client.userName, client.password)
+	err = client.authenticate(request)
+	if err != nil {
+		reader.CloseWithError(err)
+		return "", err
 	}
 
-	response, err := httpClient.Do(request)
+	response, err := client.do(request)
 	if err != nil {
 		return "", err
 	}
 
+	// body is closed right after reading, since waiting for installation
+	// makes more requests, which might be limited by
+	// WithMaxConcurrentRequests
+	data, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return "", karma.Format(
+			err,
+			"unable to read response body",
+		)
+	}
+
 	if response.StatusCode != http.StatusOK &&
 		response.StatusCode != http.StatusAccepted {
 		return "", fmt.Errorf("unexpected status code: %v", response.StatusCode)
@@ -1386,18 +2738,9 @@ func (client Client) InstallAddon(
 		}
 	}
 
-	defer response.Body.Close()
-	data, err := ioutil.ReadAll(response.Body)
-	if err != nil {
-		return "", karma.Format(
-			err,
-			"unable to read response body",
-		)
-	}
-
 	err = json.Unmarshal(data, &descriptor)
 	if err != nil {
-		return "", karma.Describe("response", string(data)).Format(
+		return "", karma.Describe("response", redactBody(data)).Format(
 			err,
 			"unable to unmarshal JSON response",
 		)
@@ -1426,9 +2769,13 @@ func (client Client) waitAddonInstallation(task string) (string, error) {
 			return "", err
 		}
 
-		statusCode, body, err := consumeResponse(request)
+		statusCode, body, err := client.consumeResponse(request)
 		if statusCode == 404 {
-			time.Sleep(interval)
+			err = client.sleep(interval)
+			if err != nil {
+				return "", err
+			}
+
 			continue
 		}
 
@@ -1452,19 +2799,23 @@ func (client Client) waitAddonInstallation(task string) (string, error) {
 		if err != nil {
 			return "", karma.
 				Describe("request", "GET "+trim(task)).
-				Describe("response", string(body)).
+				Describe("response", redactBody(body)).
 				Format(err, "unable to decode response body")
 		}
 
 		if !status.Done {
-			time.Sleep(interval)
+			err = client.sleep(interval)
+			if err != nil {
+				return "", err
+			}
+
 			continue
 		}
 
 		if status.Error.Code != "" {
 			return "", fmt.Errorf(
 				"bitbucket returned an error: %s; full response: %s",
-				status.Error.Code, string(body),
+				status.Error.Code, redactBody(body),
 			)
 		}
 
@@ -1477,9 +2828,13 @@ func (client Client) waitAddonInstallation(task string) (string, error) {
 			Key string
 		}
 
-		statusCode, body, err = consumeResponse(request)
+		statusCode, body, err = client.consumeResponse(request)
 		if statusCode == 404 {
-			time.Sleep(interval)
+			err = client.sleep(interval)
+			if err != nil {
+				return "", err
+			}
+
 			continue
 		}
 
@@ -1491,7 +2846,7 @@ func (client Client) waitAddonInstallation(task string) (string, error) {
 		if err != nil {
 			return "", karma.
 				Describe("request", "GET "+trim(status.Links.Result)).
-				Describe("response", string(body)).
+				Describe("response", redactBody(body)).
 				Format(err, "unable to decode response body")
 		}
 
@@ -1502,14 +2857,14 @@ func (client Client) waitAddonInstallation(task string) (string, error) {
 func (client Client) SetAddonLicense(addon, license string) error {
 	request, err := client.getRequest(
 		"GET",
-		"/rest/plugins/1.0/"+addon+"-key/license",
+		pathf("/rest/plugins/1.0/%s-key/license", addon),
 		nil,
 	)
 	if err != nil {
 		return err
 	}
 
-	_, body, err := consumeResponse(request)
+	_, body, err := client.consumeResponse(request)
 	if err != nil {
 		return karma.Format(
 			err,
@@ -1532,7 +2887,7 @@ func (client Client) SetAddonLicense(addon, license string) error {
 
 	request, err = client.getRequest(
 		"PUT",
-		"/rest/plugins/1.0/"+addon+"-key/license",
+		pathf("/rest/plugins/1.0/%s-key/license", addon),
 		map[string]string{
 			"rawLicense": license,
 		},
@@ -1544,17 +2899,23 @@ func (client Client) SetAddonLicense(addon, license string) error {
 	request.Header.Del("Accept")
 	request.Header.Set("Content-Type", "application/vnd.atl.plugins+json")
 
-	response, err := httpClient.Do(request)
+	if client.skipDryRun(request) {
+		return nil
+	}
+
+	response, err := client.do(request)
 	if err != nil {
 		return err
 	}
 
+	defer response.Body.Close()
+
 	if response.StatusCode != 200 {
 		reply, _ := ioutil.ReadAll(response.Body)
 
 		return karma.
 			Describe("status_code", response.StatusCode).
-			Describe("response", string(reply)).
+			Describe("response", redactBody(reply)).
 			Format(
 				err,
 				"unable to set license",
@@ -1564,9 +2925,29 @@ func (client Client) SetAddonLicense(addon, license string) error {
 	return nil
 }
 
+// DeleteAddonLicense removes license of the given addon, e.g. to clear
+// expired trial license.
+func (client Client) DeleteAddonLicense(addon string) error {
+	_, err := client.request(
+		"DELETE",
+		pathf("/rest/plugins/1.0/%s-key/license", addon),
+		nil,
+		http.StatusOK,
+		http.StatusNoContent,
+	)
+	if err != nil {
+		return karma.Format(
+			err,
+			"unable to delete license",
+		)
+	}
+
+	return nil
+}
+
 func (client Client) GetAddon(upmToken, key string) (Addon, error) {
 	request, err := client.getRequest(
-		"GET", fmt.Sprintf(
+		"GET", pathf(
 			"/rest/plugins/1.0/%s-key",
 			key,
 		),
@@ -1578,7 +2959,7 @@ func (client Client) GetAddon(upmToken, key string) (Addon, error) {
 
 	// request.Header.Set("Accept", "application/json")
 
-	_, body, err := consumeResponse(request)
+	_, body, err := client.consumeResponse(request)
 	if err != nil {
 		return Addon{}, karma.Format(
 			err,
@@ -1595,6 +2976,49 @@ func (client Client) GetAddon(upmToken, key string) (Addon, error) {
 	return result, nil
 }
 
+// GetAvailableAddons returns addons from Marketplace which can be installed
+// on this instance, optionally filtered by query.
+func (client Client) GetAvailableAddons(query string) ([]AvailableAddon, error) {
+	offset := 0
+	addons := []AvailableAddon{}
+	for {
+		payload := url.Values{}
+		if query != "" {
+			payload.Set("q", query)
+		}
+		payload.Set("offset", fmt.Sprint(offset))
+
+		data, err := client.request(
+			"GET",
+			"/rest/plugins/1.0/available?"+payload.Encode(),
+			nil,
+			http.StatusOK,
+		)
+		if err != nil {
+			return nil, karma.Format(
+				err,
+				"unable to get available addons",
+			)
+		}
+
+		var response availableAddons
+		err = json.Unmarshal(data, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		addons = append(addons, response.Plugins...)
+
+		if response.Links.Next == "" || len(response.Plugins) == 0 {
+			break
+		}
+
+		offset += len(response.Plugins)
+	}
+
+	return addons, nil
+}
+
 func (client Client) DisableAddon(
 	token string, addon Addon,
 ) error {
@@ -1613,7 +3037,7 @@ func (client Client) putAddon(
 	token string, addon Addon,
 ) error {
 	request, err := client.getRequest(
-		"PUT", fmt.Sprintf(
+		"PUT", pathf(
 			"/rest/plugins/1.0/%s-key",
 			addon.Key,
 		),
@@ -1628,11 +3052,17 @@ func (client Client) putAddon(
 		"application/vnd.atl.plugins.plugin+json",
 	)
 
-	response, err := httpClient.Do(request)
+	if client.skipDryRun(request) {
+		return nil
+	}
+
+	response, err := client.do(request)
 	if err != nil {
 		return err
 	}
 
+	defer response.Body.Close()
+
 	if response.StatusCode != http.StatusOK {
 		return fmt.Errorf("unexpected status code: %v", response.StatusCode)
 	}
@@ -1654,34 +3084,57 @@ func HasRepository(
 	return Repository{}, false
 }
 
+// IsRepositoryExists reports whether err is caused by the repository being
+// already created.
+//
+// Deprecated: use errors.Is(err, ErrConflict).
 func IsRepositoryExists(err error) bool {
-	if err == nil {
-		return false
-	}
-	if response, ok := err.(errorResponse); ok {
-		return response.StatusCode == http.StatusConflict
-	}
-	return false
+	return IsConflict(err)
 }
 
+// IsRepositoryNotFound reports whether err is caused by the repository being
+// absent.
+//
+// Deprecated: use errors.Is(err, ErrNotFound).
 func IsRepositoryNotFound(err error) bool {
-	if err == nil {
-		return false
-	}
-	if response, ok := err.(errorResponse); ok {
-		return response.StatusCode == http.StatusNotFound
-	}
-	return false
+	return IsNotFound(err)
+}
+
+// IsNotFound is a shorthand for errors.Is(err, ErrNotFound).
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsUnauthorized is a shorthand for errors.Is(err, ErrUnauthorized).
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbidden is a shorthand for errors.Is(err, ErrForbidden).
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsConflict is a shorthand for errors.Is(err, ErrConflict).
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimited is a shorthand for errors.Is(err, ErrRateLimited).
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
 }
 
-func consumeResponse(req *http.Request) (int, []byte, error) {
-	context := karma.Describe("url", req.URL.String())
+func (client Client) consumeResponse(req *http.Request) (int, []byte, error) {
+	context := karma.Describe("url", redactURL(req.URL.String()))
 
-	response, err := httpClient.Do(req)
+	response, err := client.do(req)
 	if err != nil {
 		return 0, nil, context.Reason(err)
 	}
 
+	defer response.Body.Close()
+
 	data, err := ioutil.ReadAll(response.Body)
 	if err != nil {
 		return response.StatusCode, nil, context.Format(
@@ -1690,40 +3143,49 @@ func consumeResponse(req *http.Request) (int, []byte, error) {
 		)
 	}
 
-	defer response.Body.Close()
-
 	if response.StatusCode >= 400 {
-		var errResponse stashError
-		if err := json.Unmarshal(data, &errResponse); err == nil {
-			var messages []string
-			for _, e := range errResponse.Errors {
-				messages = append(messages, e.Message)
-			}
-			return response.StatusCode, data, context.Reason(
-				errors.New(strings.Join(messages, " ")),
-			)
-		} else {
-			return response.StatusCode, nil, context.Format(
-				err,
-				"status code: %d; unable to read error body as JSON:",
-				response.StatusCode,
-			)
-		}
+		return response.StatusCode, data, parseResponseError(
+			context, response.StatusCode, data,
+		)
 	}
 
 	return response.StatusCode, data, nil
 }
 
+func parseResponseError(
+	context *karma.Context, status int, data []byte,
+) error {
+	var errResponse stashError
+	err := json.Unmarshal(data, &errResponse)
+	if err != nil {
+		return context.Format(
+			err,
+			"status code: %d; unable to read error body as JSON:",
+			status,
+		)
+	}
+
+	var messages []string
+	for _, e := range errResponse.Errors {
+		messages = append(messages, e.Message)
+	}
+
+	return context.Reason(
+		errors.New(strings.Join(messages, " ")),
+	)
+}
+
 func (client Client) GrantRepositoryUserPermission(
-	projectKey, repositorySlug, user, permission string,
+	projectKey, repositorySlug, user string,
+	permission Permission,
 ) error {
 	payload := url.Values{}
 	payload.Set("name", user)
-	payload.Set("permission", permission)
+	payload.Set("permission", string(permission))
 	_, err := client.request(
-		"PUT", fmt.Sprintf(
+		"PUT", pathf(
 			"/rest/api/1.0/projects/%s/repos/%s/permissions/users?%s",
-			projectKey, repositorySlug, payload.Encode(),
+			projectKey, repositorySlug, rawPath(payload.Encode()),
 		),
 		nil,
 		http.StatusNoContent,
@@ -1736,9 +3198,37 @@ func (client Client) RevokeRepositoryUserPermission(
 	projectKey, repositorySlug, user string,
 ) error {
 	_, err := client.request(
-		"DELETE", fmt.Sprintf(
+		"DELETE", pathf(
 			"/rest/api/1.0/projects/%s/repos/%s/permissions/users?name=%s",
-			projectKey, repositorySlug, user,
+			projectKey, repositorySlug, rawPath(url.QueryEscape(user)),
+		),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+// WatchRepository subscribes the user client is authenticated as to
+// notifications about changes in the repository.
+func (client Client) WatchRepository(projectKey, repositorySlug string) error {
+	_, err := client.request(
+		"POST", pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/watch",
+			projectKey, repositorySlug,
+		),
+		nil,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+func (client Client) UnwatchRepository(projectKey, repositorySlug string) error {
+	_, err := client.request(
+		"DELETE", pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/watch",
+			projectKey, repositorySlug,
 		),
 		nil,
 		http.StatusNoContent,
@@ -1754,7 +3244,7 @@ func (client Client) ForkRepository(
 ) (*Repository, error) {
 	response, err := client.request(
 		"POST",
-		fmt.Sprintf(
+		pathf(
 			"/rest/api/1.0/projects/%s/repos/%s",
 			projectKey,
 			repositorySlug,
@@ -1780,12 +3270,148 @@ func (client Client) ForkRepository(
 	return &fork, nil
 }
 
+// IsFork reports whether repository is forked from another one.
+func (repo Repository) IsFork() bool {
+	return repo.Origin != nil
+}
+
+// GetForkAncestors returns repositories the given one is forked from,
+// starting with its origin and ending with the canonical repository. It's
+// empty if repository is not a fork.
+func (client Client) GetForkAncestors(
+	projectKey, repositorySlug string,
+) ([]Repository, error) {
+	repository, err := client.GetRepository(projectKey, repositorySlug)
+	if err != nil {
+		return nil, err
+	}
+
+	var ancestors []Repository
+	for repository.Origin != nil {
+		// origin of personal fork may be a fork too, which is only seen by
+		// getting origin itself
+		repository, err = client.GetRepository(
+			repository.Origin.Project.Key, repository.Origin.Slug,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		ancestors = append(ancestors, repository)
+	}
+
+	return ancestors, nil
+}
+
 // SshUrl extracts the SSH-based URL from the repository metadata.
 func (repo Repository) SshUrl() string {
+	return repo.CloneURL("ssh")
+}
+
+// HttpUrl extracts the HTTP(S)-based URL from the repository metadata.
+func (repo Repository) HttpUrl() string {
+	return repo.CloneURL("http")
+}
+
+// CloneURL extracts the clone URL for the given scheme from the repository
+// metadata. Scheme names are normalized, so "http" and "https" are
+// interchangeable, because different Bitbucket versions name them
+// differently.
+func (repo Repository) CloneURL(scheme string) string {
+	scheme = normalizeCloneScheme(scheme)
 	for _, clone := range repo.Links.Clones {
-		if clone.Name == "ssh" {
+		if normalizeCloneScheme(clone.Name) == scheme {
 			return clone.HREF
 		}
 	}
 	return ""
 }
+
+func normalizeCloneScheme(scheme string) string {
+	scheme = strings.ToLower(strings.TrimSpace(scheme))
+	switch scheme {
+	case "https":
+		return "http"
+	case "git+ssh", "ssh+git":
+		return "ssh"
+	default:
+		return scheme
+	}
+}
+
+// CreatedAt returns the pull request creation date.
+func (pr PullRequest) CreatedAt() time.Time {
+	return fromMillis(pr.CreatedDate)
+}
+
+// UpdatedAt returns the date of the last pull request update.
+func (pr PullRequest) UpdatedAt() time.Time {
+	return fromMillis(pr.UpdatedDate)
+}
+
+// AuthoredAt returns the commit author date.
+func (commit Commit) AuthoredAt() time.Time {
+	return fromMillis(commit.AuthorTimestamp)
+}
+
+func fromMillis(millis int64) time.Time {
+	if millis == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(0, millis*int64(time.Millisecond))
+}
+
+// WebURL returns URL of the repository in Bitbucket UI.
+func (repo Repository) WebURL() string {
+	return repo.Links.self()
+}
+
+// BranchWebURL returns URL of the given branch of the repository in
+// Bitbucket UI.
+func (repo Repository) BranchWebURL(branch Branch) string {
+	base := repo.webBase()
+	if base == "" {
+		return ""
+	}
+
+	return base + "/browse?at=" + url.QueryEscape(branch.ID)
+}
+
+// CommitWebURL returns URL of the given commit of the repository in
+// Bitbucket UI.
+func (repo Repository) CommitWebURL(commit Commit) string {
+	base := repo.webBase()
+	if base == "" {
+		return ""
+	}
+
+	return base + "/commits/" + commit.ID
+}
+
+// webBase returns repository URL in UI without trailing page, e.g. without
+// /browse, which self link points to.
+func (repo Repository) webBase() string {
+	return strings.TrimSuffix(
+		strings.TrimRight(repo.WebURL(), "/"),
+		"/browse",
+	)
+}
+
+// WebURL returns URL of the project in Bitbucket UI.
+func (project Project) WebURL() string {
+	return project.SelfURL
+}
+
+// WebURL returns URL of the pull request in Bitbucket UI.
+func (pr PullRequest) WebURL() string {
+	return pr.Links.self()
+}
+
+func (links Links) self() string {
+	if len(links.Self) == 0 {
+		return ""
+	}
+
+	return links.Self[0].HREF
+}
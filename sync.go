@@ -0,0 +1,133 @@
+package stash
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type (
+	// RefSyncStatus tells how refs of the fork differ from its origin.
+	// Ahead, Diverged and Orphaned refs are not synchronized automatically.
+	RefSyncStatus struct {
+		// Available is false if fork can't be synchronized, e.g. its origin
+		// is deleted.
+		Available    bool          `json:"available"`
+		Enabled      bool          `json:"enabled"`
+		LastSync     int64         `json:"lastSync"`
+		AheadRefs    []RejectedRef `json:"aheadRefs"`
+		DivergedRefs []RejectedRef `json:"divergedRefs"`
+		OrphanedRefs []RejectedRef `json:"orphanedRefs"`
+	}
+
+	// RejectedRef is a ref of the fork which can't be synchronized with
+	// origin by fast-forward.
+	RejectedRef struct {
+		ID           string       `json:"id"`
+		DisplayID    string       `json:"displayId"`
+		Type         string       `json:"type"`
+		State        RefSyncState `json:"state"`
+		Tag          bool         `json:"tag"`
+		LatestCommit string       `json:"latestCommit"`
+	}
+)
+
+// GetRefSyncStatus returns synchronization status of the fork.
+func (client Client) GetRefSyncStatus(
+	projectKey, repositorySlug string,
+) (RefSyncStatus, error) {
+	var response RefSyncStatus
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/sync/1.0/projects/%s/repos/%s",
+			projectKey, repositorySlug,
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return RefSyncStatus{}, err
+	}
+
+	return response, nil
+}
+
+// SetRefSyncEnabled enables or disables automatic synchronization of the
+// fork with its origin.
+func (client Client) SetRefSyncEnabled(
+	projectKey, repositorySlug string,
+	enabled bool,
+) (RefSyncStatus, error) {
+	var response RefSyncStatus
+	err := client.requestJSON(
+		"POST",
+		pathf(
+			"/rest/sync/1.0/projects/%s/repos/%s",
+			projectKey, repositorySlug,
+		),
+		struct {
+			Enabled bool `json:"enabled"`
+		}{enabled},
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return RefSyncStatus{}, err
+	}
+
+	return response, nil
+}
+
+// SynchronizeRef resolves the ref which can't be synchronized automatically.
+// RefSyncActionMerge merges origin into the ref using commit message, which
+// is optional, and RefSyncActionDiscard resets the ref to origin or deletes
+// it if it's orphaned. The returned ref is nil if ref is now synchronized.
+func (client Client) SynchronizeRef(
+	projectKey, repositorySlug, refID string,
+	action RefSyncAction,
+	commitMessage string,
+) (*RejectedRef, error) {
+	type context struct {
+		CommitMessage string `json:"commitMessage"`
+	}
+
+	payload := struct {
+		RefID   string        `json:"refId"`
+		Action  RefSyncAction `json:"action"`
+		Context *context      `json:"context,omitempty"`
+	}{
+		RefID:  refID,
+		Action: action,
+	}
+
+	if commitMessage != "" {
+		payload.Context = &context{CommitMessage: commitMessage}
+	}
+
+	data, err := client.request(
+		"POST",
+		pathf(
+			"/rest/sync/1.0/projects/%s/repos/%s/synchronize",
+			projectKey, repositorySlug,
+		),
+		payload,
+		http.StatusOK,
+		http.StatusNoContent,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	var response *RejectedRef
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
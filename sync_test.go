@@ -0,0 +1,76 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRefSync(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/sync/1.0/projects/~BOB/repos/widge":
+			fmt.Fprint(w, `{"available": true, "enabled": true, "lastSync": 1500000000000,
+				"divergedRefs": [{"id": "refs/heads/master", "displayId": "master", "state": "DIVERGED"}]}`)
+		case "POST /rest/sync/1.0/projects/~BOB/repos/widge":
+			var payload map[string]bool
+			json.NewDecoder(r.Body).Decode(&payload)
+			if payload["enabled"] {
+				t.Fatalf("Want sync disabled but got %v\n", payload)
+			}
+			fmt.Fprint(w, `{"available": true, "enabled": false}`)
+		case "POST /rest/sync/1.0/projects/~BOB/repos/widge/synchronize":
+			var payload struct {
+				RefID   string        `json:"refId"`
+				Action  RefSyncAction `json:"action"`
+				Context *struct {
+					CommitMessage string `json:"commitMessage"`
+				} `json:"context"`
+			}
+			json.NewDecoder(r.Body).Decode(&payload)
+
+			switch payload.Action {
+			case RefSyncActionMerge:
+				if payload.Context == nil || payload.Context.CommitMessage != "Sync" {
+					t.Fatalf("Want merge with message but got %+v\n", payload)
+				}
+				w.WriteHeader(http.StatusNoContent)
+			case RefSyncActionDiscard:
+				if payload.Context != nil {
+					t.Fatalf("Want discard without context but got %+v\n", payload)
+				}
+				fmt.Fprint(w, `{"id": "refs/heads/master", "state": "AHEAD"}`)
+			}
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	status, err := stashClient.GetRefSyncStatus("~BOB", "widge")
+	if err != nil || len(status.DivergedRefs) != 1 ||
+		status.DivergedRefs[0].State != RefSyncStateDiverged {
+		t.Fatalf("Want diverged master but got %+v, %v\n", status, err)
+	}
+
+	status, err = stashClient.SetRefSyncEnabled("~BOB", "widge", false)
+	if err != nil || status.Enabled {
+		t.Fatalf("Want sync disabled but got %+v, %v\n", status, err)
+	}
+
+	ref, err := stashClient.SynchronizeRef("~BOB", "widge", "refs/heads/master", RefSyncActionMerge, "Sync")
+	if err != nil || ref != nil {
+		t.Fatalf("Want ref synchronized but got %+v, %v\n", ref, err)
+	}
+
+	ref, err = stashClient.SynchronizeRef("~BOB", "widge", "refs/heads/master", RefSyncActionDiscard, "")
+	if err != nil || ref == nil || ref.State != RefSyncStateAhead {
+		t.Fatalf("Want ref still ahead but got %+v, %v\n", ref, err)
+	}
+}
@@ -1,6 +1,7 @@
 package stash
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -92,3 +93,43 @@ func TestGetTagsAnonymous(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateAndDeleteTag(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "POST /rest/api/1.0/projects/PRJ/repos/widge/tags":
+			var payload map[string]string
+			json.NewDecoder(r.Body).Decode(&payload)
+			if payload["name"] != "release/1.0" || payload["startPoint"] != "master" {
+				t.Fatalf("Want release/1.0 at master but got %v\n", payload)
+			}
+			if _, ok := payload["message"]; ok {
+				t.Fatalf("Want lightweight tag without message but got %v\n", payload)
+			}
+			fmt.Fprint(w, `{"id": "refs/tags/release/1.0", "displayId": "release/1.0", "latestCommit": "abc"}`)
+		case "DELETE /rest/git/1.0/projects/PRJ/repos/widge/tags/release/1.0":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	repo := NewClient("u", "p", url).Project("PRJ").Repo("widge")
+
+	tag, err := repo.CreateTag("release/1.0", "master", "")
+	if err != nil || tag.LatestCommit != "abc" {
+		t.Fatalf("Want tag at abc but got %+v, %v\n", tag, err)
+	}
+
+	err = repo.DeleteTag("release/1.0")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	err = repo.DeleteTag("")
+	if _, ok := err.(ValidationError); !ok {
+		t.Fatalf("Want validation error for empty name but got %v\n", err)
+	}
+}
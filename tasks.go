@@ -0,0 +1,177 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// DefaultTask is a task added to every new pull request which source and
+// target branches match the matchers, requires Bitbucket 7.2 or newer.
+type DefaultTask struct {
+	ID            int        `json:"id,omitempty"`
+	Description   string     `json:"description"`
+	SourceMatcher RefMatcher `json:"sourceMatcher"`
+	TargetMatcher RefMatcher `json:"targetMatcher"`
+}
+
+// GetProjectDefaultTasks returns default tasks of the project. Requires
+// Bitbucket 7.2 or newer.
+func (client Client) GetProjectDefaultTasks(
+	projectKey string,
+) ([]DefaultTask, error) {
+	return client.listDefaultTasks(projectDefaultTasks(projectKey))
+}
+
+// GetRepositoryDefaultTasks returns default tasks of the repository,
+// including ones inherited from the project. Requires Bitbucket 7.2 or
+// newer.
+func (client Client) GetRepositoryDefaultTasks(
+	projectKey, repositorySlug string,
+) ([]DefaultTask, error) {
+	return client.listDefaultTasks(
+		repositoryDefaultTasks(projectKey, repositorySlug),
+	)
+}
+
+// CreateProjectDefaultTask creates default task of the project, ID of the
+// task is ignored. Requires Bitbucket 7.2 or newer.
+func (client Client) CreateProjectDefaultTask(
+	projectKey string,
+	task DefaultTask,
+) (DefaultTask, error) {
+	return client.saveDefaultTask(
+		"POST", projectDefaultTasks(projectKey), task,
+	)
+}
+
+// CreateRepositoryDefaultTask creates default task of the repository, ID of
+// the task is ignored. Requires Bitbucket 7.2 or newer.
+func (client Client) CreateRepositoryDefaultTask(
+	projectKey, repositorySlug string,
+	task DefaultTask,
+) (DefaultTask, error) {
+	return client.saveDefaultTask(
+		"POST", repositoryDefaultTasks(projectKey, repositorySlug), task,
+	)
+}
+
+// UpdateProjectDefaultTask replaces project default task with the given ID.
+// Requires Bitbucket 7.2 or newer.
+func (client Client) UpdateProjectDefaultTask(
+	projectKey string,
+	task DefaultTask,
+) (DefaultTask, error) {
+	return client.saveDefaultTask(
+		"PUT",
+		fmt.Sprintf("%s/%d", projectDefaultTasks(projectKey), task.ID),
+		task,
+	)
+}
+
+// UpdateRepositoryDefaultTask replaces repository default task with the
+// given ID. Requires Bitbucket 7.2 or newer.
+func (client Client) UpdateRepositoryDefaultTask(
+	projectKey, repositorySlug string,
+	task DefaultTask,
+) (DefaultTask, error) {
+	return client.saveDefaultTask(
+		"PUT",
+		fmt.Sprintf(
+			"%s/%d", repositoryDefaultTasks(projectKey, repositorySlug), task.ID,
+		),
+		task,
+	)
+}
+
+// DeleteProjectDefaultTask deletes project default task with the given ID.
+// Requires Bitbucket 7.2 or newer.
+func (client Client) DeleteProjectDefaultTask(projectKey string, id int) error {
+	return client.deleteDefaultTask(
+		fmt.Sprintf("%s/%d", projectDefaultTasks(projectKey), id),
+	)
+}
+
+// DeleteRepositoryDefaultTask deletes repository default task with the
+// given ID. Requires Bitbucket 7.2 or newer.
+func (client Client) DeleteRepositoryDefaultTask(
+	projectKey, repositorySlug string,
+	id int,
+) error {
+	return client.deleteDefaultTask(
+		fmt.Sprintf(
+			"%s/%d", repositoryDefaultTasks(projectKey, repositorySlug), id,
+		),
+	)
+}
+
+func projectDefaultTasks(projectKey string) string {
+	return pathf("/rest/default-tasks/1.0/projects/%s/tasks", projectKey)
+}
+
+func repositoryDefaultTasks(projectKey, repositorySlug string) string {
+	return pathf(
+		"/rest/default-tasks/1.0/projects/%s/repos/%s/tasks",
+		projectKey, repositorySlug,
+	)
+}
+
+func (client Client) listDefaultTasks(path string) ([]DefaultTask, error) {
+	err := client.requireVersion("default tasks", 7, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[DefaultTask], error) {
+			var response Paged[DefaultTask]
+			err := client.requestJSON(
+				"GET",
+				fmt.Sprintf("%s?start=%d&limit=%d", path, start, limit),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+func (client Client) saveDefaultTask(
+	method, path string,
+	task DefaultTask,
+) (DefaultTask, error) {
+	err := client.requireVersion("default tasks", 7, 2)
+	if err != nil {
+		return DefaultTask{}, err
+	}
+
+	if method == "POST" {
+		task.ID = 0
+	}
+
+	var response DefaultTask
+	err = client.requestJSON(
+		method, path,
+		task,
+		&response,
+		http.StatusOK,
+		http.StatusCreated,
+	)
+	if err != nil {
+		return DefaultTask{}, err
+	}
+
+	return response, nil
+}
+
+func (client Client) deleteDefaultTask(path string) error {
+	err := client.requireVersion("default tasks", 7, 2)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.request("DELETE", path, nil, http.StatusNoContent)
+
+	return err
+}
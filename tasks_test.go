@@ -0,0 +1,79 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestDefaultTasks(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/1.0/application-properties" {
+			fmt.Fprint(w, `{"version": "8.9.0"}`)
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/default-tasks/1.0/projects/PRJ/repos/widge/tasks":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{
+				"id": 1,
+				"description": "Update changelog",
+				"sourceMatcher": {"id": "ANY_REF_MATCHER_ID", "type": {"id": "ANY_REF"}},
+				"targetMatcher": {"id": "refs/heads/master", "type": {"id": "BRANCH"}}
+			}]}`)
+		case "POST /rest/default-tasks/1.0/projects/PRJ/tasks":
+			var task DefaultTask
+			if err := json.NewDecoder(r.Body).Decode(&task); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if task.ID != 0 || task.TargetMatcher.Type.ID != RefMatcherModelCategory {
+				t.Fatalf("Unexpected task %+v\n", task)
+			}
+			task.ID = 2
+			json.NewEncoder(w).Encode(task)
+		case "PUT /rest/default-tasks/1.0/projects/PRJ/tasks/2":
+			w.Write([]byte(`{"id": 2, "description": "Bump version"}`))
+		case "DELETE /rest/default-tasks/1.0/projects/PRJ/repos/widge/tasks/1":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	tasks, err := stashClient.GetRepositoryDefaultTasks("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("GetRepositoryDefaultTasks() not expecting an error, but received: %v\n", err)
+	}
+	if len(tasks) != 1 || tasks[0].SourceMatcher.Type.ID != RefMatcherAnyRef {
+		t.Fatalf("Unexpected tasks %+v\n", tasks)
+	}
+
+	task, err := stashClient.CreateProjectDefaultTask("PRJ", DefaultTask{
+		ID:            5,
+		Description:   "Bump version",
+		SourceMatcher: RefMatcher{ID: "ANY_REF_MATCHER_ID", Type: RefMatcherType{ID: RefMatcherAnyRef}},
+		TargetMatcher: RefMatcher{ID: "RELEASE", Type: RefMatcherType{ID: RefMatcherModelCategory}},
+	})
+	if err != nil {
+		t.Fatalf("CreateProjectDefaultTask() not expecting an error, but received: %v\n", err)
+	}
+	if task.ID != 2 {
+		t.Fatalf("Want created task with ID 2 but got %+v\n", task)
+	}
+
+	_, err = stashClient.UpdateProjectDefaultTask("PRJ", task)
+	if err != nil {
+		t.Fatalf("UpdateProjectDefaultTask() not expecting an error, but received: %v\n", err)
+	}
+
+	err = stashClient.DeleteRepositoryDefaultTask("PRJ", "widge", 1)
+	if err != nil {
+		t.Fatalf("DeleteRepositoryDefaultTask() not expecting an error, but received: %v\n", err)
+	}
+}
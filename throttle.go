@@ -0,0 +1,144 @@
+package stash
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// defaultThrottleRetries is how many times request rejected by rate
+	// limiter is resent unless WithThrottleRetries is given, so clients
+	// wait for rate limiter by default.
+	defaultThrottleRetries = 5
+
+	// defaultMaxThrottleDelay limits wait for rate limiter unless
+	// WithRetryPolicy is given, which MaxBackoff is used then.
+	defaultMaxThrottleDelay = 30 * time.Second
+)
+
+type (
+	// ThrottleStats counts requests rejected by server rate limiter with
+	// 429, see Client.ThrottleStats.
+	ThrottleStats struct {
+		// Throttled is amount of 429 responses.
+		Throttled int64
+
+		// Exhausted is amount of requests which still got 429 after all
+		// retries, their errors are returned to callers.
+		Exhausted int64
+
+		// Waited is total time spent waiting for rate limiter.
+		Waited time.Duration
+	}
+
+	throttleCounters struct {
+		throttled atomic.Int64
+		exhausted atomic.Int64
+		waited    atomic.Int64
+	}
+)
+
+// WithThrottleRetries limits how many times request rejected by server rate
+// limiter is resent after waiting as server suggests. Clients retry 5 times
+// by default, waiting up to MaxBackoff of the retry policy, or 30s, each
+// time. Zero disables waiting, so 429 is returned as error right away.
+func WithThrottleRetries(retries int) Option {
+	return func(client *Client) {
+		client.throttleRetries = retries
+	}
+}
+
+// ThrottleStats returns counters of rate limited requests made by the
+// client and its copies, e.g. made with WithContext.
+func (client Client) ThrottleStats() ThrottleStats {
+	if client.throttle == nil {
+		return ThrottleStats{}
+	}
+
+	return ThrottleStats{
+		Throttled: client.throttle.throttled.Load(),
+		Exhausted: client.throttle.exhausted.Load(),
+		Waited:    time.Duration(client.throttle.waited.Load()),
+	}
+}
+
+// sendThrottled sends the request, waiting and resending it while server
+// rejects it with 429. Any request is resent, since rate limiter rejects
+// them before processing.
+func (client Client) sendThrottled(request *http.Request) (*http.Response, error) {
+	for retry := 0; ; retry++ {
+		response, err := client.sendAuthenticated(request)
+		if err != nil || response.StatusCode != http.StatusTooManyRequests {
+			return response, err
+		}
+
+		if client.throttle != nil {
+			client.throttle.throttled.Add(1)
+		}
+
+		if retry >= client.throttleRetries ||
+			(request.Body != nil && request.GetBody == nil) {
+			if client.throttle != nil {
+				client.throttle.exhausted.Add(1)
+			}
+
+			return response, nil
+		}
+
+		delay := min(throttleDelay(response), client.maxThrottleDelay())
+
+		io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+
+		started := time.Now()
+		err = client.sleep(delay)
+		if client.throttle != nil {
+			client.throttle.waited.Add(int64(time.Since(started)))
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		request = request.Clone(request.Context())
+		if request.GetBody != nil {
+			request.Body, err = request.GetBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func (client Client) maxThrottleDelay() time.Duration {
+	if client.retries != nil {
+		return client.retries.MaxBackoff
+	}
+
+	return defaultMaxThrottleDelay
+}
+
+// throttleDelay returns how long to wait before resending request rejected
+// by rate limiter. Bitbucket sends Retry-After along with token bucket
+// settings, which tell how soon the next token is available.
+func throttleDelay(response *http.Response) time.Duration {
+	if delay, ok := retryAfter(response); ok {
+		return delay
+	}
+
+	interval, err := strconv.ParseFloat(
+		response.Header.Get("X-RateLimit-Interval-Seconds"), 64,
+	)
+	if err == nil {
+		fillRate, err := strconv.ParseFloat(
+			response.Header.Get("X-RateLimit-FillRate"), 64,
+		)
+		if err == nil && fillRate > 0 && interval > 0 {
+			return time.Duration(interval / fillRate * float64(time.Second))
+		}
+	}
+
+	return time.Second
+}
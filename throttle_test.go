@@ -0,0 +1,74 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestThrottling(t *testing.T) {
+	var requests int
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= 2 || r.Method == "POST" {
+			w.Header().Set("X-RateLimit-Interval-Seconds", "1")
+			w.Header().Set("X-RateLimit-FillRate", "100")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"slug": "widge", "project": {"key": "PRJ"}}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	_, err := stashClient.GetRepository("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("Want no error after throttling but got %s\n", err)
+	}
+
+	stats := stashClient.ThrottleStats()
+	if stats.Throttled != 2 || stats.Exhausted != 0 || stats.Waited < 20*time.Millisecond {
+		t.Fatalf("Want 2 throttled requests waited for 20ms but got %+v\n", stats)
+	}
+
+	requests = 0
+	_, err = NewClient("u", "p", url, WithThrottleRetries(1)).
+		CreateBranch("PRJ", "widge", "feature", "master")
+	if err == nil || requests != 2 {
+		t.Fatalf("Want 429 error after a single retry but got %d requests, %v\n", requests, err)
+	}
+}
+
+func TestThrottleDelayCapped(t *testing.T) {
+	var requests int
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `{"slug": "widge", "project": {"key": "PRJ"}}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient(
+		"u", "p", url,
+		WithRetryPolicy(RetryPolicy{MaxBackoff: 10 * time.Millisecond}),
+	)
+
+	_, err := stashClient.GetRepository("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("Want no error after throttling but got %s\n", err)
+	}
+
+	if waited := stashClient.ThrottleStats().Waited; waited > time.Second {
+		t.Fatalf("Want wait limited by MaxBackoff but waited %s\n", waited)
+	}
+}
@@ -0,0 +1,25 @@
+package stash
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPullRequestTimestamps(t *testing.T) {
+	pr := PullRequest{CreatedDate: 1435759062673, UpdatedDate: 1435759063000}
+
+	want := time.Date(2015, 7, 1, 13, 57, 42, 673000000, time.UTC)
+	if !pr.CreatedAt().Equal(want) {
+		t.Fatalf("Want %v but got %v\n", want, pr.CreatedAt())
+	}
+	if !pr.UpdatedAt().After(pr.CreatedAt()) {
+		t.Fatalf("Want updated date after created date but got %v\n", pr.UpdatedAt())
+	}
+}
+
+func TestCommitTimestampMissing(t *testing.T) {
+	commit := Commit{}
+	if !commit.AuthoredAt().IsZero() {
+		t.Fatalf("Want zero time but got %v\n", commit.AuthoredAt())
+	}
+}
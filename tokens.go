@@ -0,0 +1,159 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type (
+	// AccessToken is an HTTP access token bound to a project or repository,
+	// requires Bitbucket 8.0 or newer.
+	AccessToken struct {
+		ID          string       `json:"id"`
+		Name        string       `json:"name"`
+		Permissions []Permission `json:"permissions"`
+
+		// CreatedDate, ExpiryDate and LastAuthenticated are in
+		// milliseconds, ExpiryDate is zero for tokens which never expire.
+		CreatedDate       int64 `json:"createdDate"`
+		ExpiryDate        int64 `json:"expiryDate,omitempty"`
+		LastAuthenticated int64 `json:"lastAuthenticated,omitempty"`
+
+		// Token is the secret itself, server returns it only once when
+		// token is created.
+		Token string `json:"token,omitempty"`
+
+		// User is a service user created for the token.
+		User *User `json:"user,omitempty"`
+	}
+
+	AccessTokenOptions struct {
+		Name string `json:"name"`
+
+		// Permissions are either project or repository ones, e.g.
+		// PermissionRepoRead, depending on token scope.
+		Permissions []Permission `json:"permissions"`
+
+		// ExpiryDays is a token lifetime, token never expires if it's zero
+		// and server allows it.
+		ExpiryDays int `json:"expiryDays,omitempty"`
+	}
+)
+
+func (client Client) GetProjectAccessTokens(
+	projectKey string,
+) ([]AccessToken, error) {
+	return client.listAccessTokens(projectAccessTokens(projectKey))
+}
+
+func (client Client) GetRepositoryAccessTokens(
+	projectKey, repositorySlug string,
+) ([]AccessToken, error) {
+	return client.listAccessTokens(
+		repositoryAccessTokens(projectKey, repositorySlug),
+	)
+}
+
+// CreateProjectAccessToken creates token granting access to the project,
+// secret is returned in Token field of the result.
+func (client Client) CreateProjectAccessToken(
+	projectKey string,
+	options AccessTokenOptions,
+) (AccessToken, error) {
+	return client.createAccessToken(projectAccessTokens(projectKey), options)
+}
+
+// CreateRepositoryAccessToken creates token granting access to the
+// repository, secret is returned in Token field of the result.
+func (client Client) CreateRepositoryAccessToken(
+	projectKey, repositorySlug string,
+	options AccessTokenOptions,
+) (AccessToken, error) {
+	return client.createAccessToken(
+		repositoryAccessTokens(projectKey, repositorySlug), options,
+	)
+}
+
+func (client Client) RevokeProjectAccessToken(projectKey, tokenID string) error {
+	return client.revokeAccessToken(
+		projectAccessTokens(projectKey) + "/" + url.PathEscape(tokenID),
+	)
+}
+
+func (client Client) RevokeRepositoryAccessToken(
+	projectKey, repositorySlug, tokenID string,
+) error {
+	return client.revokeAccessToken(
+		repositoryAccessTokens(projectKey, repositorySlug) +
+			"/" + url.PathEscape(tokenID),
+	)
+}
+
+func projectAccessTokens(projectKey string) string {
+	return pathf("/rest/access-tokens/1.0/projects/%s", projectKey)
+}
+
+func repositoryAccessTokens(projectKey, repositorySlug string) string {
+	return pathf(
+		"/rest/access-tokens/1.0/projects/%s/repos/%s",
+		projectKey, repositorySlug,
+	)
+}
+
+func (client Client) listAccessTokens(path string) ([]AccessToken, error) {
+	err := client.requireVersion("resource access tokens", 8, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[AccessToken], error) {
+			var response Paged[AccessToken]
+			err := client.requestJSON(
+				"GET",
+				fmt.Sprintf("%s?start=%d&limit=%d", path, start, limit),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+func (client Client) createAccessToken(
+	path string,
+	options AccessTokenOptions,
+) (AccessToken, error) {
+	err := client.requireVersion("resource access tokens", 8, 0)
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	var response AccessToken
+	err = client.requestJSON(
+		"PUT", path,
+		options,
+		&response,
+		http.StatusOK,
+		http.StatusCreated,
+	)
+	if err != nil {
+		return AccessToken{}, err
+	}
+
+	return response, nil
+}
+
+func (client Client) revokeAccessToken(path string) error {
+	err := client.requireVersion("resource access tokens", 8, 0)
+	if err != nil {
+		return err
+	}
+
+	_, err = client.request("DELETE", path, nil, http.StatusNoContent)
+
+	return err
+}
@@ -0,0 +1,66 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestRepositoryAccessTokens(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/1.0/application-properties" {
+			fmt.Fprint(w, `{"version": "8.9.0"}`)
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/access-tokens/1.0/projects/PRJ/repos/widge":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"id": "123", "name": "ci", "permissions": ["REPO_READ"], "createdDate": 1700000000000}]}`)
+		case "PUT /rest/access-tokens/1.0/projects/PRJ/repos/widge":
+			var options AccessTokenOptions
+			if err := json.NewDecoder(r.Body).Decode(&options); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if options.Name != "deploy" || options.ExpiryDays != 90 ||
+				len(options.Permissions) != 1 || options.Permissions[0] != PermissionRepoWrite {
+				t.Fatalf("Unexpected options %+v\n", options)
+			}
+			fmt.Fprint(w, `{"id": "456", "name": "deploy", "permissions": ["REPO_WRITE"], "token": "NjU0..."}`)
+		case "DELETE /rest/access-tokens/1.0/projects/PRJ/repos/widge/123":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	tokens, err := stashClient.GetRepositoryAccessTokens("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("GetRepositoryAccessTokens() not expecting an error, but received: %v\n", err)
+	}
+	if len(tokens) != 1 || tokens[0].ID != "123" || tokens[0].Token != "" {
+		t.Fatalf("Unexpected tokens %+v\n", tokens)
+	}
+
+	token, err := stashClient.CreateRepositoryAccessToken("PRJ", "widge", AccessTokenOptions{
+		Name:        "deploy",
+		Permissions: []Permission{PermissionRepoWrite},
+		ExpiryDays:  90,
+	})
+	if err != nil {
+		t.Fatalf("CreateRepositoryAccessToken() not expecting an error, but received: %v\n", err)
+	}
+	if token.Token != "NjU0..." {
+		t.Fatalf("Want token secret but got %+v\n", token)
+	}
+
+	err = stashClient.RevokeRepositoryAccessToken("PRJ", "widge", "123")
+	if err != nil {
+		t.Fatalf("RevokeRepositoryAccessToken() not expecting an error, but received: %v\n", err)
+	}
+}
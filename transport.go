@@ -0,0 +1,114 @@
+package stash
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+)
+
+// TransportOptions tunes connection reuse of the client, zero fields keep
+// net/http defaults.
+type TransportOptions struct {
+	// MaxIdleConns limits idle connections kept across all hosts.
+	MaxIdleConns int
+
+	// MaxIdleConnsPerHost limits idle connections kept to the server,
+	// net/http keeps only 2 by default, which is too few for concurrent
+	// jobs.
+	MaxIdleConnsPerHost int
+
+	// MaxConnsPerHost limits all connections to the server, including ones
+	// in use.
+	MaxConnsPerHost int
+
+	// IdleConnTimeout is how long idle connection is kept open.
+	IdleConnTimeout time.Duration
+
+	// HTTP2 enables HTTP/2 over TLS, which net/http doesn't try on its own
+	// when TLS is configured explicitly.
+	HTTP2 bool
+}
+
+// defaultTimeout limits requests of clients created without
+// WithHTTPClient.
+const defaultTimeout = 10 * time.Second
+
+// WithHTTPClient makes client send requests with the given http.Client, e.g.
+// to use proxy, custom TLS settings or timeout. Authentication is still
+// added by the client.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.http = httpClient
+	}
+}
+
+// WithTransport tunes connection pool of the client with given options.
+func WithTransport(options TransportOptions) Option {
+	return func(client *Client) {
+		client.configureTransport(func(transport *http.Transport) {
+			transport.MaxIdleConns = options.MaxIdleConns
+			transport.MaxIdleConnsPerHost = options.MaxIdleConnsPerHost
+			transport.MaxConnsPerHost = options.MaxConnsPerHost
+			transport.IdleConnTimeout = options.IdleConnTimeout
+			transport.ForceAttemptHTTP2 = options.HTTP2
+		})
+	}
+}
+
+// WithInsecureTLS disables verification of server certificates, e.g. for
+// test instances with self-signed certificates. It's not meant for
+// production use, since it makes connections open to interception.
+func WithInsecureTLS() Option {
+	return func(client *Client) {
+		client.configureTransport(func(transport *http.Transport) {
+			if transport.TLSClientConfig == nil {
+				transport.TLSClientConfig = &tls.Config{}
+			}
+
+			transport.TLSClientConfig.InsecureSkipVerify = true
+		})
+	}
+}
+
+// configureTransport changes copy of the client transport, so http.Client
+// given with WithHTTPClient is not modified. Transport given with
+// WithHTTPClient is kept if it's *http.Transport.
+func (client *Client) configureTransport(configure func(*http.Transport)) {
+	httpClient := *client.httpClient()
+
+	base, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		base = newTransport()
+	}
+
+	transport := base.Clone()
+	configure(transport)
+
+	httpClient.Transport = transport
+	client.http = &httpClient
+}
+
+// newHTTPClient returns http.Client owned by a single client, so clients
+// don't share connection pool.
+func newHTTPClient() *http.Client {
+	return &http.Client{
+		Timeout:   defaultTimeout,
+		Transport: newTransport(),
+	}
+}
+
+// newTransport returns transport which verifies server certificates, see
+// WithInsecureTLS.
+func newTransport() *http.Transport {
+	return http.DefaultTransport.(*http.Transport).Clone()
+}
+
+// httpClient returns http.Client to send requests with, zero Client which
+// is not created by NewClient uses http.DefaultClient.
+func (client Client) httpClient() *http.Client {
+	if client.http != nil {
+		return client.http
+	}
+
+	return http.DefaultClient
+}
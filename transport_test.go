@@ -0,0 +1,133 @@
+package stash
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWithTransportReusesConnections(t *testing.T) {
+	var connections int32
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	testServer.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&connections, 1)
+		}
+	}
+	testServer.Start()
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithTransport(TransportOptions{
+		MaxIdleConnsPerHost: 8,
+	}))
+
+	var branches []string
+	for i := 0; i < 8; i++ {
+		branches = append(branches, fmt.Sprintf("issue/%d", i))
+	}
+
+	for round := 0; round < 3; round++ {
+		results := stashClient.DeleteBranches(
+			"PROJ", "slug", branches,
+			DeleteBranchesOptions{Concurrency: 8},
+		)
+		for _, result := range results {
+			if result.Err != nil {
+				t.Fatalf("Not expecting error: %v\n", result.Err)
+			}
+		}
+	}
+
+	if atomic.LoadInt32(&connections) > 8 {
+		t.Fatalf("Want at most 8 connections but got %d\n", connections)
+	}
+}
+
+func TestWithTransportHTTP2(t *testing.T) {
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			t.Fatalf("Want HTTP/2 but found %s\n", r.Proto)
+		}
+		fmt.Fprint(w, `{"version": "8.19.0"}`)
+	}))
+	testServer.EnableHTTP2 = true
+	testServer.StartTLS()
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithInsecureTLS(), WithTransport(TransportOptions{
+		HTTP2: true,
+	}))
+
+	if _, err := stashClient.GetServerVersion(); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+}
+
+// roundTripFunc records requests sent through the injected http.Client.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (fn roundTripFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return fn(request)
+}
+
+func TestWithHTTPClient(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version": "8.19.0"}`)
+	}))
+	defer testServer.Close()
+
+	var sent []string
+	injected := &http.Client{
+		Transport: roundTripFunc(func(request *http.Request) (*http.Response, error) {
+			sent = append(sent, request.URL.Path)
+			return http.DefaultTransport.RoundTrip(request)
+		}),
+	}
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url, WithHTTPClient(injected))
+	otherClient := NewClient("u", "p", url)
+
+	if _, err := stashClient.GetServerVersion(); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if _, err := otherClient.GetServerVersion(); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if len(sent) != 1 || sent[0] != "/rest/api/1.0/application-properties" {
+		t.Fatalf("Want single request through injected client but got %v\n", sent)
+	}
+
+	if stashClient.(Client).http == otherClient.(Client).http {
+		t.Fatalf("Want clients to own their http.Client\n")
+	}
+}
+
+func TestTLSVerification(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"version": "8.19.0"}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+
+	_, err := NewClient("u", "p", url).GetServerVersion()
+	if err == nil {
+		t.Fatalf("Want certificate error for self-signed server but got none\n")
+	}
+
+	_, err = NewClient("u", "p", url, WithInsecureTLS()).GetServerVersion()
+	if err != nil {
+		t.Fatalf("Want no error with WithInsecureTLS but got %v\n", err)
+	}
+}
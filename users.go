@@ -0,0 +1,236 @@
+package stash
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetUsers returns users which name, display name or email starts with
+// filter, all users are returned if it's empty.
+func (client Client) GetUsers(filter string) ([]User, error) {
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[User], error) {
+			query := url.Values{}
+			if filter != "" {
+				query.Set("filter", filter)
+			}
+			query.Set("start", strconv.Itoa(start))
+			query.Set("limit", strconv.Itoa(limit))
+
+			var response Paged[User]
+			err := client.requestJSON(
+				"GET",
+				"/rest/api/1.0/users?"+query.Encode(),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+// GetUser returns the user with the given slug, which is usually the same as
+// user name.
+func (client Client) GetUser(userSlug string) (User, error) {
+	var response User
+	err := client.requestJSON(
+		"GET",
+		pathf("/rest/api/1.0/users/%s", userSlug),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return User{}, err
+	}
+
+	return response, nil
+}
+
+// GetCurrentUser returns the user client is authenticated as. Server tells
+// user name in X-AUSERNAME header for any authenticated request, which is
+// looked up then, so it works for every kind of authentication.
+func (client Client) GetCurrentUser() (User, error) {
+	var name string
+	err := client.requestDecode(
+		"GET", "/rest/api/1.0/application-properties",
+		nil,
+		func(response *http.Response) error {
+			name = response.Header.Get("X-AUSERNAME")
+			return nil
+		},
+		http.StatusOK,
+	)
+	if err != nil {
+		return User{}, err
+	}
+
+	if name == "" {
+		return User{}, ValidationError{Reason: "request is not authenticated"}
+	}
+
+	users, err := client.GetUsers(name)
+	if err != nil {
+		return User{}, err
+	}
+
+	for _, user := range users {
+		if user.Name == name {
+			return user, nil
+		}
+	}
+
+	return User{}, fmt.Errorf("user %q: %w", name, ErrNotFound)
+}
+
+// UserUpdate changes profile of the current user, empty fields are left as
+// is.
+type UserUpdate struct {
+	DisplayName string `json:"displayName,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+// UpdateCurrentUser changes display name and email of the user client is
+// authenticated as and returns updated user.
+func (client Client) UpdateCurrentUser(update UserUpdate) (User, error) {
+	payload := struct {
+		Name string `json:"name,omitempty"`
+		UserUpdate
+	}{client.userName, update}
+
+	var response User
+	err := client.requestJSON(
+		"PUT", "/rest/api/1.0/users",
+		payload,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return User{}, err
+	}
+
+	return response, nil
+}
+
+// GetUserSettings returns settings stored for the user, values are
+// decoded from JSON as is.
+func (client Client) GetUserSettings(userSlug string) (map[string]any, error) {
+	var response map[string]any
+	err := client.requestJSON(
+		"GET",
+		pathf("/rest/api/1.0/users/%s/settings", userSlug),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if response == nil {
+		response = map[string]any{}
+	}
+
+	return response, nil
+}
+
+// UpdateUserSettings stores given settings for the user, settings which are
+// not given are left as is. Values must be encodable to JSON.
+func (client Client) UpdateUserSettings(
+	userSlug string,
+	settings map[string]any,
+) error {
+	_, err := client.request(
+		"POST",
+		pathf("/rest/api/1.0/users/%s/settings", userSlug),
+		settings,
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+// SetUserPassword replaces password of the user, requires administrator
+// permission. Server doesn't support forcing user to change password on
+// next login, so new password should be handed over securely.
+func (client Client) SetUserPassword(name, password string) error {
+	_, err := client.request(
+		"PUT", "/rest/api/1.0/admin/users/credentials",
+		struct {
+			Name            string `json:"name"`
+			Password        string `json:"password"`
+			PasswordConfirm string `json:"passwordConfirm"`
+		}{name, password, password},
+		http.StatusNoContent,
+	)
+
+	return err
+}
+
+// UpdateUser changes display name and email of the user, requires
+// administrator permission. Empty fields are left as is.
+func (client Client) UpdateUser(name string, update UserUpdate) (User, error) {
+	payload := struct {
+		Name string `json:"name"`
+		UserUpdate
+	}{name, update}
+
+	var response User
+	err := client.requestJSON(
+		"PUT", "/rest/api/1.0/admin/users",
+		payload,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return User{}, err
+	}
+
+	return response, nil
+}
+
+// RenameUser changes name of the user, keeping its permissions and history,
+// and returns renamed user. Users from external directories can't be
+// renamed.
+func (client Client) RenameUser(name, newName string) (User, error) {
+	var response User
+	err := client.requestJSON(
+		"POST", "/rest/api/1.0/admin/users/rename",
+		struct {
+			Name    string `json:"name"`
+			NewName string `json:"newName"`
+		}{name, newName},
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return User{}, err
+	}
+
+	return response, nil
+}
+
+// DeleteUser deletes the user and returns it, permissions granted to the
+// user are revoked.
+func (client Client) DeleteUser(name string) (User, error) {
+	if name == "" {
+		return User{}, ValidationError{Reason: "user name is empty"}
+	}
+
+	var response User
+	err := client.requestJSON(
+		"DELETE", "/rest/api/1.0/admin/users?name="+url.QueryEscape(name),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return User{}, err
+	}
+
+	return response, nil
+}
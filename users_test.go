@@ -0,0 +1,204 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestUserSettings(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/users/bob/settings" {
+			t.Fatalf("Want /rest/api/1.0/users/bob/settings but found %s\n", r.URL.Path)
+		}
+
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `{"bot.notify": true, "bot.channel": "#dev"}`)
+		case "POST":
+			var payload map[string]any
+			if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+				t.Fatalf("Unexpected error: %v\n", err)
+			}
+			if payload["bot.notify"] != false {
+				t.Fatalf("Unexpected payload %v\n", payload)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	settings, err := stashClient.GetUserSettings("bob")
+	if err != nil {
+		t.Fatalf("GetUserSettings() not expecting an error, but received: %v\n", err)
+	}
+	if settings["bot.notify"] != true || settings["bot.channel"] != "#dev" {
+		t.Fatalf("Unexpected settings %v\n", settings)
+	}
+
+	err = stashClient.UpdateUserSettings("bob", map[string]any{"bot.notify": false})
+	if err != nil {
+		t.Fatalf("UpdateUserSettings() not expecting an error, but received: %v\n", err)
+	}
+}
+
+func TestSetUserPassword(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/rest/api/1.0/admin/users/credentials" {
+			t.Fatalf("Want PUT /rest/api/1.0/admin/users/credentials but found %s %s\n", r.Method, r.URL.Path)
+		}
+
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if payload["name"] != "bob" || payload["password"] != "n3w" || payload["passwordConfirm"] != "n3w" {
+			t.Fatalf("Unexpected payload %v\n", payload)
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errors": [{"message": "Password is too weak."}], "password": "n3w"}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	err := stashClient.SetUserPassword("bob", "n3w")
+	if err == nil {
+		t.Fatalf("SetUserPassword() expecting an error\n")
+	}
+	if strings.Contains(err.Error(), "n3w") {
+		t.Fatalf("Want password redacted from error but got %s\n", err)
+	}
+}
+
+func TestUpdateCurrentUser(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/rest/api/1.0/users" {
+			t.Fatalf("Want PUT /rest/api/1.0/users but found %s %s\n", r.Method, r.URL.Path)
+		}
+
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("Unexpected error: %v\n", err)
+		}
+		if fmt.Sprint(payload) != "map[displayName:Bob Smith email:bob@example.com name:bob]" {
+			t.Fatalf("Unexpected payload %v\n", payload)
+		}
+
+		fmt.Fprint(w, `{"name": "bob", "displayName": "Bob Smith", "emailAddress": "bob@example.com"}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("bob", "p", url)
+
+	user, err := stashClient.UpdateCurrentUser(UserUpdate{
+		DisplayName: "Bob Smith",
+		Email:       "bob@example.com",
+	})
+	if err != nil {
+		t.Fatalf("UpdateCurrentUser() not expecting an error, but received: %v\n", err)
+	}
+	if user.DisplayName != "Bob Smith" || user.Email != "bob@example.com" {
+		t.Fatalf("Unexpected user %+v\n", user)
+	}
+}
+
+func TestAdminUsers(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		if r.Body != nil {
+			json.NewDecoder(r.Body).Decode(&payload)
+		}
+
+		switch r.Method + " " + r.URL.Path {
+		case "PUT /rest/api/1.0/admin/users":
+			if fmt.Sprint(payload) != "map[displayName:Bob name:bob]" {
+				t.Fatalf("Unexpected payload %v\n", payload)
+			}
+			fmt.Fprint(w, `{"name": "bob", "displayName": "Bob"}`)
+		case "POST /rest/api/1.0/admin/users/rename":
+			if payload["name"] != "bob" || payload["newName"] != "robert" {
+				t.Fatalf("Unexpected payload %v\n", payload)
+			}
+			fmt.Fprint(w, `{"name": "robert", "slug": "robert"}`)
+		case "DELETE /rest/api/1.0/admin/users":
+			if r.URL.Query().Get("name") != "robert" {
+				t.Fatalf("Want robert deleted but got %s\n", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"name": "robert"}`)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("admin", "p", url)
+
+	user, err := stashClient.UpdateUser("bob", UserUpdate{DisplayName: "Bob"})
+	if err != nil || user.DisplayName != "Bob" {
+		t.Fatalf("Want updated user but got %+v, %v\n", user, err)
+	}
+
+	user, err = stashClient.RenameUser("bob", "robert")
+	if err != nil || user.Name != "robert" {
+		t.Fatalf("Want renamed user but got %+v, %v\n", user, err)
+	}
+
+	user, err = stashClient.DeleteUser("robert")
+	if err != nil || user.Name != "robert" {
+		t.Fatalf("Want deleted user but got %+v, %v\n", user, err)
+	}
+}
+
+func TestGetUsers(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/users":
+			if r.URL.Query().Get("filter") != "bo" {
+				t.Fatalf("Want users filtered by bo but got %s\n", r.URL.RawQuery)
+			}
+
+			switch r.URL.Query().Get("start") {
+			case "0":
+				fmt.Fprint(w, `{"isLastPage": false, "nextPageStart": 1, "values": [{"id": 1, "name": "bob", "active": true, "type": "NORMAL"}]}`)
+			case "1":
+				fmt.Fprint(w, `{"isLastPage": true, "values": [{"id": 2, "name": "bot", "type": "SERVICE"}]}`)
+			}
+		case "/rest/api/1.0/users/bob":
+			fmt.Fprint(w, `{"id": 1, "name": "bob", "slug": "bob", "active": true, "type": "NORMAL",
+				"links": {"self": [{"href": "http://stash/users/bob"}]}}`)
+		default:
+			t.Fatalf("Unexpected path %s\n", r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	users, err := stashClient.GetUsers("bo")
+	if err != nil || len(users) != 2 || users[1].Type != "SERVICE" {
+		t.Fatalf("Want users from both pages but got %+v, %v\n", users, err)
+	}
+
+	user, err := stashClient.GetUser("bob")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if user.ID != 1 || !user.Active || user.Links == nil ||
+		user.Links.self() != "http://stash/users/bob" {
+		t.Fatalf("Want full user model but got %+v\n", user)
+	}
+}
@@ -0,0 +1,49 @@
+package stash
+
+import "strings"
+
+// ValidationError is returned instead of sending request which is known to
+// be invalid, e.g. with empty project key, which server would reject with
+// confusing 404 response.
+type ValidationError struct {
+	Reason string
+}
+
+func (err ValidationError) Error() string {
+	return "invalid request: " + err.Reason
+}
+
+// validateRequest checks that client is configured and that path has no
+// empty parameters, e.g. project key or repository slug, see pathf.
+func (client Client) validateRequest(path string) error {
+	if client.baseURL == nil {
+		return ValidationError{Reason: "base URL is not set"}
+	}
+
+	if strings.Contains(path, emptySegment) {
+		return ValidationError{
+			Reason: "empty parameter in " +
+				strings.ReplaceAll(path, emptySegment, "<empty>"),
+		}
+	}
+
+	return nil
+}
+
+// validatePullRequestState checks that state can be used as a filter,
+// empty state is valid and means server default.
+func validatePullRequestState(state PullRequestState) error {
+	switch state {
+	case "",
+		PullRequestStateOpen,
+		PullRequestStateMerged,
+		PullRequestStateDeclined,
+		PullRequestStateAll:
+		return nil
+	}
+
+	return ValidationError{
+		Reason: "unknown pull request state " + string(state) +
+			", expected OPEN, MERGED, DECLINED or ALL",
+	}
+}
@@ -0,0 +1,36 @@
+package stash
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("Not expecting request %s %s\n", r.Method, r.URL.Path)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	var validation ValidationError
+
+	_, err := stashClient.GetRepository("PRJ", "")
+	if !errors.As(err, &validation) {
+		t.Fatalf("Want ValidationError for empty slug but got %v\n", err)
+	}
+
+	_, err = stashClient.GetPullRequests("PRJ", "widge", "OPENED")
+	if !errors.As(err, &validation) {
+		t.Fatalf("Want ValidationError for unknown state but got %v\n", err)
+	}
+
+	_, err = NewClient("u", "p", nil).GetRepository("PRJ", "widge")
+	if !errors.As(err, &validation) {
+		t.Fatalf("Want ValidationError for nil base URL but got %v\n", err)
+	}
+}
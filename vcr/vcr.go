@@ -0,0 +1,354 @@
+// Package vcr records interactions with a real Stash server into fixture
+// files and replays them later, so tests can be written against realistic
+// responses without a live server.
+//
+// Both Recorder and Replayer are http.RoundTripper and are meant to be used
+// as transport of the client:
+//
+//	recorder := vcr.NewRecordingTransport(nil)
+//	client := stash.NewClient(user, password, stashURL,
+//		stash.WithHTTPClient(&http.Client{Transport: recorder}))
+//	... run client ...
+//	err := recorder.Save("testdata/get-repository.json")
+//
+//	replayer, err := vcr.Load("testdata/get-repository.json")
+//	client := stash.NewClient(user, password, stashURL,
+//		stash.WithHTTPClient(&http.Client{Transport: replayer}))
+//
+// They are also http.Handler, so they can be served by httptest.Server when
+// the client can't be configured.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/reconquest/stash-go/internal/redact"
+)
+
+type (
+	// Cassette is a list of recorded interactions as stored in fixture
+	// files.
+	Cassette struct {
+		Interactions []Interaction `json:"interactions"`
+	}
+
+	Interaction struct {
+		Request  Request  `json:"request"`
+		Response Response `json:"response"`
+	}
+
+	// Request is a recorded request. Headers are not recorded and values
+	// of sensitive query parameters and JSON fields are redacted, so
+	// credentials never end up in fixtures.
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body,omitempty"`
+	}
+
+	Response struct {
+		Status  int               `json:"status"`
+		Headers map[string]string `json:"headers,omitempty"`
+		Body    string            `json:"body"`
+	}
+
+	// Recorder records requests sent to the real server, either as
+	// http.RoundTripper or as http.Handler proxying to target.
+	Recorder struct {
+		target    *url.URL
+		transport http.RoundTripper
+
+		mutex    sync.Mutex
+		cassette Cassette
+	}
+
+	// Replayer serves recorded responses. Requests are matched by method,
+	// URL and body; identical requests are answered in the order they were
+	// recorded.
+	Replayer struct {
+		mutex  sync.Mutex
+		played []bool
+
+		cassette Cassette
+	}
+)
+
+// recordedHeaders are response headers worth keeping in fixtures. UPM token
+// is not among them, since it's a credential.
+var recordedHeaders = []string{"Content-Type", "Retry-After"}
+
+// NewRecorder returns Recorder which forwards requests to target when served
+// as http.Handler.
+func NewRecorder(target *url.URL) *Recorder {
+	return &Recorder{
+		target:    target,
+		transport: http.DefaultTransport,
+	}
+}
+
+// NewRecordingTransport returns Recorder which sends requests through
+// transport, http.DefaultTransport is used if it's nil.
+func NewRecordingTransport(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &Recorder{transport: transport}
+}
+
+// RoundTrip sends request through underlying transport and records it.
+func (recorder *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := readBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := recorder.transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction := Interaction{
+		Request: newRequest(request, body),
+		Response: Response{
+			Status:  response.StatusCode,
+			Headers: map[string]string{},
+			Body:    string(data),
+		},
+	}
+
+	for _, header := range recordedHeaders {
+		if value := response.Header.Get(header); value != "" {
+			interaction.Response.Headers[header] = value
+		}
+	}
+
+	recorder.mutex.Lock()
+	recorder.cassette.Interactions = append(
+		recorder.cassette.Interactions,
+		interaction,
+	)
+	recorder.mutex.Unlock()
+
+	response.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	return response, nil
+}
+
+func (recorder *Recorder) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	forward, err := http.NewRequest(
+		request.Method,
+		strings.TrimRight(recorder.target.String(), "/")+request.URL.RequestURI(),
+		request.Body,
+	)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	forward.Header = request.Header.Clone()
+
+	response, err := recorder.RoundTrip(forward)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	defer response.Body.Close()
+
+	for header, values := range response.Header {
+		writer.Header()[header] = values
+	}
+
+	writer.WriteHeader(response.StatusCode)
+	io.Copy(writer, response.Body)
+}
+
+// Cassette returns interactions recorded so far.
+func (recorder *Recorder) Cassette() Cassette {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return Cassette{
+		Interactions: append(
+			[]Interaction(nil),
+			recorder.cassette.Interactions...,
+		),
+	}
+}
+
+// Save writes recorded interactions into fixture file.
+func (recorder *Recorder) Save(path string) error {
+	data, err := json.MarshalIndent(recorder.Cassette(), "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// Load reads fixture file written by Recorder.Save.
+func Load(path string) (*Replayer, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	err = json.Unmarshal(data, &cassette)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode cassette %s: %s", path, err)
+	}
+
+	return NewReplayer(cassette), nil
+}
+
+func NewReplayer(cassette Cassette) *Replayer {
+	return &Replayer{
+		cassette: cassette,
+		played:   make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// RoundTrip answers request with recorded response.
+func (replayer *Replayer) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := readBody(request)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded, ok := replayer.play(newRequest(request, body))
+	if !ok {
+		return nil, fmt.Errorf(
+			"vcr: no recorded interaction for %s %s",
+			request.Method, redact.URL(request.URL.RequestURI()),
+		)
+	}
+
+	response := &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       request,
+	}
+
+	for header, value := range recorded.Headers {
+		response.Header.Set(header, value)
+	}
+
+	return response, nil
+}
+
+func (replayer *Replayer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	body, err := readBody(request)
+	if err != nil {
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	recorded, ok := replayer.play(newRequest(request, body))
+	if !ok {
+		http.Error(
+			writer,
+			fmt.Sprintf(
+				"vcr: no recorded interaction for %s %s",
+				request.Method, redact.URL(request.URL.RequestURI()),
+			),
+			http.StatusNotImplemented,
+		)
+		return
+	}
+
+	recorded.write(writer)
+}
+
+// play finds first not yet played interaction matching request.
+func (replayer *Replayer) play(request Request) (Response, bool) {
+	replayer.mutex.Lock()
+	defer replayer.mutex.Unlock()
+
+	for i, interaction := range replayer.cassette.Interactions {
+		if replayer.played[i] || interaction.Request != request {
+			continue
+		}
+
+		replayer.played[i] = true
+
+		return interaction.Response, true
+	}
+
+	return Response{}, false
+}
+
+// Unplayed returns recorded requests which were not replayed yet, which is
+// useful to ensure that test made all expected calls.
+func (replayer *Replayer) Unplayed() []Request {
+	replayer.mutex.Lock()
+	defer replayer.mutex.Unlock()
+
+	var requests []Request
+	for i, interaction := range replayer.cassette.Interactions {
+		if !replayer.played[i] {
+			requests = append(requests, interaction.Request)
+		}
+	}
+
+	return requests
+}
+
+func (response Response) write(writer http.ResponseWriter) {
+	for header, value := range response.Headers {
+		writer.Header().Set(header, value)
+	}
+
+	writer.WriteHeader(response.Status)
+	writer.Write([]byte(response.Body))
+}
+
+// readBody reads request body and puts it back, so request can be sent
+// further.
+func readBody(request *http.Request) ([]byte, error) {
+	if request.Body == nil {
+		return nil, nil
+	}
+
+	body, err := ioutil.ReadAll(request.Body)
+	request.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+// newRequest returns request as it's stored in fixtures, with values of
+// sensitive query parameters and JSON fields redacted.
+func newRequest(request *http.Request, body []byte) Request {
+	return Request{
+		Method: request.Method,
+		URL:    redact.URL(request.URL.RequestURI()),
+		Body:   redact.Body(body),
+	}
+}
@@ -0,0 +1,147 @@
+package vcr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/reconquest/stash-go"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Basic dTpw" {
+			t.Fatalf("Want Basic dTpw but found %s\n", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"id": 1, "slug": "widge", "project": {"key": "PRJ"}}`)
+	}))
+	defer upstream.Close()
+
+	target, _ := url.Parse(upstream.URL)
+	recorder := NewRecorder(target)
+	recording := httptest.NewServer(recorder)
+
+	recordingURL, _ := url.Parse(recording.URL)
+	repository, err := stash.NewClient("u", "p", recordingURL).
+		GetRepository("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if repository.Slug != "widge" {
+		t.Fatalf("Want widge but got %s\n", repository.Slug)
+	}
+
+	recording.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	upstream.Close()
+
+	replayer, err := Load(path)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	replaying := httptest.NewServer(replayer)
+	defer replaying.Close()
+
+	replayingURL, _ := url.Parse(replaying.URL)
+	client := stash.NewClient("u", "p", replayingURL)
+
+	repository, err = client.GetRepository("PRJ", "widge")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if repository.Project.Key != "PRJ" {
+		t.Fatalf("Want PRJ but got %s\n", repository.Project.Key)
+	}
+	if len(replayer.Unplayed()) != 0 {
+		t.Fatalf("Want all interactions played but got %v\n", replayer.Unplayed())
+	}
+
+	if _, err := client.GetRepository("PRJ", "widge"); err == nil {
+		t.Fatalf("Want error for request which was not recorded\n")
+	}
+}
+
+func TestRecordAndReplayTransport(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("password") != "s3cret" {
+			t.Fatalf("Want s3cret but found %s\n", r.URL.Query().Get("password"))
+		}
+		w.Header().Set("Upm-Token", "upm-s3cret")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer upstream.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	recorder := NewRecordingTransport(nil)
+
+	_, err := stash.NewClient(
+		"u", "p", upstreamURL,
+		stash.WithHTTPClient(&http.Client{Transport: recorder}),
+	).CreateUser("bob", "s3cret", "Bob", "bob@example.com")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	upstream.Close()
+
+	cassette := recorder.Cassette()
+	if len(cassette.Interactions) != 1 {
+		t.Fatalf("Want 1 interaction but got %d\n", len(cassette.Interactions))
+	}
+
+	interaction := cassette.Interactions[0]
+	if strings.Contains(interaction.Request.URL, "s3cret") ||
+		!strings.Contains(interaction.Request.URL, "password=REDACTED") {
+		t.Fatalf("Want password redacted but got %s\n", interaction.Request.URL)
+	}
+	if _, ok := interaction.Response.Headers["Upm-Token"]; ok {
+		t.Fatalf("Want Upm-Token not recorded\n")
+	}
+
+	replayer := NewReplayer(cassette)
+	_, err = stash.NewClient(
+		"u", "p", upstreamURL,
+		stash.WithHTTPClient(&http.Client{Transport: replayer}),
+	).CreateUser("bob", "other", "Bob", "bob@example.com")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if len(replayer.Unplayed()) != 0 {
+		t.Fatalf("Want all interactions played but got %v\n", replayer.Unplayed())
+	}
+}
+
+func TestRecorderRedactsBody(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer upstream.Close()
+
+	recorder := NewRecordingTransport(nil)
+	request, _ := http.NewRequest(
+		"PUT", upstream.URL,
+		strings.NewReader(`{"rawLicense": "AAAB-license", "name": "x"}`),
+	)
+
+	response, err := recorder.RoundTrip(request)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	response.Body.Close()
+
+	body := recorder.Cassette().Interactions[0].Request.Body
+	if body != `{"rawLicense": "REDACTED", "name": "x"}` {
+		t.Fatalf("Want license redacted but got %s\n", body)
+	}
+}
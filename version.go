@@ -0,0 +1,175 @@
+package stash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type (
+	ApplicationProperties struct {
+		Version     string `json:"version"`
+		BuildNumber string `json:"buildNumber"`
+		BuildDate   string `json:"buildDate"`
+		DisplayName string `json:"displayName"`
+	}
+
+	// ServerVersion is a parsed version of the Bitbucket Server instance.
+	ServerVersion struct {
+		Major int
+		Minor int
+		Patch int
+	}
+
+	// UnsupportedServerVersionError is returned when called API is not
+	// available on the server version, instead of the opaque 404 the
+	// server would reply with.
+	UnsupportedServerVersionError struct {
+		Feature  string
+		Required ServerVersion
+		Actual   ServerVersion
+	}
+
+	versionCache struct {
+		sync.Mutex
+		detected bool
+		version  ServerVersion
+	}
+)
+
+// ErrUnsupportedServerVersion matches any UnsupportedServerVersionError
+// with errors.Is.
+var ErrUnsupportedServerVersion = errors.New("unsupported server version")
+
+func (err UnsupportedServerVersionError) Error() string {
+	return fmt.Sprintf(
+		"%s require server version %s or newer, but server is %s",
+		err.Feature, err.Required, err.Actual,
+	)
+}
+
+func (err UnsupportedServerVersionError) Is(target error) bool {
+	return target == ErrUnsupportedServerVersion
+}
+
+func (version ServerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch)
+}
+
+// AtLeast reports whether version is the same or newer than given one.
+func (version ServerVersion) AtLeast(major, minor int) bool {
+	if version.Major != major {
+		return version.Major > major
+	}
+
+	return version.Minor >= minor
+}
+
+// ParseServerVersion parses version strings like "7.21.0" or "8.9.0-rc1".
+func ParseServerVersion(raw string) (ServerVersion, error) {
+	parts := strings.SplitN(raw, ".", 3)
+	if len(parts) < 2 {
+		return ServerVersion{}, fmt.Errorf("invalid server version: %q", raw)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		// strip suffixes like -rc1 or -SNAPSHOT
+		if index := strings.IndexFunc(part, func(r rune) bool {
+			return r < '0' || r > '9'
+		}); index >= 0 {
+			part = part[:index]
+		}
+
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return ServerVersion{}, fmt.Errorf("invalid server version: %q", raw)
+		}
+
+		numbers[i] = number
+	}
+
+	return ServerVersion{
+		Major: numbers[0],
+		Minor: numbers[1],
+		Patch: numbers[2],
+	}, nil
+}
+
+// GetApplicationProperties returns version and build information of the
+// server.
+func (client Client) GetApplicationProperties() (ApplicationProperties, error) {
+	data, err := client.request(
+		"GET", "/rest/api/1.0/application-properties",
+		nil,
+		http.StatusOK,
+	)
+	if err != nil {
+		return ApplicationProperties{}, err
+	}
+
+	var response ApplicationProperties
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return ApplicationProperties{}, err
+	}
+
+	return response, nil
+}
+
+// GetServerVersion returns the server version. Version is detected only once
+// per client created by NewClient.
+func (client Client) GetServerVersion() (ServerVersion, error) {
+	if client.version == nil {
+		return client.detectServerVersion()
+	}
+
+	client.version.Lock()
+	defer client.version.Unlock()
+
+	if client.version.detected {
+		return client.version.version, nil
+	}
+
+	version, err := client.detectServerVersion()
+	if err != nil {
+		return ServerVersion{}, err
+	}
+
+	client.version.version = version
+	client.version.detected = true
+
+	return version, nil
+}
+
+func (client Client) detectServerVersion() (ServerVersion, error) {
+	properties, err := client.GetApplicationProperties()
+	if err != nil {
+		return ServerVersion{}, err
+	}
+
+	return ParseServerVersion(properties.Version)
+}
+
+// requireVersion returns UnsupportedServerVersionError if server is older
+// than given version.
+func (client Client) requireVersion(feature string, major, minor int) error {
+	version, err := client.GetServerVersion()
+	if err != nil {
+		return err
+	}
+
+	if !version.AtLeast(major, minor) {
+		return UnsupportedServerVersionError{
+			Feature:  feature,
+			Required: ServerVersion{Major: major, Minor: minor},
+			Actual:   version,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package stash
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestParseServerVersion(t *testing.T) {
+	version, err := ParseServerVersion("8.9.0-rc1")
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+	if version != (ServerVersion{Major: 8, Minor: 9, Patch: 0}) {
+		t.Fatalf("Want 8.9.0 but got %s\n", version)
+	}
+	if !version.AtLeast(7, 21) || !version.AtLeast(8, 9) || version.AtLeast(8, 10) {
+		t.Fatalf("Unexpected comparison result for %s\n", version)
+	}
+
+	if _, err := ParseServerVersion("unknown"); err == nil {
+		t.Fatalf("Want error but got none\n")
+	}
+}
+
+func TestGetServerVersionDetectedOnce(t *testing.T) {
+	requests := 0
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		url := *r.URL
+		if url.Path != "/rest/api/1.0/application-properties" {
+			t.Fatalf("Want /rest/api/1.0/application-properties but found %s\n", url.Path)
+		}
+		requests++
+		fmt.Fprint(w, `{"version": "7.21.4", "buildNumber": "7021004", "displayName": "Bitbucket"}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	for i := 0; i < 2; i++ {
+		version, err := stashClient.GetServerVersion()
+		if err != nil {
+			t.Fatalf("Not expecting error: %v\n", err)
+		}
+		if version.String() != "7.21.4" {
+			t.Fatalf("Want 7.21.4 but got %s\n", version)
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("Want 1 request but got %d\n", requests)
+	}
+}
+
+func TestUnsupportedServerVersion(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/application-properties" {
+			t.Fatalf("Not expecting request to %s\n", r.URL.Path)
+		}
+		fmt.Fprint(w, `{"version": "7.1.4"}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+	_, err := stashClient.CreatePullRequestWithOptions(CreatePullRequestOptions{
+		Title: "a title",
+		Draft: true,
+	})
+	if !errors.Is(err, ErrUnsupportedServerVersion) {
+		t.Fatalf("Want ErrUnsupportedServerVersion but got %v\n", err)
+	}
+
+	calls := map[string]func() error{
+		"default tasks": func() error {
+			_, err := stashClient.GetProjectDefaultTasks("PRJ")
+			return err
+		},
+		"instance default branch": func() error {
+			return stashClient.SetInstanceDefaultBranch("main")
+		},
+		"access tokens": func() error {
+			return stashClient.RevokeProjectAccessToken("PRJ", "1")
+		},
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrUnsupportedServerVersion) {
+			t.Fatalf("Want ErrUnsupportedServerVersion for %s but got %v\n", name, err)
+		}
+	}
+}
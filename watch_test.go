@@ -0,0 +1,37 @@
+package stash
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestWatchRepository(t *testing.T) {
+	var methods []string
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PRJ/repos/widge/watch" {
+			t.Fatalf("WatchRepository() URL path expected to be /rest/api/1.0/projects/PRJ/repos/widge/watch but found %s\n", r.URL.Path)
+		}
+		if r.Header.Get("Authorization") != "Basic dTpw" {
+			t.Fatalf("Want  Basic dTpw but found %s\n", r.Header.Get("Authorization"))
+		}
+		methods = append(methods, r.Method)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	repo := NewClient("u", "p", url).Project("PRJ").Repo("widge")
+
+	if err := repo.Watch(); err != nil {
+		t.Fatalf("Watch() not expecting an error, but received: %v\n", err)
+	}
+	if err := repo.Unwatch(); err != nil {
+		t.Fatalf("Unwatch() not expecting an error, but received: %v\n", err)
+	}
+
+	if len(methods) != 2 || methods[0] != "POST" || methods[1] != "DELETE" {
+		t.Fatalf("Want POST and DELETE but got %v\n", methods)
+	}
+}
@@ -0,0 +1,560 @@
+package stash
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+type (
+	Webhook struct {
+		ID     int            `json:"id,omitempty"`
+		Name   string         `json:"name"`
+		URL    string         `json:"url"`
+		Events []WebhookEvent `json:"events"`
+		Active bool           `json:"active"`
+
+		// ScopeType tells whether webhook is defined on project or
+		// repository, it's set by the server.
+		ScopeType WebhookScope `json:"scopeType,omitempty"`
+
+		// Configuration holds settings like secret, see SetSecret.
+		Configuration map[string]string `json:"configuration,omitempty"`
+	}
+
+	// WebhookFilter narrows webhooks returned by ListWebhooks, zero fields
+	// match any webhook.
+	WebhookFilter struct {
+		// Event matches webhooks subscribed to the event.
+		Event WebhookEvent
+
+		// ScopeType matches webhooks defined on the given level, e.g. to
+		// get webhooks inherited from the project.
+		ScopeType WebhookScope
+	}
+
+	Webhooks = Paged[Webhook]
+)
+
+// webhookSecret is a configuration key of the secret used to sign
+// payloads with HMAC.
+const webhookSecret = "secret"
+
+// GetWebhooks returns all webhooks of the given repository.
+func (client Client) GetWebhooks(
+	projectKey, repositorySlug string,
+) ([]Webhook, error) {
+	return client.ListWebhooks(projectKey, repositorySlug, WebhookFilter{})
+}
+
+// ListWebhooks returns webhooks of the given repository which match the
+// filter.
+func (client Client) ListWebhooks(
+	projectKey, repositorySlug string,
+	filter WebhookFilter,
+) ([]Webhook, error) {
+	return client.listWebhooks(
+		repositoryWebhooks(projectKey, repositorySlug), filter,
+	)
+}
+
+// ListProjectWebhooks returns webhooks defined on the project which match
+// the filter, they are triggered by events in every repository of the
+// project. Requires Bitbucket 7.x or newer.
+func (client Client) ListProjectWebhooks(
+	projectKey string,
+	filter WebhookFilter,
+) ([]Webhook, error) {
+	err := client.requireProjectWebhooks()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.listWebhooks(projectWebhooks(projectKey), filter)
+}
+
+func (client Client) listWebhooks(
+	path string,
+	filter WebhookFilter,
+) ([]Webhook, error) {
+	query := url.Values{}
+	if filter.Event != "" {
+		query.Set("event", string(filter.Event))
+	}
+
+	if filter.ScopeType != "" {
+		query.Set("scopeType", string(filter.ScopeType))
+	}
+
+	return collectPages(
+		client,
+		func(start, limit int) (Paged[Webhook], error) {
+			// pages may be fetched concurrently, see WithPagePrefetch
+			query := maps.Clone(query)
+			query.Set("start", strconv.Itoa(start))
+			query.Set("limit", strconv.Itoa(limit))
+
+			var response Webhooks
+			err := client.requestJSON(
+				"GET",
+				path+"?"+query.Encode(),
+				nil,
+				&response,
+				http.StatusOK,
+			)
+			return response, err
+		},
+	)
+}
+
+func repositoryWebhooks(projectKey, repositorySlug string) string {
+	return pathf(
+		"/rest/api/1.0/projects/%s/repos/%s/webhooks",
+		projectKey, repositorySlug,
+	)
+}
+
+func projectWebhooks(projectKey string) string {
+	return pathf("/rest/api/1.0/projects/%s/webhooks", projectKey)
+}
+
+// Secret returns secret used to sign payloads, server may omit it in
+// responses.
+func (webhook Webhook) Secret() string {
+	return webhook.Configuration[webhookSecret]
+}
+
+// SetSecret sets secret used to sign payloads with HMAC, empty secret
+// disables signing.
+func (webhook *Webhook) SetSecret(secret string) {
+	// configuration is copied, since webhooks are passed by value and may
+	// share it
+	configuration := map[string]string{}
+	for key, value := range webhook.Configuration {
+		configuration[key] = value
+	}
+
+	if secret == "" {
+		delete(configuration, webhookSecret)
+	} else {
+		configuration[webhookSecret] = secret
+	}
+
+	webhook.Configuration = configuration
+}
+
+// Subscribed reports whether webhook is triggered by the event.
+func (webhook Webhook) Subscribed(event WebhookEvent) bool {
+	return slices.Contains(webhook.Events, event)
+}
+
+// CreateWebhook creates webhook in the given repository, ID of the
+// webhook is ignored.
+func (client Client) CreateWebhook(
+	projectKey, repositorySlug string,
+	webhook Webhook,
+) (Webhook, error) {
+	webhook.ID = 0
+
+	return client.saveWebhook(
+		"POST", repositoryWebhooks(projectKey, repositorySlug), webhook,
+		http.StatusCreated,
+	)
+}
+
+// UpdateWebhook replaces webhook with the given ID.
+func (client Client) UpdateWebhook(
+	projectKey, repositorySlug string,
+	webhook Webhook,
+) (Webhook, error) {
+	return client.saveWebhook(
+		"PUT",
+		fmt.Sprintf(
+			"%s/%d", repositoryWebhooks(projectKey, repositorySlug), webhook.ID,
+		),
+		webhook,
+		http.StatusOK,
+	)
+}
+
+func (client Client) DeleteWebhook(
+	projectKey, repositorySlug string,
+	id int,
+) error {
+	return client.deleteWebhook(
+		fmt.Sprintf("%s/%d", repositoryWebhooks(projectKey, repositorySlug), id),
+	)
+}
+
+// CreateProjectWebhook creates webhook on the project, ID of the webhook is
+// ignored. Requires Bitbucket 7.x or newer.
+func (client Client) CreateProjectWebhook(
+	projectKey string,
+	webhook Webhook,
+) (Webhook, error) {
+	err := client.requireProjectWebhooks()
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	webhook.ID = 0
+
+	return client.saveWebhook(
+		"POST", projectWebhooks(projectKey), webhook,
+		http.StatusCreated,
+	)
+}
+
+// UpdateProjectWebhook replaces project webhook with the given ID.
+func (client Client) UpdateProjectWebhook(
+	projectKey string,
+	webhook Webhook,
+) (Webhook, error) {
+	err := client.requireProjectWebhooks()
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	return client.saveWebhook(
+		"PUT",
+		fmt.Sprintf("%s/%d", projectWebhooks(projectKey), webhook.ID),
+		webhook,
+		http.StatusOK,
+	)
+}
+
+func (client Client) DeleteProjectWebhook(projectKey string, id int) error {
+	err := client.requireProjectWebhooks()
+	if err != nil {
+		return err
+	}
+
+	return client.deleteWebhook(
+		fmt.Sprintf("%s/%d", projectWebhooks(projectKey), id),
+	)
+}
+
+// WebhookTest is a result of sending test payload to webhook URL.
+type WebhookTest struct {
+	Request struct {
+		URL     string            `json:"url"`
+		Method  string            `json:"method"`
+		Headers map[string]string `json:"headers"`
+		Body    string            `json:"body"`
+	} `json:"request"`
+
+	// Response is nil if receiver couldn't be reached.
+	Response *struct {
+		StatusCode int               `json:"statusCode"`
+		Headers    map[string]string `json:"headers"`
+		Body       string            `json:"body"`
+	} `json:"response"`
+}
+
+// TestWebhook sends test payload to URL of the repository webhook, so
+// connectivity is checked before events are delivered. Secret of the
+// webhook with the given ID is used to sign payload, ID may be zero for
+// webhooks which are not created yet.
+func (client Client) TestWebhook(
+	projectKey, repositorySlug string,
+	webhook Webhook,
+) (WebhookTest, error) {
+	return client.testWebhook(
+		repositoryWebhooks(projectKey, repositorySlug), webhook,
+	)
+}
+
+// TestProjectWebhook sends test payload to URL of the project webhook, see
+// TestWebhook.
+func (client Client) TestProjectWebhook(
+	projectKey string,
+	webhook Webhook,
+) (WebhookTest, error) {
+	err := client.requireProjectWebhooks()
+	if err != nil {
+		return WebhookTest{}, err
+	}
+
+	return client.testWebhook(projectWebhooks(projectKey), webhook)
+}
+
+func (client Client) requireProjectWebhooks() error {
+	return client.requireVersion("project webhooks", 7, 0)
+}
+
+func (client Client) testWebhook(
+	path string,
+	webhook Webhook,
+) (WebhookTest, error) {
+	query := url.Values{}
+	query.Set("url", webhook.URL)
+	if webhook.ID != 0 {
+		query.Set("webhookId", strconv.Itoa(webhook.ID))
+	}
+
+	var response WebhookTest
+	err := client.requestJSON(
+		"POST", path+"/test?"+query.Encode(),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return WebhookTest{}, err
+	}
+
+	return response, nil
+}
+
+// Succeeded reports whether receiver replied with 2xx status.
+func (test WebhookTest) Succeeded() bool {
+	return test.Response != nil &&
+		test.Response.StatusCode >= 200 && test.Response.StatusCode < 300
+}
+
+func (client Client) saveWebhook(
+	method, path string,
+	webhook Webhook,
+	status int,
+) (Webhook, error) {
+	var response Webhook
+	err := client.requestJSON(
+		method, path,
+		webhook,
+		&response,
+		status,
+	)
+	if err != nil {
+		return Webhook{}, err
+	}
+
+	return response, nil
+}
+
+func (client Client) deleteWebhook(path string) error {
+	_, err := client.request("DELETE", path, nil, http.StatusNoContent)
+
+	return err
+}
+
+type (
+	RotateWebhookSecretsOptions struct {
+		// Concurrency limits amount of repositories updated at the same
+		// time, default is 1.
+		Concurrency int
+	}
+
+	// WebhookRotation is a result of setting new secret on a single
+	// repository webhook.
+	WebhookRotation struct {
+		Repository RepositoryRef
+		Webhook    Webhook
+		Err        error
+	}
+)
+
+// RotateWebhookSecrets sets the secret on every webhook of every repository
+// of the project, so receivers can switch to the new secret at once.
+// Webhooks inherited from the project are not changed. Repositories which
+// webhooks can't be listed are reported with zero Webhook.
+func (client Client) RotateWebhookSecrets(
+	projectKey, secret string,
+	options RotateWebhookSecretsOptions,
+) ([]WebhookRotation, error) {
+	repositories, err := client.ListProjectRepositories(projectKey)
+	if err != nil {
+		return nil, err
+	}
+
+	rotations := make([][]WebhookRotation, len(repositories))
+	forEachConcurrently(len(repositories), options.Concurrency, func(index int) {
+		repository := RepositoryRef{
+			ProjectKey: projectKey,
+			Slug:       repositories[index].Slug,
+		}
+
+		webhooks, err := client.ListWebhooks(
+			repository.ProjectKey, repository.Slug,
+			WebhookFilter{ScopeType: WebhookScopeRepository},
+		)
+		if err != nil {
+			rotations[index] = []WebhookRotation{{
+				Repository: repository,
+				Err:        err,
+			}}
+			return
+		}
+
+		for _, webhook := range webhooks {
+			webhook.SetSecret(secret)
+
+			updated, err := client.UpdateWebhook(
+				repository.ProjectKey, repository.Slug, webhook,
+			)
+			if err == nil {
+				webhook = updated
+			}
+
+			rotations[index] = append(rotations[index], WebhookRotation{
+				Repository: repository,
+				Webhook:    webhook,
+				Err:        err,
+			})
+		}
+	})
+
+	var results []WebhookRotation
+	for _, rotation := range rotations {
+		results = append(results, rotation...)
+	}
+
+	return results, nil
+}
+
+type (
+	// WebhookInvocation is a single delivery of webhook.
+	WebhookInvocation struct {
+		ID    int          `json:"id"`
+		Event WebhookEvent `json:"event"`
+
+		// Duration of the delivery, as well as Start and Finish, is in
+		// milliseconds.
+		Duration int64 `json:"duration"`
+		Start    int64 `json:"start"`
+		Finish   int64 `json:"finish"`
+
+		Request struct {
+			URL    string `json:"url"`
+			Method string `json:"method"`
+		} `json:"request"`
+
+		Result struct {
+			// Description is an excerpt of the receiver response or error.
+			Description string         `json:"description"`
+			Outcome     WebhookOutcome `json:"outcome"`
+		} `json:"result"`
+	}
+
+	// WebhookStatistics summarizes recent deliveries of webhook.
+	WebhookStatistics struct {
+		LastSuccess *WebhookInvocation `json:"lastSuccess"`
+		LastFailure *WebhookInvocation `json:"lastFailure"`
+		LastError   *WebhookInvocation `json:"lastError"`
+
+		Counts struct {
+			Successes int `json:"successes"`
+			Failures  int `json:"failures"`
+			Errors    int `json:"errors"`
+
+			// Window is a period counts are collected for, in milliseconds.
+			Window struct {
+				Start    int64 `json:"start"`
+				Duration int64 `json:"duration"`
+			} `json:"window"`
+		} `json:"counts"`
+	}
+
+	// WebhookInvocationFilter narrows invocation returned by
+	// GetLatestWebhookInvocation, zero fields match any invocation.
+	WebhookInvocationFilter struct {
+		Event   WebhookEvent
+		Outcome WebhookOutcome
+	}
+)
+
+// GetWebhookStatistics returns delivery statistics of the webhook, event
+// limits them to a single event if it's not empty.
+func (client Client) GetWebhookStatistics(
+	projectKey, repositorySlug string,
+	id int,
+	event WebhookEvent,
+) (WebhookStatistics, error) {
+	query := url.Values{}
+	if event != "" {
+		query.Set("event", string(event))
+	}
+
+	var response WebhookStatistics
+	err := client.requestJSON(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/webhooks/%d/statistics?%s",
+			projectKey, repositorySlug, id, rawPath(query.Encode()),
+		),
+		nil,
+		&response,
+		http.StatusOK,
+	)
+	if err != nil {
+		return WebhookStatistics{}, err
+	}
+
+	return response, nil
+}
+
+// GetLatestWebhookInvocation returns the latest delivery of the webhook
+// which matches the filter, or nil if there was none.
+func (client Client) GetLatestWebhookInvocation(
+	projectKey, repositorySlug string,
+	id int,
+	filter WebhookInvocationFilter,
+) (*WebhookInvocation, error) {
+	query := url.Values{}
+	if filter.Event != "" {
+		query.Set("event", string(filter.Event))
+	}
+
+	if filter.Outcome != "" {
+		query.Set("outcome", string(filter.Outcome))
+	}
+
+	data, err := client.request(
+		"GET",
+		pathf(
+			"/rest/api/1.0/projects/%s/repos/%s/webhooks/%d/latest?%s",
+			projectKey, repositorySlug, id, rawPath(query.Encode()),
+		),
+		nil,
+		http.StatusOK,
+		http.StatusNoContent,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var response *WebhookInvocation
+	err = json.Unmarshal(data, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// Failing reports whether the latest delivery of webhook has failed.
+func (statistics WebhookStatistics) Failing() bool {
+	var lastSuccess int64
+	if statistics.LastSuccess != nil {
+		lastSuccess = statistics.LastSuccess.Finish
+	}
+
+	for _, invocation := range []*WebhookInvocation{
+		statistics.LastFailure,
+		statistics.LastError,
+	} {
+		if invocation != nil && invocation.Finish > lastSuccess {
+			return true
+		}
+	}
+
+	return false
+}
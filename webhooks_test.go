@@ -0,0 +1,272 @@
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestListWebhooks(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PRJ/repos/widge/webhooks" {
+			t.Fatalf("ListWebhooks() URL path expected to be /rest/api/1.0/projects/PRJ/repos/widge/webhooks but found %s\n", r.URL.Path)
+		}
+		params := r.URL.Query()
+		if params.Get("event") != "pr:merged" || params.Get("scopeType") != "project" {
+			t.Fatalf("Want event=pr:merged&scopeType=project but found %s\n", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"isLastPage": true, "values": [
+			{"id": 3, "name": "deploy", "url": "http://deploy/hook", "events": ["pr:merged", "repo:refs_changed"], "active": true, "scopeType": "project", "configuration": {"secret": "s3cr3t"}}
+		]}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	webhooks, err := stashClient.ListWebhooks("PRJ", "widge", WebhookFilter{
+		Event:     WebhookEventPullRequestMerged,
+		ScopeType: WebhookScopeProject,
+	})
+	if err != nil {
+		t.Fatalf("ListWebhooks() not expecting an error, but received: %v\n", err)
+	}
+
+	if len(webhooks) != 1 || webhooks[0].ScopeType != WebhookScopeProject {
+		t.Fatalf("Want single project webhook but got %+v\n", webhooks)
+	}
+	if !webhooks[0].Subscribed(WebhookEventRefsChanged) || webhooks[0].Subscribed(WebhookEventPullRequestOpened) {
+		t.Fatalf("Unexpected webhook events %v\n", webhooks[0].Events)
+	}
+	if webhooks[0].Secret() != "s3cr3t" {
+		t.Fatalf("Want secret s3cr3t but got %q\n", webhooks[0].Secret())
+	}
+}
+
+func TestProjectWebhooks(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/1.0/application-properties" {
+			fmt.Fprint(w, `{"version": "8.9.0"}`)
+			return
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /rest/api/1.0/projects/PRJ/webhooks":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"id": 3, "name": "ci", "scopeType": "project"}]}`)
+		case "POST /rest/api/1.0/projects/PRJ/webhooks":
+			var webhook Webhook
+			json.NewDecoder(r.Body).Decode(&webhook)
+			if webhook.ID != 0 || webhook.Secret() != "s3cr3t" || !webhook.Subscribed(WebhookEventRefsChanged) {
+				t.Fatalf("Unexpected webhook %+v\n", webhook)
+			}
+			webhook.ID = 4
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(webhook)
+		case "PUT /rest/api/1.0/projects/PRJ/webhooks/4":
+			fmt.Fprint(w, `{"id": 4, "name": "ci", "active": false}`)
+		case "DELETE /rest/api/1.0/projects/PRJ/webhooks/4":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	webhooks, err := stashClient.ListProjectWebhooks("PRJ", WebhookFilter{})
+	if err != nil || len(webhooks) != 1 || webhooks[0].ScopeType != WebhookScopeProject {
+		t.Fatalf("Want single project webhook but got %+v, %v\n", webhooks, err)
+	}
+
+	webhook := Webhook{
+		ID:     3,
+		Name:   "ci",
+		URL:    "http://ci/hook",
+		Events: []WebhookEvent{WebhookEventRefsChanged},
+		Active: true,
+	}
+	webhook.SetSecret("s3cr3t")
+
+	webhook, err = stashClient.CreateProjectWebhook("PRJ", webhook)
+	if err != nil || webhook.ID != 4 {
+		t.Fatalf("Want created webhook 4 but got %+v, %v\n", webhook, err)
+	}
+
+	webhook.Active = false
+	webhook, err = stashClient.UpdateProjectWebhook("PRJ", webhook)
+	if err != nil || webhook.Active {
+		t.Fatalf("Want deactivated webhook but got %+v, %v\n", webhook, err)
+	}
+
+	err = stashClient.DeleteProjectWebhook("PRJ", webhook.ID)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+}
+
+func TestWebhookSetSecret(t *testing.T) {
+	shared := map[string]string{"secret": "old", "createdBy": "bitbucket"}
+	webhook := Webhook{Configuration: shared}
+
+	webhook.SetSecret("new")
+	if webhook.Secret() != "new" || webhook.Configuration["createdBy"] != "bitbucket" {
+		t.Fatalf("Want secret replaced but got %v\n", webhook.Configuration)
+	}
+	if shared["secret"] != "old" {
+		t.Fatalf("Want shared configuration untouched but got %v\n", shared)
+	}
+
+	webhook.SetSecret("")
+	if _, ok := webhook.Configuration["secret"]; ok {
+		t.Fatalf("Want secret removed but got %v\n", webhook.Configuration)
+	}
+}
+
+func TestRotateWebhookSecrets(t *testing.T) {
+	var (
+		mutex   sync.Mutex
+		updated []string
+	)
+
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/api/1.0/projects/PRJ/repos":
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"slug": "one"}, {"slug": "two"}]}`)
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/webhooks"):
+			if r.URL.Query().Get("scopeType") != "repository" {
+				t.Fatalf("Want only repository webhooks requested but found %s\n", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{"isLastPage": true, "values": [{"id": 1, "name": "ci", "url": "http://ci/hook"}]}`)
+		case r.Method == "PUT":
+			var webhook Webhook
+			json.NewDecoder(r.Body).Decode(&webhook)
+			if webhook.Secret() != "rotated" {
+				t.Fatalf("Want rotated secret but got %+v\n", webhook)
+			}
+			mutex.Lock()
+			updated = append(updated, r.URL.Path)
+			mutex.Unlock()
+			json.NewEncoder(w).Encode(webhook)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	rotations, err := stashClient.RotateWebhookSecrets(
+		"PRJ", "rotated", RotateWebhookSecretsOptions{Concurrency: 2},
+	)
+	if err != nil {
+		t.Fatalf("RotateWebhookSecrets() not expecting an error, but received: %v\n", err)
+	}
+
+	if len(rotations) != 2 || rotations[0].Repository.Slug != "one" || rotations[1].Repository.Slug != "two" {
+		t.Fatalf("Want rotations for one and two but got %+v\n", rotations)
+	}
+	for _, rotation := range rotations {
+		if rotation.Err != nil {
+			t.Fatalf("Not expecting error: %v\n", rotation.Err)
+		}
+	}
+	if len(updated) != 2 {
+		t.Fatalf("Want 2 webhooks updated but got %v\n", updated)
+	}
+}
+
+func TestGetWebhookStatistics(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/1.0/projects/PRJ/repos/widge/webhooks/3/statistics":
+			if r.URL.Query().Get("event") != "repo:refs_changed" {
+				t.Fatalf("Want event=repo:refs_changed but found %s\n", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `{
+				"lastSuccess": {"id": 1, "finish": 100, "result": {"outcome": "SUCCESS"}},
+				"lastFailure": {"id": 2, "finish": 200, "result": {"outcome": "FAILURE", "description": "502 Bad Gateway"}},
+				"counts": {"successes": 10, "failures": 1, "errors": 0, "window": {"start": 50, "duration": 86400000}}
+			}`)
+		case "/rest/api/1.0/projects/PRJ/repos/widge/webhooks/3/latest":
+			if r.URL.Query().Get("outcome") != "FAILURE" {
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			fmt.Fprint(w, `{"id": 2, "event": "repo:refs_changed", "request": {"url": "http://deploy/hook", "method": "POST"}, "result": {"outcome": "FAILURE", "description": "502 Bad Gateway"}}`)
+		default:
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	statistics, err := stashClient.GetWebhookStatistics("PRJ", "widge", 3, WebhookEventRefsChanged)
+	if err != nil {
+		t.Fatalf("GetWebhookStatistics() not expecting an error, but received: %v\n", err)
+	}
+	if statistics.Counts.Successes != 10 || statistics.Counts.Failures != 1 {
+		t.Fatalf("Unexpected counts %+v\n", statistics.Counts)
+	}
+	if !statistics.Failing() {
+		t.Fatalf("Want webhook failing since last failure is newer than last success\n")
+	}
+
+	invocation, err := stashClient.GetLatestWebhookInvocation("PRJ", "widge", 3, WebhookInvocationFilter{
+		Outcome: WebhookOutcomeFailure,
+	})
+	if err != nil {
+		t.Fatalf("GetLatestWebhookInvocation() not expecting an error, but received: %v\n", err)
+	}
+	if invocation == nil || invocation.Result.Description != "502 Bad Gateway" || invocation.Request.Method != "POST" {
+		t.Fatalf("Unexpected invocation %+v\n", invocation)
+	}
+
+	invocation, err = stashClient.GetLatestWebhookInvocation("PRJ", "widge", 3, WebhookInvocationFilter{})
+	if err != nil {
+		t.Fatalf("GetLatestWebhookInvocation() not expecting an error, but received: %v\n", err)
+	}
+	if invocation != nil {
+		t.Fatalf("Want no invocation but got %+v\n", invocation)
+	}
+}
+
+func TestTestWebhook(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/rest/api/1.0/projects/PRJ/repos/widge/webhooks/test" {
+			t.Fatalf("Unexpected request %s %s\n", r.Method, r.URL.Path)
+		}
+
+		params := r.URL.Query()
+		if params.Get("url") != "http://deploy/hook" || params.Get("webhookId") != "3" {
+			t.Fatalf("Want test of webhook 3 but got %s\n", r.URL.RawQuery)
+		}
+
+		fmt.Fprint(w, `{
+			"request": {"url": "http://deploy/hook", "method": "POST", "headers": {"X-Event-Key": "diagnostics:ping"}},
+			"response": {"statusCode": 502, "body": "Bad Gateway"}
+		}`)
+	}))
+	defer testServer.Close()
+
+	url, _ := url.Parse(testServer.URL)
+	stashClient := NewClient("u", "p", url)
+
+	test, err := stashClient.TestWebhook("PRJ", "widge", Webhook{ID: 3, URL: "http://deploy/hook"})
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if test.Succeeded() || test.Response.StatusCode != 502 ||
+		test.Request.Headers["X-Event-Key"] != "diagnostics:ping" {
+		t.Fatalf("Want failed delivery with 502 but got %+v\n", test)
+	}
+}
@@ -0,0 +1,57 @@
+package stash
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRepositoryWebURL(t *testing.T) {
+	repository := Repository{
+		Links: Links{
+			Self: []Link{
+				{HREF: "http://localhost:7990/projects/PRJ/repos/widge/browse"},
+			},
+		},
+	}
+
+	if repository.WebURL() != "http://localhost:7990/projects/PRJ/repos/widge/browse" {
+		t.Fatalf("Unexpected repository URL %s\n", repository.WebURL())
+	}
+
+	branchURL := repository.BranchWebURL(Branch{ID: "refs/heads/feature/x"})
+	if branchURL != "http://localhost:7990/projects/PRJ/repos/widge/browse?at=refs%2Fheads%2Ffeature%2Fx" {
+		t.Fatalf("Unexpected branch URL %s\n", branchURL)
+	}
+
+	commitURL := repository.CommitWebURL(Commit{ID: "abcdef"})
+	if commitURL != "http://localhost:7990/projects/PRJ/repos/widge/commits/abcdef" {
+		t.Fatalf("Unexpected commit URL %s\n", commitURL)
+	}
+}
+
+func TestWebURLMissing(t *testing.T) {
+	if (Repository{}).CommitWebURL(Commit{ID: "abcdef"}) != "" {
+		t.Fatalf("Want no url for repository without links\n")
+	}
+	if (PullRequest{}).WebURL() != "" {
+		t.Fatalf("Want no url for pull request without links\n")
+	}
+}
+
+func TestProjectWebURL(t *testing.T) {
+	var repository Repository
+	err := json.Unmarshal([]byte(`{"slug": "widge", "project": {"key": "PRJ", "links": {"self": [{"href": "http://localhost:7990/projects/PRJ"}]}}}`), &repository)
+	if err != nil {
+		t.Fatalf("Not expecting error: %v\n", err)
+	}
+
+	if repository.Project.WebURL() != "http://localhost:7990/projects/PRJ" {
+		t.Fatalf("Unexpected project URL %s\n", repository.Project.WebURL())
+	}
+
+	// project is used as map key by callers
+	projects := map[Project]bool{repository.Project: true}
+	if !projects[repository.Project] {
+		t.Fatalf("Want project found by itself\n")
+	}
+}